@@ -22,6 +22,7 @@ type ServerFactory struct {
 	Config     *internal.Config
 	Logger     logger.Logger
 	Service    service.ProductService
+	Sweeper    *service.ReservationSweeper
 	GRPRServer *grpc.Server
 }
 
@@ -46,6 +47,12 @@ func NewServerFactory() (*ServerFactory, error) {
 		return nil, err
 	}
 
+	sweeper := service.NewReservationSweeper(&service.SweeperSetting{
+		ProductRepo: productRepo,
+		Interval:    config.SweepInterval,
+		Logger:      zapLogger,
+	})
+
 	grpcServer, err := handler.New(&handler.Setting{
 		Config:  config,
 		Service: productService,
@@ -61,6 +68,7 @@ func NewServerFactory() (*ServerFactory, error) {
 		Config:     config,
 		Logger:     zapLogger,
 		Service:    productService,
+		Sweeper:    sweeper,
 		GRPRServer: grpcServer,
 	}, nil
 }
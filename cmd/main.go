@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	nativeLog "log"
 	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 )
 
 func main() {
@@ -15,15 +20,35 @@ func main() {
 	config := factory.Config
 	grpcServer := factory.GRPRServer
 
+	// Interrupt and terminate signals stop server and background work
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Release expired reservations in background
+	var sweeper sync.WaitGroup
+	sweeper.Add(1)
+	go func() {
+		defer sweeper.Done()
+		factory.Sweeper.Run(ctx)
+	}()
+
 	// Running gRPC Server
 	listener, err := net.Listen("tcp", config.GRPCPort)
 	if err != nil {
 		nativeLog.Fatal("cannot create grpc server: ", err)
 	}
 
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+
 	fmt.Printf("Running gRPC server on port %s\n", config.GRPCPort)
 	if err := grpcServer.Serve(listener); err != nil {
 		nativeLog.Fatalf("failed ro bind gRPC server on port %s, error: %s", config.GRPCPort, err.Error())
 	}
 
+	// Serve returns after a signal stopped server, wait for a running sweep to finish
+	stop()
+	sweeper.Wait()
 }
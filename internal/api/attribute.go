@@ -0,0 +1,75 @@
+package api
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+)
+
+type AttributeDefinition struct {
+	Id        uint     `json:"id"`
+	CompanyId uint     `json:"company_id"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"` // One of string, number, enum, bool
+	Unit      string   `json:"unit"`
+	Required  bool     `json:"required"`
+	Options   []string `json:"options,omitempty"` // Allowed values of enum type
+}
+
+func AttributeDefinitionApiToModel(d AttributeDefinition) *model.AttributeDefinition {
+	return &model.AttributeDefinition{
+		Id:        d.Id,
+		CompanyId: d.CompanyId,
+		Name:      d.Name,
+		Type:      d.Type,
+		Unit:      d.Unit,
+		Required:  d.Required,
+		Options:   d.Options,
+	}
+}
+
+func AttributeDefinitionSchemaToApi(d schema.AttributeDefinition) *AttributeDefinition {
+	return &AttributeDefinition{
+		Id:        d.ID,
+		CompanyId: d.CompanyId,
+		Name:      d.Name,
+		Type:      d.Type,
+		Unit:      d.Unit,
+		Required:  d.Required,
+		Options:   d.Options,
+	}
+}
+
+type (
+	CreateAttributeDefinitionRequest struct {
+		AttributeDefinition
+	}
+
+	CreateAttributeDefinitionResponse struct {
+		AttributeDefinition
+	}
+)
+
+type GetAttributeDefinitionsResponse struct {
+	AttributeDefinitions []AttributeDefinition `json:"attribute_definitions"`
+}
+
+type (
+	EditAttributeDefinitionRequest struct {
+		AttributeDefinition
+	}
+
+	EditAttributeDefinitionResponse struct {
+		AttributeDefinition
+	}
+)
+
+type DeleteAttributeDefinitionRequest struct {
+	CompanyId   uint `json:"company_id"`
+	AttributeId uint `json:"attribute_id"`
+}
+
+type AttributeFilter struct {
+	Name     string `json:"name"`
+	Operator string `json:"operator"` // One of eq, ne, gt, gte, lt, lte, a product without a value matches none
+	Value    string `json:"value"`
+}
@@ -0,0 +1,50 @@
+package api
+
+import "github.com/seed95/product-service/internal/model"
+
+// BulkEditResult is what a bulk edit did with a product, or would do in a dry run
+type BulkEditResult struct {
+	ProductId     uint     `json:"product_id"`
+	DesignCode    string   `json:"design_code"`
+	AddedColors   []string `json:"added_colors,omitempty"`
+	RemovedColors []string `json:"removed_colors,omitempty"`
+	AddedSizes    []string `json:"added_sizes,omitempty"`
+	RemovedSizes  []string `json:"removed_sizes,omitempty"`
+	Attributes    bool     `json:"attributes,omitempty"` // Attribute values are changed
+	Error         string   `json:"error,omitempty"`      // Why product failed
+}
+
+func BulkEditResultModelToApi(r model.BulkEditResult) *BulkEditResult {
+	return &BulkEditResult{
+		ProductId:     r.ProductId,
+		DesignCode:    r.DesignCode,
+		AddedColors:   r.AddedColors,
+		RemovedColors: r.RemovedColors,
+		AddedSizes:    r.AddedSizes,
+		RemovedSizes:  r.RemovedSizes,
+		Attributes:    r.Attributes,
+		Error:         r.Error,
+	}
+}
+
+type (
+	// BulkEditProductsRequest apply same changes to products of company that match request like SearchProductsRequest,
+	// an empty attribute value removes the attribute
+	BulkEditProductsRequest struct {
+		SearchProductsRequest
+		AddColors    []string          `json:"add_colors"`
+		RemoveColors []string          `json:"remove_colors"`
+		AddSizes     []string          `json:"add_sizes"`
+		RemoveSizes  []string          `json:"remove_sizes"`
+		Attributes   map[string]string `json:"attributes"` // Attribute name to value
+		DryRun       bool              `json:"dry_run"`    // Report what edit would do without saving
+	}
+
+	BulkEditProductsResponse struct {
+		DryRun    bool             `json:"dry_run"`
+		Changed   int              `json:"changed"`
+		Unchanged int              `json:"unchanged"`
+		Failed    int              `json:"failed"`
+		Products  []BulkEditResult `json:"products"`
+	}
+)
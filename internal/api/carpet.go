@@ -0,0 +1,159 @@
+package api
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/pkg/sku"
+)
+
+// Carpet is a sellable size and color of a product (SKU)
+type Carpet struct {
+	Id          string `json:"id"`
+	CompanyId   uint   `json:"company_id"`
+	ProductId   uint   `json:"product_id"`
+	DimensionId uint   `json:"dimension_id"`
+	ThemeId     uint   `json:"theme_id"`
+	DesignCode  string `json:"design_code"`
+	Size        string `json:"size"`
+	Color       string `json:"color"`
+	OnHand      int64  `json:"on_hand"`   // Pieces in all warehouses
+	Available   int64  `json:"available"` // Pieces in all warehouses that are not reserved
+}
+
+func CarpetModelToApi(c model.Carpet) *Carpet {
+	return &Carpet{
+		Id:          c.Id,
+		CompanyId:   c.CompanyId,
+		ProductId:   c.ProductId,
+		DimensionId: c.DimensionId,
+		ThemeId:     c.ThemeId,
+		DesignCode:  c.DesignCode,
+		Size:        c.Dimension,
+		Color:       c.Color,
+		OnHand:      c.OnHand,
+		Available:   c.Available,
+	}
+}
+
+func CarpetApiToModel(c Carpet) model.Carpet {
+	return model.Carpet{
+		Id:          c.Id,
+		CompanyId:   c.CompanyId,
+		ProductId:   c.ProductId,
+		DimensionId: c.DimensionId,
+		ThemeId:     c.ThemeId,
+		DesignCode:  c.DesignCode,
+		Dimension:   c.Size,
+		Color:       c.Color,
+	}
+}
+
+// PageRequest select a page of a listing, zero values mean first page and default size
+type PageRequest struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+}
+
+func PageRequestToModel(p PageRequest) model.Page {
+	return model.Page{Number: p.Page, Size: p.PageSize}.Normalize()
+}
+
+type (
+	// GetCarpetsRequest filter carpets by their products
+	GetCarpetsRequest struct {
+		SearchProductsRequest
+		PageRequest
+	}
+
+	GetProductCarpetsRequest struct {
+		CompanyId uint `json:"company_id"`
+		ProductId uint `json:"product_id"`
+		PageRequest
+	}
+
+	GetCarpetsResponse struct {
+		Carpets  []Carpet `json:"carpets"`
+		Total    int64    `json:"total"`
+		Page     int      `json:"page"`
+		PageSize int      `json:"page_size"`
+	}
+)
+
+type (
+	GetCarpetRequest struct {
+		CompanyId uint   `json:"company_id"`
+		CarpetId  string `json:"carpet_id"`
+	}
+
+	GetCarpetResponse struct {
+		Carpet
+	}
+)
+
+// CarpetExclusion is a size and color of a product that is not a carpet, on exclude the carpet
+// is selected with CarpetId, with DimensionId and ThemeId or with Size and Color of ProductId
+type CarpetExclusion struct {
+	Id          uint   `json:"id"`
+	CarpetId    string `json:"carpet_id"` // SKU
+	ProductId   uint   `json:"product_id"`
+	DimensionId uint   `json:"dimension_id"`
+	ThemeId     uint   `json:"theme_id"`
+	Size        string `json:"size"`
+	Color       string `json:"color"`
+	Reason      string `json:"reason"`
+}
+
+func CarpetExclusionApiToModel(e CarpetExclusion) *model.CarpetExclusion {
+	return &model.CarpetExclusion{
+		Id:          e.Id,
+		ProductId:   e.ProductId,
+		DimensionId: e.DimensionId,
+		ThemeId:     e.ThemeId,
+		Size:        e.Size,
+		Color:       e.Color,
+		Reason:      e.Reason,
+	}
+}
+
+func CarpetExclusionModelToApi(e model.CarpetExclusion) *CarpetExclusion {
+	return &CarpetExclusion{
+		Id:          e.Id,
+		CarpetId:    sku.Encode(sku.SKU{ProductId: e.ProductId, DimensionId: e.DimensionId, ThemeId: e.ThemeId}),
+		ProductId:   e.ProductId,
+		DimensionId: e.DimensionId,
+		ThemeId:     e.ThemeId,
+		Size:        e.Size,
+		Color:       e.Color,
+		Reason:      e.Reason,
+	}
+}
+
+type (
+	ExcludeCarpetRequest struct {
+		CompanyId uint `json:"company_id"`
+		CarpetExclusion
+	}
+
+	ExcludeCarpetResponse struct {
+		CarpetExclusion
+	}
+)
+
+// IncludeCarpetRequest select an excluded carpet with CarpetId or with ProductId, DimensionId and ThemeId
+type IncludeCarpetRequest struct {
+	CompanyId   uint   `json:"company_id"`
+	CarpetId    string `json:"carpet_id"`
+	ProductId   uint   `json:"product_id"`
+	DimensionId uint   `json:"dimension_id"`
+	ThemeId     uint   `json:"theme_id"`
+}
+
+type (
+	GetCarpetExclusionsRequest struct {
+		CompanyId uint `json:"company_id"`
+		ProductId uint `json:"product_id"`
+	}
+
+	GetCarpetExclusionsResponse struct {
+		Exclusions []CarpetExclusion `json:"exclusions"`
+	}
+)
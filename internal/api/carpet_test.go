@@ -0,0 +1,22 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGetCarpetsRequest_Unmarshal(t *testing.T) {
+	payload := `{"company_id": 1, "tags": ["کلاسیک"], "page": 2, "page_size": 20}`
+
+	req := GetCarpetsRequest{}
+	require.Nil(t, json.Unmarshal([]byte(payload), &req))
+	require.Equal(t, uint(1), req.CompanyId)
+	require.Equal(t, []string{"کلاسیک"}, req.Tags)
+	require.Equal(t, model.Page{Number: 2, Size: 20}, PageRequestToModel(req.PageRequest))
+}
+
+func TestPageRequestToModel(t *testing.T) {
+	require.Equal(t, model.Page{Number: 1, Size: model.DefaultPageSize}, PageRequestToModel(PageRequest{}))
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+)
+
+type Category struct {
+	Id        uint       `json:"id"`
+	CompanyId uint       `json:"company_id"`
+	ParentId  *uint      `json:"parent_id"` // Nil for a root category
+	Name      string     `json:"name"`
+	Kind      string     `json:"kind"` // One of category, collection
+	Children  []Category `json:"children,omitempty"`
+}
+
+func CategoryApiToModel(c Category) *model.Category {
+	return &model.Category{
+		Id:        c.Id,
+		CompanyId: c.CompanyId,
+		ParentId:  c.ParentId,
+		Name:      c.Name,
+		Kind:      c.Kind,
+	}
+}
+
+func CategorySchemaToApi(c schema.Category) *Category {
+	return &Category{
+		Id:        c.ID,
+		CompanyId: c.CompanyId,
+		ParentId:  c.ParentId,
+		Name:      c.Name,
+		Kind:      c.Kind,
+	}
+}
+
+// CategoriesSchemaToTree return root categories with their descendants as children,
+// a category whose parent isn't in `categories` is returned as a root
+func CategoriesSchemaToTree(categories []schema.Category) []Category {
+	children := make(map[uint][]schema.Category)
+	exist := make(map[uint]bool, len(categories))
+	for _, c := range categories {
+		exist[c.ID] = true
+	}
+
+	var roots []schema.Category
+	for _, c := range categories {
+		if c.ParentId == nil || !exist[*c.ParentId] {
+			roots = append(roots, c)
+		} else {
+			children[*c.ParentId] = append(children[*c.ParentId], c)
+		}
+	}
+
+	var build func(nodes []schema.Category) []Category
+	build = func(nodes []schema.Category) []Category {
+		result := make([]Category, len(nodes))
+		for i, n := range nodes {
+			result[i] = *CategorySchemaToApi(n)
+			result[i].Children = build(children[n.ID])
+		}
+		return result
+	}
+
+	return build(roots)
+}
+
+type (
+	CreateCategoryRequest struct {
+		Category
+	}
+
+	CreateCategoryResponse struct {
+		Category
+	}
+)
+
+type GetCategoriesResponse struct {
+	Categories []Category `json:"categories"`
+}
+
+type (
+	MoveCategoryRequest struct {
+		CompanyId  uint  `json:"company_id"`
+		CategoryId uint  `json:"category_id"`
+		ParentId   *uint `json:"parent_id"` // Nil move category to root
+	}
+
+	MoveCategoryResponse struct {
+		Category
+	}
+)
+
+type DeleteCategoryRequest struct {
+	CompanyId  uint `json:"company_id"`
+	CategoryId uint `json:"category_id"`
+}
+
+type GetCategoryProductsRequest struct {
+	CompanyId  uint `json:"company_id"`
+	CategoryId uint `json:"category_id"`
+}
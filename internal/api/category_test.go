@@ -0,0 +1,30 @@
+package api
+
+import (
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestCategoriesSchemaToTree(t *testing.T) {
+	silk, kashan, qom, modern := uint(1), uint(2), uint(3), uint(4)
+	categories := []schema.Category{
+		{Model: gorm.Model{ID: silk}, Name: "ابریشم"},
+		{Model: gorm.Model{ID: kashan}, ParentId: &silk, Name: "کاشان"},
+		{Model: gorm.Model{ID: qom}, ParentId: &silk, Name: "قم"},
+		{Model: gorm.Model{ID: modern}, Name: "مدرن"},
+	}
+
+	tree := CategoriesSchemaToTree(categories)
+	require.Equal(t, 2, len(tree))
+	require.Equal(t, silk, tree[0].Id)
+	require.Equal(t, 2, len(tree[0].Children))
+	require.Equal(t, qom, tree[0].Children[1].Id)
+	require.Equal(t, modern, tree[1].Id)
+	require.Equal(t, 0, len(tree[1].Children))
+
+	// Orphan is a root
+	tree = CategoriesSchemaToTree(categories[1:])
+	require.Equal(t, 3, len(tree))
+}
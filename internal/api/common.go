@@ -6,3 +6,8 @@ type Common struct {
 	CompanyId   int64  `json:"company_id"`
 	CompanyName string `json:"company_name"`
 }
+
+// CompanyRequest is payload of requests that only need a company
+type CompanyRequest struct {
+	CompanyId uint `json:"company_id"`
+}
@@ -0,0 +1,24 @@
+package api
+
+type (
+	DumpCatalogRequest struct {
+		CompanyId uint `json:"company_id"`
+	}
+
+	// DumpCatalogResponse has a versioned json dump of complete catalog of company with checksums
+	DumpCatalogResponse struct {
+		ContentType string `json:"content_type"`
+		Data        []byte `json:"data"` // Base64 in json
+	}
+
+	// RestoreCatalogRequest restore a dump of any company into `CompanyId`, company should have no catalog
+	RestoreCatalogRequest struct {
+		CompanyId uint   `json:"company_id"`
+		Data      []byte `json:"data"` // Base64 in json
+	}
+
+	RestoreCatalogResponse struct {
+		Counts     map[string]int `json:"counts"`      // Restored records of every section
+		ProductIds map[uint]uint  `json:"product_ids"` // Id of a product in dump to its new id
+	}
+)
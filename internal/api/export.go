@@ -0,0 +1,23 @@
+package api
+
+type (
+	// ExportCatalogRequest select products of company like SearchProductsRequest,
+	// prices are exported only with a price list
+	ExportCatalogRequest struct {
+		SearchProductsRequest
+		Kind        string   `json:"kind"`    // One of products (default), carpets
+		Format      string   `json:"format"`  // One of csv (default), jsonl
+		Columns     []string `json:"columns"` // Empty means all columns
+		PriceListId uint     `json:"price_list_id"`
+		AfterId     uint     `json:"after_id"` // NextAfterId of previous response to continue an export
+	}
+
+	// ExportCatalogResponse hold a bounded part of an export, only first part has a csv header so parts
+	// concatenate into one file. NextAfterId is zero when export is finished, otherwise it should be sent
+	// as AfterId of next request
+	ExportCatalogResponse struct {
+		ContentType string `json:"content_type"`
+		Data        []byte `json:"data"` // Base64 in json
+		NextAfterId uint   `json:"next_after_id"`
+	}
+)
@@ -0,0 +1,42 @@
+package api
+
+import "github.com/seed95/product-service/internal/model"
+
+// ImportResult is what import did with a line of csv, or would do in a dry run
+type ImportResult struct {
+	Line       int    `json:"line"`
+	DesignCode string `json:"design_code"`
+	Action     string `json:"action"`               // One of create, update, fail
+	ProductId  uint   `json:"product_id,omitempty"` // Created or updated product, not set for a create in a dry run
+	Error      string `json:"error,omitempty"`      // Why row failed
+}
+
+func ImportResultModelToApi(r model.ImportResult) *ImportResult {
+	return &ImportResult{
+		Line:       r.Line,
+		DesignCode: r.DesignCode,
+		Action:     r.Action,
+		ProductId:  r.ProductId,
+		Error:      r.Error,
+	}
+}
+
+type (
+	// ImportProductsRequest has a csv with a header line and a line per design, columns are
+	// design_code, description, sizes, colors and attributes. sizes and colors are separated
+	// with | and attributes are name=value separated with |
+	ImportProductsRequest struct {
+		CompanyId uint   `json:"company_id"`
+		Csv       string `json:"csv"`
+		DryRun    bool   `json:"dry_run"`    // Report what import would do without saving
+		ChunkSize int    `json:"chunk_size"` // Rows of a transaction, zero means default
+	}
+
+	ImportProductsResponse struct {
+		DryRun  bool           `json:"dry_run"`
+		Created int            `json:"created"`
+		Updated int            `json:"updated"`
+		Failed  int            `json:"failed"`
+		Rows    []ImportResult `json:"rows"`
+	}
+)
@@ -0,0 +1,31 @@
+package api
+
+type (
+	GetCarpetLabelRequest struct {
+		CompanyId uint   `json:"company_id"`
+		CarpetId  string `json:"carpet_id"`
+		Format    string `json:"format"` // One of png, svg, default is svg for persian text and png otherwise
+	}
+
+	// GetLabelSheetRequest select carpets of a product, or of company if ProductId is zero.
+	// a sheet without page has every carpet, up to service.MaxLabelSheetCarpets
+	GetLabelSheetRequest struct {
+		CompanyId uint   `json:"company_id"`
+		ProductId uint   `json:"product_id"`
+		Format    string `json:"format"`  // One of png, svg, default is svg for persian text and png otherwise
+		Columns   int    `json:"columns"` // Labels in a row of sheet, default 2
+		PageRequest
+	}
+
+	LabelResponse struct {
+		ContentType string `json:"content_type"`
+		Data        []byte `json:"data"` // Base64 in json
+	}
+
+	GetLabelSheetResponse struct {
+		LabelResponse
+		Total    int64 `json:"total"`     // Number of carpets in all pages
+		Page     int   `json:"page"`      // Zero for a sheet of every carpet
+		PageSize int   `json:"page_size"` // Zero for a sheet of every carpet
+	}
+)
@@ -0,0 +1,307 @@
+package api
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"time"
+)
+
+type PriceList struct {
+	Id        uint   `json:"id"`
+	CompanyId uint   `json:"company_id"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`     // One of retail, wholesale, dealer
+	Currency  string `json:"currency"` // ISO 4217 code
+}
+
+func PriceListApiToModel(l PriceList) *model.PriceList {
+	return &model.PriceList{
+		Id:        l.Id,
+		CompanyId: l.CompanyId,
+		Name:      l.Name,
+		Kind:      l.Kind,
+		Currency:  l.Currency,
+	}
+}
+
+func PriceListSchemaToApi(l schema.PriceList) *PriceList {
+	return &PriceList{
+		Id:        l.ID,
+		CompanyId: l.CompanyId,
+		Name:      l.Name,
+		Kind:      l.Kind,
+		Currency:  l.Currency,
+	}
+}
+
+// Price is amount of a carpet, of every color of a product size or of a square meter of a product.
+// on add, a carpet is selected with CarpetId or with ProductId, DimensionId (or Size) and ThemeId
+type Price struct {
+	Id             uint       `json:"id"`
+	PriceListId    uint       `json:"price_list_id"`
+	CarpetId       string     `json:"carpet_id,omitempty"` // SKU
+	ProductId      uint       `json:"product_id"`
+	DimensionId    uint       `json:"dimension_id"`
+	Size           string     `json:"size,omitempty"`
+	ThemeId        uint       `json:"theme_id"` // Zero for every color
+	PerSquareMeter bool       `json:"per_square_meter"`
+	Amount         int64      `json:"amount"` // Smallest unit of currency
+	ValidFrom      time.Time  `json:"valid_from"`
+	ValidTo        *time.Time `json:"valid_to"` // Nil is open ended
+}
+
+func PriceApiToModel(p Price) *model.Price {
+	return &model.Price{
+		Id:             p.Id,
+		PriceListId:    p.PriceListId,
+		ProductId:      p.ProductId,
+		DimensionId:    p.DimensionId,
+		Size:           p.Size,
+		ThemeId:        p.ThemeId,
+		PerSquareMeter: p.PerSquareMeter,
+		Amount:         p.Amount,
+		ValidFrom:      p.ValidFrom,
+		ValidTo:        p.ValidTo,
+	}
+}
+
+func PriceSchemaToApi(p schema.Price) *Price {
+	return PriceModelToApi(schema.PriceToModel(&p))
+}
+
+func PriceModelToApi(p model.Price) *Price {
+	return &Price{
+		Id:             p.Id,
+		PriceListId:    p.PriceListId,
+		ProductId:      p.ProductId,
+		DimensionId:    p.DimensionId,
+		ThemeId:        p.ThemeId,
+		PerSquareMeter: p.PerSquareMeter,
+		Amount:         p.Amount,
+		ValidFrom:      p.ValidFrom,
+		ValidTo:        p.ValidTo,
+	}
+}
+
+// EffectivePrice is price of a carpet in a price list at a time, an area price has its calculation in Breakdown
+type EffectivePrice struct {
+	Carpet    Carpet          `json:"carpet"`
+	PriceList PriceList       `json:"price_list"`
+	Price     Price           `json:"price"`
+	Source    string          `json:"source"` // One of carpet, size, area
+	Breakdown *PriceBreakdown `json:"breakdown,omitempty"`
+}
+
+func EffectivePriceModelToApi(p model.EffectivePrice) *EffectivePrice {
+	return &EffectivePrice{
+		Carpet: *CarpetModelToApi(p.Carpet),
+		PriceList: PriceList{
+			Id:        p.PriceList.Id,
+			CompanyId: p.PriceList.CompanyId,
+			Name:      p.PriceList.Name,
+			Kind:      p.PriceList.Kind,
+			Currency:  p.PriceList.Currency,
+		},
+		Price:     *PriceModelToApi(p.Price),
+		Source:    p.Source,
+		Breakdown: PriceBreakdownModelToApi(p.Breakdown),
+	}
+}
+
+// PriceAdjustment is a percent of area prices for a color or shape, zero ProductId applies to every product
+type PriceAdjustment struct {
+	Id          uint    `json:"id"`
+	PriceListId uint    `json:"price_list_id"`
+	ProductId   uint    `json:"product_id"`
+	Kind        string  `json:"kind"`    // One of color, shape
+	Value       string  `json:"value"`   // Color or one of rectangle, square, runner, round
+	Percent     float64 `json:"percent"` // Negative for discount
+}
+
+func PriceAdjustmentApiToModel(a PriceAdjustment) *model.PriceAdjustment {
+	return &model.PriceAdjustment{
+		Id:          a.Id,
+		PriceListId: a.PriceListId,
+		ProductId:   a.ProductId,
+		Kind:        a.Kind,
+		Value:       a.Value,
+		Percent:     a.Percent,
+	}
+}
+
+func PriceAdjustmentModelToApi(a model.PriceAdjustment) *PriceAdjustment {
+	return &PriceAdjustment{
+		Id:          a.Id,
+		PriceListId: a.PriceListId,
+		ProductId:   a.ProductId,
+		Kind:        a.Kind,
+		Value:       a.Value,
+		Percent:     a.Percent,
+	}
+}
+
+func PriceAdjustmentSchemaToApi(a schema.PriceAdjustment) *PriceAdjustment {
+	return PriceAdjustmentModelToApi(schema.PriceAdjustmentToModel(&a))
+}
+
+type AppliedAdjustment struct {
+	PriceAdjustment
+	Amount int64 `json:"amount"`
+}
+
+// PriceBreakdown is calculation of an area price
+type PriceBreakdown struct {
+	Width          uint                `json:"width"`  // Centimeter, zero if size is known only by its area
+	Length         uint                `json:"length"` // Centimeter
+	Area           float64             `json:"area"`   // Square meter
+	Shape          string              `json:"shape"`
+	PerSquareMeter int64               `json:"per_square_meter"`
+	Base           int64               `json:"base"`
+	Adjustments    []AppliedAdjustment `json:"adjustments"`
+	Total          int64               `json:"total"`
+}
+
+func PriceBreakdownModelToApi(b *model.PriceBreakdown) *PriceBreakdown {
+	if b == nil {
+		return nil
+	}
+
+	result := &PriceBreakdown{
+		Width:          b.Size.Width,
+		Length:         b.Size.Length,
+		Area:           b.Size.Area,
+		Shape:          b.Size.Shape,
+		PerSquareMeter: b.PerSquareMeter,
+		Base:           b.Base,
+		Adjustments:    make([]AppliedAdjustment, len(b.Adjustments)),
+		Total:          b.Total,
+	}
+	for i, a := range b.Adjustments {
+		result.Adjustments[i] = AppliedAdjustment{
+			PriceAdjustment: *PriceAdjustmentModelToApi(a.PriceAdjustment),
+			Amount:          a.Amount,
+		}
+	}
+	return result
+}
+
+type (
+	CreatePriceListRequest struct {
+		PriceList
+	}
+
+	CreatePriceListResponse struct {
+		PriceList
+	}
+)
+
+type GetPriceListsResponse struct {
+	PriceLists []PriceList `json:"price_lists"`
+}
+
+type DeletePriceListRequest struct {
+	CompanyId   uint `json:"company_id"`
+	PriceListId uint `json:"price_list_id"`
+}
+
+type (
+	AddPriceRequest struct {
+		CompanyId uint `json:"company_id"`
+		Price
+	}
+
+	AddPriceResponse struct {
+		Price
+	}
+)
+
+type (
+	// GetPricesRequest list prices of a price list, zero ProductId means every product
+	GetPricesRequest struct {
+		CompanyId   uint `json:"company_id"`
+		PriceListId uint `json:"price_list_id"`
+		ProductId   uint `json:"product_id"`
+	}
+
+	GetPricesResponse struct {
+		Prices []Price `json:"prices"`
+	}
+)
+
+type DeletePriceRequest struct {
+	CompanyId uint `json:"company_id"`
+	PriceId   uint `json:"price_id"`
+}
+
+type (
+	// ResolvePriceRequest ask effective price of a carpet at a time, nil At means now
+	ResolvePriceRequest struct {
+		CompanyId   uint       `json:"company_id"`
+		PriceListId uint       `json:"price_list_id"`
+		CarpetId    string     `json:"carpet_id"`
+		At          *time.Time `json:"at"`
+	}
+
+	ResolvePriceResponse struct {
+		EffectivePrice
+	}
+)
+
+type (
+	AddPriceAdjustmentRequest struct {
+		CompanyId uint `json:"company_id"`
+		PriceAdjustment
+	}
+
+	AddPriceAdjustmentResponse struct {
+		PriceAdjustment
+	}
+)
+
+type (
+	GetPriceAdjustmentsRequest struct {
+		CompanyId   uint `json:"company_id"`
+		PriceListId uint `json:"price_list_id"`
+	}
+
+	GetPriceAdjustmentsResponse struct {
+		Adjustments []PriceAdjustment `json:"adjustments"`
+	}
+)
+
+type DeletePriceAdjustmentRequest struct {
+	CompanyId    uint `json:"company_id"`
+	AdjustmentId uint `json:"adjustment_id"`
+}
+
+type (
+	// QuotePriceRequest ask area price of a product for a size label of product or a custom Width and Length,
+	// empty Shape is found from width and length, nil At means now
+	QuotePriceRequest struct {
+		CompanyId   uint       `json:"company_id"`
+		PriceListId uint       `json:"price_list_id"`
+		ProductId   uint       `json:"product_id"`
+		Size        string     `json:"size"`
+		Width       uint       `json:"width"`  // Centimeter
+		Length      uint       `json:"length"` // Centimeter
+		Shape       string     `json:"shape"`
+		Color       string     `json:"color"`
+		At          *time.Time `json:"at"`
+	}
+
+	QuotePriceResponse struct {
+		EffectivePrice
+	}
+)
+
+func QuotePriceRequestToModel(req QuotePriceRequest) model.PriceQuote {
+	return model.PriceQuote{
+		PriceListId: req.PriceListId,
+		ProductId:   req.ProductId,
+		Size:        req.Size,
+		Width:       req.Width,
+		Length:      req.Length,
+		Shape:       req.Shape,
+		Color:       req.Color,
+	}
+}
@@ -3,39 +3,64 @@ package api
 import (
 	"github.com/seed95/product-service/internal/model"
 	"github.com/seed95/product-service/internal/repo/product/schema"
+	"time"
 )
 
 type Product struct {
-	Id          uint     `json:"id"`
-	CompanyId   uint     `json:"company_id"`
-	CompanyName string   `json:"company_name"`
-	DesignCode  string   `json:"design_code"`
-	Description string   `json:"description"`
-	Sizes       []string `json:"sizes"`
-	Colors      []string `json:"colors"`
+	Id             uint              `json:"id"`
+	CompanyId      uint              `json:"company_id"`
+	CompanyName    string            `json:"company_name"`
+	DesignCode     string            `json:"design_code"`
+	Description    string            `json:"description"`
+	Sizes          []string          `json:"sizes"`
+	Colors         []string          `json:"colors"`
+	StandardSizes  []uint            `json:"standard_sizes,omitempty"` // Company catalog size ids, their labels are also in Sizes
+	Attributes     map[string]string `json:"attributes,omitempty"`     // Attribute name to value
+	Categories     []uint            `json:"categories,omitempty"`     // Category tree node ids
+	Tags           []string          `json:"tags,omitempty"`           // Read only, edited with tag operations
+	Status         string            `json:"status"`                   // One of draft, published (default), archived, discontinued, only set on create
+	PublishedAt    *time.Time        `json:"published_at,omitempty"`
+	ArchivedAt     *time.Time        `json:"archived_at,omitempty"`
+	DiscontinuedAt *time.Time        `json:"discontinued_at,omitempty"`
+	OnHand         int64             `json:"on_hand"`   // Read only, pieces of all carpets in all warehouses
+	Available      int64             `json:"available"` // Read only, pieces of all carpets in all warehouses that are not reserved
 }
 
 func ProductApiToModel(p Product) *model.Product {
 	return &model.Product{
-		Id:          p.Id,
-		CompanyName: p.CompanyName,
-		CompanyId:   p.CompanyId,
-		DesignCode:  p.DesignCode,
-		Colors:      p.Colors,
-		Sizes:       p.Sizes,
-		Description: p.Description,
+		Id:            p.Id,
+		CompanyName:   p.CompanyName,
+		CompanyId:     p.CompanyId,
+		DesignCode:    p.DesignCode,
+		Colors:        p.Colors,
+		Sizes:         p.Sizes,
+		Description:   p.Description,
+		StandardSizes: p.StandardSizes,
+		Attributes:    p.Attributes,
+		Categories:    p.Categories,
+		Status:        p.Status,
 	}
 }
 
 func ProductSchemaToApi(p schema.Product) *Product {
 	return &Product{
-		Id:          p.ID,
-		CompanyId:   p.CompanyId,
-		CompanyName: "", //todo fixme
-		DesignCode:  p.DesignCode,
-		Description: p.Description,
-		Sizes:       schema.GetSizes(p.Dimensions),
-		Colors:      schema.GetColors(p.Themes),
+		Id:             p.ID,
+		CompanyId:      p.CompanyId,
+		CompanyName:    "", //todo fixme
+		DesignCode:     p.DesignCode,
+		Description:    p.Description,
+		Sizes:          schema.GetSizes(p.Dimensions),
+		Colors:         schema.GetColors(p.Themes),
+		StandardSizes:  schema.GetStandardSizeIds(p.Dimensions),
+		Attributes:     schema.GetAttributes(p.Attributes),
+		Categories:     schema.GetCategoryIds(p.Categories),
+		Tags:           schema.GetTagNames(p.Tags),
+		Status:         p.Status,
+		PublishedAt:    p.PublishedAt,
+		ArchivedAt:     p.ArchivedAt,
+		DiscontinuedAt: p.DiscontinuedAt,
+		OnHand:         p.OnHand,
+		Available:      p.Available,
 	}
 }
 
@@ -64,6 +89,188 @@ type (
 	}
 )
 
+// Add, remove or rename colors and sizes of a product, response of each is EditProductResponse
+type (
+	AddThemesRequest struct {
+		CompanyId uint     `json:"company_id"`
+		ProductId uint     `json:"product_id"`
+		Colors    []string `json:"colors"`
+	}
+
+	RemoveThemesRequest struct {
+		CompanyId uint   `json:"company_id"`
+		ProductId uint   `json:"product_id"`
+		ThemeIds  []uint `json:"theme_ids"`
+	}
+
+	AddDimensionsRequest struct {
+		CompanyId uint     `json:"company_id"`
+		ProductId uint     `json:"product_id"`
+		Sizes     []string `json:"sizes"`
+	}
+
+	RemoveDimensionsRequest struct {
+		CompanyId    uint   `json:"company_id"`
+		ProductId    uint   `json:"product_id"`
+		DimensionIds []uint `json:"dimension_ids"`
+	}
+
+	// RenameThemeRequest change a color in place, its carpets keep their SKU
+	RenameThemeRequest struct {
+		CompanyId uint   `json:"company_id"`
+		ProductId uint   `json:"product_id"`
+		ThemeId   uint   `json:"theme_id"`
+		Color     string `json:"color"`
+	}
+
+	// RenameDimensionRequest change a size in place, its carpets keep their SKU
+	RenameDimensionRequest struct {
+		CompanyId   uint   `json:"company_id"`
+		ProductId   uint   `json:"product_id"`
+		DimensionId uint   `json:"dimension_id"`
+		Size        string `json:"size"`
+	}
+)
+
+// ProductAlias is an old design code of a product
+type ProductAlias struct {
+	DesignCode string    `json:"design_code"`
+	CreatedAt  time.Time `json:"created_at"` // Time product was renamed
+}
+
+func ProductAliasSchemaToApi(a schema.ProductAlias) *ProductAlias {
+	return &ProductAlias{
+		DesignCode: a.DesignCode,
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+type (
+	// GetProductWithDesignCodeRequest find a product by its design code or an old code of it
+	GetProductWithDesignCodeRequest struct {
+		CompanyId  uint   `json:"company_id"`
+		DesignCode string `json:"design_code"`
+	}
+
+	// RenameDesignCodeRequest change design code of a product, old code is kept as an alias
+	RenameDesignCodeRequest struct {
+		CompanyId  uint   `json:"company_id"`
+		ProductId  uint   `json:"product_id"`
+		DesignCode string `json:"design_code"`
+	}
+
+	GetProductAliasesRequest struct {
+		CompanyId uint `json:"company_id"`
+		ProductId uint `json:"product_id"`
+	}
+
+	GetProductAliasesResponse struct {
+		Aliases []ProductAlias `json:"aliases"`
+	}
+)
+
+type (
+	// MergeProductsRequest fold source product into target product, source design code is kept as an alias of target
+	MergeProductsRequest struct {
+		CompanyId       uint `json:"company_id"`
+		SourceProductId uint `json:"source_product_id"`
+		TargetProductId uint `json:"target_product_id"`
+	}
+
+	GetProductHistoryRequest struct {
+		CompanyId uint `json:"company_id"`
+		ProductId uint `json:"product_id"`
+	}
+
+	GetProductHistoryResponse struct {
+		History []ProductHistory `json:"history"`
+	}
+)
+
+// ProductHistory is a recorded change of a product
+type ProductHistory struct {
+	Kind            string    `json:"kind"`
+	SourceProductId uint      `json:"source_product_id"` // Product folded in by a merge
+	Note            string    `json:"note"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func ProductHistorySchemaToApi(h schema.ProductHistory) *ProductHistory {
+	return &ProductHistory{
+		Kind:            h.Kind,
+		SourceProductId: h.SourceProductId,
+		Note:            h.Note,
+		CreatedAt:       h.CreatedAt,
+	}
+}
+
 type GetProductResponse struct {
 	Product
 }
+
+type (
+	ChangeProductStatusRequest struct {
+		CompanyId uint   `json:"company_id"`
+		ProductId uint   `json:"product_id"`
+		Status    string `json:"status"`
+	}
+
+	ChangeProductStatusResponse struct {
+		Product
+	}
+)
+
+type (
+	// CloneProductRequest copy a product with a new design code as a draft,
+	// zero TargetCompanyId means company of product
+	CloneProductRequest struct {
+		CompanyId       uint   `json:"company_id"`
+		ProductId       uint   `json:"product_id"`
+		DesignCode      string `json:"design_code"`
+		TargetCompanyId uint   `json:"target_company_id"`
+		WithPrices      bool   `json:"with_prices"`
+	}
+
+	CloneProductResponse struct {
+		Product
+	}
+)
+
+func CloneProductApiToModel(req CloneProductRequest) model.ProductClone {
+	return model.ProductClone{
+		CompanyId:       req.CompanyId,
+		ProductId:       req.ProductId,
+		DesignCode:      req.DesignCode,
+		TargetCompanyId: req.TargetCompanyId,
+		WithPrices:      req.WithPrices,
+	}
+}
+
+type SearchProductsRequest struct {
+	CompanyId  uint              `json:"company_id"`
+	Attributes []AttributeFilter `json:"attributes"`
+	CategoryId uint              `json:"category_id"` // Members of the category or its descendants
+	ProductIds []uint            `json:"product_ids"`
+	Tags       []string          `json:"tags"`
+	TagMatch   string            `json:"tag_match"` // One of any (default), all
+	Statuses   []string          `json:"statuses"`
+}
+
+func SearchProductsRequestToFilter(req SearchProductsRequest) *model.ProductFilter {
+	filter := model.ProductFilter{
+		CompanyId:  req.CompanyId,
+		CategoryId: req.CategoryId,
+		ProductIds: req.ProductIds,
+		Tags:       req.Tags,
+		TagMatch:   req.TagMatch,
+		Statuses:   req.Statuses,
+	}
+	for _, a := range req.Attributes {
+		filter.Attributes = append(filter.Attributes, model.AttributeFilter{
+			Name:     a.Name,
+			Operator: a.Operator,
+			Value:    a.Value,
+		})
+	}
+	return &filter
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+)
+
+type StandardSize struct {
+	Id        uint   `json:"id"`
+	CompanyId uint   `json:"company_id"`
+	Label     string `json:"label"`
+	Width     uint   `json:"width"`  // Centimeter
+	Length    uint   `json:"length"` // Centimeter
+	SortOrder int    `json:"sort_order"`
+}
+
+func StandardSizeApiToModel(s StandardSize) *model.StandardSize {
+	return &model.StandardSize{
+		Id:        s.Id,
+		CompanyId: s.CompanyId,
+		Label:     s.Label,
+		Width:     s.Width,
+		Length:    s.Length,
+		SortOrder: s.SortOrder,
+	}
+}
+
+func StandardSizeSchemaToApi(s schema.StandardSize) *StandardSize {
+	return &StandardSize{
+		Id:        s.ID,
+		CompanyId: s.CompanyId,
+		Label:     s.Label,
+		Width:     s.Width,
+		Length:    s.Length,
+		SortOrder: s.SortOrder,
+	}
+}
+
+func StandardSizeModelToApi(s model.StandardSize) *StandardSize {
+	return &StandardSize{
+		Id:        s.Id,
+		CompanyId: s.CompanyId,
+		Label:     s.Label,
+		Width:     s.Width,
+		Length:    s.Length,
+		SortOrder: s.SortOrder,
+	}
+}
+
+type (
+	CreateStandardSizeRequest struct {
+		StandardSize
+	}
+
+	CreateStandardSizeResponse struct {
+		StandardSize
+	}
+)
+
+type GetStandardSizesResponse struct {
+	StandardSizes []StandardSize `json:"standard_sizes"`
+}
+
+type (
+	EditStandardSizeRequest struct {
+		StandardSize
+	}
+
+	EditStandardSizeResponse struct {
+		StandardSize
+	}
+)
+
+type DeleteStandardSizeRequest struct {
+	CompanyId      uint `json:"company_id"`
+	StandardSizeId uint `json:"standard_size_id"`
+}
+
+type (
+	ProductCode struct {
+		ProductId  uint   `json:"product_id"`
+		DesignCode string `json:"design_code"`
+	}
+
+	StandardSizeProducts struct {
+		StandardSize StandardSize  `json:"standard_size"`
+		Products     []ProductCode `json:"products"`
+	}
+
+	GetStandardSizeProductsResponse struct {
+		StandardSizes []StandardSizeProducts `json:"standard_sizes"`
+	}
+)
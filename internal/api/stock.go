@@ -0,0 +1,267 @@
+package api
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/sku"
+	"time"
+)
+
+type Warehouse struct {
+	Id        uint   `json:"id"`
+	CompanyId uint   `json:"company_id"`
+	Name      string `json:"name"`
+}
+
+func WarehouseApiToModel(w Warehouse) *model.Warehouse {
+	return &model.Warehouse{
+		Id:        w.Id,
+		CompanyId: w.CompanyId,
+		Name:      w.Name,
+	}
+}
+
+func WarehouseSchemaToApi(w schema.Warehouse) *Warehouse {
+	return &Warehouse{
+		Id:        w.ID,
+		CompanyId: w.CompanyId,
+		Name:      w.Name,
+	}
+}
+
+// StockMovement is an entry of stock ledger of a carpet, on record a carpet is selected with CarpetId
+// or with ProductId, DimensionId and ThemeId. in ledger Quantity is signed change of warehouse quantity
+// and CounterWarehouseId is other warehouse of a transfer
+type StockMovement struct {
+	Id                 uint      `json:"id"`
+	CompanyId          uint      `json:"company_id"`
+	WarehouseId        uint      `json:"warehouse_id"`
+	ToWarehouseId      uint      `json:"to_warehouse_id,omitempty"` // Destination of a transfer
+	CounterWarehouseId uint      `json:"counter_warehouse_id,omitempty"`
+	CarpetId           string    `json:"carpet_id"` // SKU
+	ProductId          uint      `json:"product_id"`
+	DimensionId        uint      `json:"dimension_id"`
+	ThemeId            uint      `json:"theme_id"`
+	Kind               string    `json:"kind"`                     // One of receipt, sale, adjustment, transfer
+	Quantity           int64     `json:"quantity"`                 // Positive, except an adjustment that is signed
+	OnHand             int64     `json:"on_hand"`                  // Read only, quantity of warehouse after movement
+	ReservationId      uint      `json:"reservation_id,omitempty"` // Reservation a sale fulfills
+	Reference          string    `json:"reference"`
+	Note               string    `json:"note"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+func StockMovementApiToModel(m StockMovement) *model.StockMovement {
+	return &model.StockMovement{
+		Id:            m.Id,
+		CompanyId:     m.CompanyId,
+		WarehouseId:   m.WarehouseId,
+		ToWarehouseId: m.ToWarehouseId,
+		ProductId:     m.ProductId,
+		DimensionId:   m.DimensionId,
+		ThemeId:       m.ThemeId,
+		Kind:          m.Kind,
+		Quantity:      m.Quantity,
+		ReservationId: m.ReservationId,
+		Reference:     m.Reference,
+		Note:          m.Note,
+	}
+}
+
+func StockMovementSchemaToApi(m schema.StockMovement) *StockMovement {
+	return &StockMovement{
+		Id:                 m.ID,
+		CompanyId:          m.CompanyId,
+		WarehouseId:        m.WarehouseId,
+		CounterWarehouseId: m.CounterWarehouseId,
+		CarpetId:           sku.Encode(sku.SKU{ProductId: m.ProductId, DimensionId: m.DimensionId, ThemeId: m.ThemeId}),
+		ProductId:          m.ProductId,
+		DimensionId:        m.DimensionId,
+		ThemeId:            m.ThemeId,
+		Kind:               m.Kind,
+		Quantity:           m.Quantity,
+		OnHand:             m.OnHand,
+		ReservationId:      m.ReservationId,
+		Reference:          m.Reference,
+		Note:               m.Note,
+		CreatedAt:          m.CreatedAt,
+	}
+}
+
+// Reservation hold pieces of a carpet in a warehouse for a holder until ExpiresAt,
+// on reserve a carpet is selected with CarpetId or with ProductId, DimensionId and ThemeId
+type Reservation struct {
+	Id            uint       `json:"id"`
+	CompanyId     uint       `json:"company_id"`
+	WarehouseId   uint       `json:"warehouse_id"`
+	CarpetId      string     `json:"carpet_id"` // SKU
+	ProductId     uint       `json:"product_id"`
+	DimensionId   uint       `json:"dimension_id"`
+	ThemeId       uint       `json:"theme_id"`
+	Quantity      int64      `json:"quantity"`
+	Holder        string     `json:"holder"`
+	Reason        string     `json:"reason"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	ReleasedAt    *time.Time `json:"released_at,omitempty"`    // Read only
+	ReleaseReason string     `json:"release_reason,omitempty"` // Read only, one of fulfilled, cancelled, expired
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func ReservationApiToModel(r Reservation) *model.Reservation {
+	return &model.Reservation{
+		Id:          r.Id,
+		CompanyId:   r.CompanyId,
+		WarehouseId: r.WarehouseId,
+		ProductId:   r.ProductId,
+		DimensionId: r.DimensionId,
+		ThemeId:     r.ThemeId,
+		Quantity:    r.Quantity,
+		Holder:      r.Holder,
+		Reason:      r.Reason,
+		ExpiresAt:   r.ExpiresAt,
+	}
+}
+
+func ReservationSchemaToApi(r schema.Reservation) *Reservation {
+	return &Reservation{
+		Id:            r.ID,
+		CompanyId:     r.CompanyId,
+		WarehouseId:   r.WarehouseId,
+		CarpetId:      sku.Encode(sku.SKU{ProductId: r.ProductId, DimensionId: r.DimensionId, ThemeId: r.ThemeId}),
+		ProductId:     r.ProductId,
+		DimensionId:   r.DimensionId,
+		ThemeId:       r.ThemeId,
+		Quantity:      r.Quantity,
+		Holder:        r.Holder,
+		Reason:        r.Reason,
+		ExpiresAt:     r.ExpiresAt,
+		ReleasedAt:    r.ReleasedAt,
+		ReleaseReason: r.ReleaseReason,
+		CreatedAt:     r.CreatedAt,
+	}
+}
+
+// StockLevel is quantity of a carpet in a warehouse, Available is on hand pieces that are not reserved
+type StockLevel struct {
+	WarehouseId uint   `json:"warehouse_id"`
+	Carpet      Carpet `json:"carpet"`
+	OnHand      int64  `json:"on_hand"`
+	Reserved    int64  `json:"reserved"`
+	Available   int64  `json:"available"`
+}
+
+func StockLevelModelToApi(l model.StockLevel) *StockLevel {
+	return &StockLevel{
+		WarehouseId: l.WarehouseId,
+		Carpet:      *CarpetModelToApi(l.Carpet),
+		OnHand:      l.OnHand,
+		Reserved:    l.Reserved,
+		Available:   l.Available,
+	}
+}
+
+type (
+	CreateWarehouseRequest struct {
+		Warehouse
+	}
+
+	CreateWarehouseResponse struct {
+		Warehouse
+	}
+)
+
+type GetWarehousesResponse struct {
+	Warehouses []Warehouse `json:"warehouses"`
+}
+
+type DeleteWarehouseRequest struct {
+	CompanyId   uint `json:"company_id"`
+	WarehouseId uint `json:"warehouse_id"`
+}
+
+type (
+	RecordStockMovementRequest struct {
+		StockMovement
+	}
+
+	// RecordStockMovementResponse has ledger entries of movement, a transfer has an entry for each warehouse
+	RecordStockMovementResponse struct {
+		Movements []StockMovement `json:"movements"`
+	}
+)
+
+type (
+	// GetStockMovementsRequest filter stock ledger of a company, zero fields match everything
+	GetStockMovementsRequest struct {
+		CompanyId   uint   `json:"company_id"`
+		WarehouseId uint   `json:"warehouse_id"`
+		CarpetId    string `json:"carpet_id"`
+		ProductId   uint   `json:"product_id"`
+		Kind        string `json:"kind"`
+		PageRequest
+	}
+
+	GetStockMovementsResponse struct {
+		Movements []StockMovement `json:"movements"`
+		Total     int64           `json:"total"`
+		Page      int             `json:"page"`
+		PageSize  int             `json:"page_size"`
+	}
+)
+
+type (
+	// GetStockRequest list quantities of carpets per warehouse, zero WarehouseId and ProductId match everything
+	GetStockRequest struct {
+		CompanyId   uint `json:"company_id"`
+		WarehouseId uint `json:"warehouse_id"`
+		ProductId   uint `json:"product_id"`
+		PageRequest
+	}
+
+	GetStockResponse struct {
+		Stock    []StockLevel `json:"stock"`
+		Total    int64        `json:"total"`
+		Page     int          `json:"page"`
+		PageSize int          `json:"page_size"`
+	}
+)
+
+type (
+	ReserveStockRequest struct {
+		Reservation
+	}
+
+	ReserveStockResponse struct {
+		Reservation
+	}
+)
+
+type (
+	ReleaseReservationRequest struct {
+		CompanyId     uint `json:"company_id"`
+		ReservationId uint `json:"reservation_id"`
+	}
+
+	ReleaseReservationResponse struct {
+		Reservation
+	}
+)
+
+type (
+	// GetReservationsRequest filter reservations of a company, zero fields match everything
+	GetReservationsRequest struct {
+		CompanyId   uint   `json:"company_id"`
+		WarehouseId uint   `json:"warehouse_id"`
+		ProductId   uint   `json:"product_id"`
+		Holder      string `json:"holder"`
+		ActiveOnly  bool   `json:"active_only"`
+		PageRequest
+	}
+
+	GetReservationsResponse struct {
+		Reservations []Reservation `json:"reservations"`
+		Total        int64         `json:"total"`
+		Page         int           `json:"page"`
+		PageSize     int           `json:"page_size"`
+	}
+)
@@ -0,0 +1,26 @@
+package api
+
+import "github.com/seed95/product-service/internal/model"
+
+type TagUsage struct {
+	Name     string `json:"name"`
+	Products int64  `json:"products"` // Number of products that have the tag
+}
+
+func TagUsageModelToApi(t model.TagUsage) *TagUsage {
+	return &TagUsage{
+		Name:     t.Name,
+		Products: t.Products,
+	}
+}
+
+// TagProductsRequest is payload of add and remove tags of one or many products
+type TagProductsRequest struct {
+	CompanyId  uint     `json:"company_id"`
+	ProductIds []uint   `json:"product_ids"`
+	Tags       []string `json:"tags"`
+}
+
+type GetTagsResponse struct {
+	Tags []TagUsage `json:"tags"`
+}
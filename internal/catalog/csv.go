@@ -0,0 +1,174 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/model"
+	"io"
+	"strings"
+)
+
+// Columns of a product csv, design code, sizes and colors are required and columns may be in any order
+const (
+	ColumnDesignCode  = "design_code"
+	ColumnDescription = "description"
+	ColumnSizes       = "sizes"
+	ColumnColors      = "colors"
+	ColumnAttributes  = "attributes"
+)
+
+// Separators of a cell, sizes and colors are a list (6|9) and attributes are a list of name=value (material=پشم|knots=1200)
+const (
+	ListSeparator      = "|"
+	AttributeSeparator = "="
+)
+
+var (
+	ErrEmptyFile       = errors.New("empty file")
+	ErrMissingColumn   = errors.New("missing column")
+	ErrUnknownColumn   = errors.New("unknown column")
+	ErrDuplicateColumn = errors.New("duplicate column")
+	ErrInvalidCell     = errors.New("invalid cell")
+)
+
+var columns = map[string]bool{
+	ColumnDesignCode:  true,
+	ColumnDescription: false,
+	ColumnSizes:       true,
+	ColumnColors:      true,
+	ColumnAttributes:  false,
+}
+
+// Row is a product of a line of a csv, a row that can't be read has Err
+type Row struct {
+	Line    int
+	Product model.Product
+	Err     error
+}
+
+// ReadProducts read products of a csv with a header line and a row per design.
+// an error of a row is in its Row, an error of header or of csv syntax stops reading
+func ReadProducts(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrEmptyFile
+	} else if err != nil {
+		return nil, err
+	}
+
+	index, err := headerIndex(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, err
+		}
+
+		row := Row{Line: line}
+		if err != nil {
+			row.Err = fmt.Errorf("%w: %v fields, header has %v", ErrInvalidCell, len(record), len(header))
+		} else {
+			row.Product, row.Err = recordProduct(record, index)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// headerIndex return index of columns in `header`, names are case insensitive
+func headerIndex(header []string) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheet exports may start with a byte order mark
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\uFEFF")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: %v", ErrUnknownColumn, name)
+		}
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicateColumn, name)
+		}
+		index[name] = i
+	}
+
+	for name, required := range columns {
+		if _, ok := index[name]; required && !ok {
+			return nil, fmt.Errorf("%w: %v", ErrMissingColumn, name)
+		}
+	}
+
+	return index, nil
+}
+
+func recordProduct(record []string, index map[string]int) (model.Product, error) {
+	cell := func(name string) string {
+		if i, ok := index[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	product := model.Product{
+		DesignCode:  cell(ColumnDesignCode),
+		Description: cell(ColumnDescription),
+		Sizes:       splitList(cell(ColumnSizes)),
+		Colors:      splitList(cell(ColumnColors)),
+	}
+
+	attributes, err := splitAttributes(cell(ColumnAttributes))
+	if err != nil {
+		return model.Product{}, err
+	}
+	product.Attributes = attributes
+
+	return product, nil
+}
+
+// splitList return items of a list cell, an empty cell is an empty list
+func splitList(cell string) []string {
+	if cell == "" {
+		return nil
+	}
+	items := strings.Split(cell, ListSeparator)
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
+
+func splitAttributes(cell string) (map[string]string, error) {
+	items := splitList(cell)
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	attributes := make(map[string]string, len(items))
+	for _, item := range items {
+		name, value, ok := strings.Cut(item, AttributeSeparator)
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: attribute %q is not name%vvalue", ErrInvalidCell, item, AttributeSeparator)
+		}
+		if _, ok := attributes[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate attribute %v", ErrInvalidCell, name)
+		}
+		attributes[name] = value
+	}
+	return attributes, nil
+}
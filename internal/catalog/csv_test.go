@@ -0,0 +1,73 @@
+package catalog
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestReadProducts(t *testing.T) {
+	file := "\uFEFFDesign_Code,sizes,colors,description,attributes\n" +
+		"105,6|9,قرمز|آبی,توضیحات ۱۰۵,material=پشم|knots=1200\n" +
+		"\n" +
+		"106, 6 ,قرمز,,\n" +
+		"107,6,قرمز\n" +
+		"108,6,قرمز,,material\n"
+
+	rows, err := ReadProducts(strings.NewReader(file))
+	require.Nil(t, err)
+	require.Equal(t, 4, len(rows))
+
+	require.Nil(t, rows[0].Err)
+	require.Equal(t, 2, rows[0].Line)
+	require.Equal(t, "105", rows[0].Product.DesignCode)
+	require.Equal(t, []string{"6", "9"}, rows[0].Product.Sizes)
+	require.Equal(t, []string{"قرمز", "آبی"}, rows[0].Product.Colors)
+	require.Equal(t, map[string]string{"material": "پشم", "knots": "1200"}, rows[0].Product.Attributes)
+
+	require.Nil(t, rows[1].Err)
+	require.Equal(t, 4, rows[1].Line)
+	require.Equal(t, []string{"6"}, rows[1].Product.Sizes)
+	require.Nil(t, rows[1].Product.Attributes)
+
+	require.True(t, errors.Is(rows[2].Err, ErrInvalidCell))
+	require.True(t, errors.Is(rows[3].Err, ErrInvalidCell))
+}
+
+func TestReadProducts_Header(t *testing.T) {
+
+	tests := []struct {
+		Name string
+		File string
+		Err  error
+	}{
+		{
+			Name: "Empty",
+			File: "",
+			Err:  ErrEmptyFile,
+		},
+		{
+			Name: "MissingColumn",
+			File: "design_code,sizes\n105,6\n",
+			Err:  ErrMissingColumn,
+		},
+		{
+			Name: "UnknownColumn",
+			File: "design_code,sizes,colors,price\n105,6,قرمز,1000\n",
+			Err:  ErrUnknownColumn,
+		},
+		{
+			Name: "DuplicateColumn",
+			File: "design_code,sizes,colors,sizes\n105,6,قرمز,9\n",
+			Err:  ErrDuplicateColumn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			_, err := ReadProducts(strings.NewReader(tt.File))
+			require.True(t, errors.Is(err, tt.Err), err)
+		})
+	}
+}
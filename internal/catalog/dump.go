@@ -0,0 +1,174 @@
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/model"
+	"io"
+	"reflect"
+	"time"
+)
+
+var (
+	ErrInvalidDump  = errors.New("invalid dump")
+	ErrDumpVersion  = errors.New("unsupported dump version")
+	ErrDumpChecksum = errors.New("checksum mismatch")
+)
+
+// dumpFile is json document of a dump. rows of a section have a sha256 checksum of their canonical json
+// and Checksum of the file covers header and checksums of sections
+type dumpFile struct {
+	Format    string        `json:"format"`
+	Version   int           `json:"version"`
+	CompanyId uint          `json:"company_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Sections  []dumpSection `json:"sections"`
+	Checksum  string        `json:"checksum"`
+}
+
+type dumpSection struct {
+	Name     string          `json:"name"`
+	Count    int             `json:"count"`
+	Checksum string          `json:"checksum"`
+	Rows     json.RawMessage `json:"rows"`
+}
+
+// sectionRows point to rows of a section in a dump, version is the dump version that added the section
+type sectionRows struct {
+	name    string
+	rows    interface{}
+	version int
+}
+
+// dumpSections return pointer to rows of each section of `dump` in order of restore
+func dumpSections(dump *model.CatalogDump) []sectionRows {
+	return []sectionRows{
+		{model.DumpStandardSizes, &dump.StandardSizes, 1},
+		{model.DumpAttributeDefinitions, &dump.AttributeDefinitions, 1},
+		{model.DumpCategories, &dump.Categories, 1},
+		{model.DumpTags, &dump.Tags, 1},
+		{model.DumpProducts, &dump.Products, 1},
+		{model.DumpDimensions, &dump.Dimensions, 1},
+		{model.DumpThemes, &dump.Themes, 1},
+		{model.DumpProductAttributes, &dump.ProductAttributes, 1},
+		{model.DumpProductCategories, &dump.ProductCategories, 1},
+		{model.DumpProductTags, &dump.ProductTags, 1},
+		{model.DumpCarpetExclusions, &dump.CarpetExclusions, 1},
+		{model.DumpPriceLists, &dump.PriceLists, 1},
+		{model.DumpPrices, &dump.Prices, 1},
+		{model.DumpPriceAdjustments, &dump.PriceAdjustments, 1},
+		{model.DumpProductAliases, &dump.ProductAliases, 2},
+	}
+}
+
+// WriteDump write `dump` as a json document with checksums
+func WriteDump(w io.Writer, dump model.CatalogDump) error {
+	file := dumpFile{
+		Format:    model.DumpFormat,
+		Version:   model.DumpVersion,
+		CompanyId: dump.CompanyId,
+		CreatedAt: dump.CreatedAt.UTC(),
+	}
+
+	for _, s := range dumpSections(&dump) {
+		// An empty section is an empty list
+		if rows := reflect.ValueOf(s.rows).Elem(); rows.IsNil() {
+			rows.Set(reflect.MakeSlice(rows.Type(), 0, 0))
+		}
+
+		rows, checksum, err := canonicalRows(s.rows)
+		if err != nil {
+			return err
+		}
+		file.Sections = append(file.Sections, dumpSection{
+			Name:     s.name,
+			Count:    reflect.ValueOf(s.rows).Elem().Len(),
+			Checksum: checksum,
+			Rows:     rows,
+		})
+	}
+	file.Checksum = fileChecksum(file)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(file)
+}
+
+// ReadDump read a dump written by WriteDump, every checksum and count is verified
+func ReadDump(r io.Reader) (*model.CatalogDump, error) {
+	var file dumpFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDump, err)
+	}
+
+	if file.Format != model.DumpFormat {
+		return nil, fmt.Errorf("%w: format %q", ErrInvalidDump, file.Format)
+	}
+	if file.Version < 1 || file.Version > model.DumpVersion {
+		return nil, fmt.Errorf("%w: %v", ErrDumpVersion, file.Version)
+	}
+	if file.Checksum != fileChecksum(file) {
+		return nil, fmt.Errorf("%w: file", ErrDumpChecksum)
+	}
+
+	// A dump of an older version lacks sections added after it, they are restored empty
+	dump := &model.CatalogDump{CompanyId: file.CompanyId, CreatedAt: file.CreatedAt}
+	var sections []sectionRows
+	for _, s := range dumpSections(dump) {
+		if s.version > file.Version {
+			rows := reflect.ValueOf(s.rows).Elem()
+			rows.Set(reflect.MakeSlice(rows.Type(), 0, 0))
+			continue
+		}
+		sections = append(sections, s)
+	}
+	if len(file.Sections) != len(sections) {
+		return nil, fmt.Errorf("%w: %v sections, expected %v", ErrInvalidDump, len(file.Sections), len(sections))
+	}
+
+	for i, s := range sections {
+		section := file.Sections[i]
+		if section.Name != s.name {
+			return nil, fmt.Errorf("%w: section %q, expected %q", ErrInvalidDump, section.Name, s.name)
+		}
+
+		if err := json.Unmarshal(section.Rows, s.rows); err != nil {
+			return nil, fmt.Errorf("%w: section %v: %v", ErrInvalidDump, s.name, err)
+		}
+
+		_, checksum, err := canonicalRows(s.rows)
+		if err != nil {
+			return nil, err
+		}
+		if checksum != section.Checksum {
+			return nil, fmt.Errorf("%w: section %v", ErrDumpChecksum, s.name)
+		}
+		if count := reflect.ValueOf(s.rows).Elem().Len(); count != section.Count {
+			return nil, fmt.Errorf("%w: section %v has %v rows, expected %v", ErrDumpChecksum, s.name, count, section.Count)
+		}
+	}
+
+	return dump, nil
+}
+
+// canonicalRows return compact json of `rows` and its checksum, formatting of a file doesn't change checksum
+func canonicalRows(rows interface{}) ([]byte, string, error) {
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(data)
+	return data, hex.EncodeToString(sum[:]), nil
+}
+
+func fileChecksum(file dumpFile) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%v\n%v\n%v\n%v\n", file.Format, file.Version, file.CompanyId, file.CreatedAt.UTC().Format(time.RFC3339Nano))
+	for _, s := range file.Sections {
+		fmt.Fprintf(hash, "%v %v %v\n", s.Name, s.Count, s.Checksum)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,119 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testDump() model.CatalogDump {
+	parentId := uint(3)
+	return model.CatalogDump{
+		CompanyId:  1,
+		CreatedAt:  time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC),
+		Categories: []model.DumpCategory{{Id: 3, Name: "کلاسیک", Kind: "category"}, {Id: 4, ParentId: &parentId, Name: "افشان", Kind: "category"}},
+		Products:   []model.DumpProduct{{Id: 7, DesignCode: "105", Status: model.StatusPublished}},
+		Dimensions: []model.DumpDimension{{Id: 11, ProductId: 7, Size: "6"}},
+		Themes:     []model.DumpTheme{{Id: 12, ProductId: 7, Color: "قرمز"}},
+		AttributeDefinitions: []model.DumpAttributeDefinition{
+			{Id: 2, Name: "material", Type: "enum", Options: []string{"wool", "silk"}},
+		},
+	}
+}
+
+func TestDumpRoundTrip(t *testing.T) {
+	dump := testDump()
+
+	var buf bytes.Buffer
+	require.Nil(t, WriteDump(&buf, dump))
+
+	read, err := ReadDump(&buf)
+	require.Nil(t, err)
+	require.Equal(t, dump.CompanyId, read.CompanyId)
+	require.True(t, dump.CreatedAt.Equal(read.CreatedAt))
+	require.Equal(t, dump.Categories, read.Categories)
+	require.Equal(t, dump.Products, read.Products)
+	require.Equal(t, dump.AttributeDefinitions, read.AttributeDefinitions)
+	require.NotNil(t, read.Prices)
+	require.Equal(t, 0, len(read.Prices))
+}
+
+func TestReadDump(t *testing.T) {
+	var buf bytes.Buffer
+	require.Nil(t, WriteDump(&buf, testDump()))
+	data := buf.String()
+
+	tests := []struct {
+		Name string
+		Data string
+		Err  error
+	}{
+		{
+			Name: "TamperedRow",
+			Data: strings.Replace(data, `"105"`, `"106"`, 1),
+			Err:  ErrDumpChecksum,
+		},
+		{
+			Name: "NewerVersion",
+			Data: strings.Replace(data, `"version": 2`, `"version": 3`, 1),
+			Err:  ErrDumpVersion,
+		},
+		{
+			Name: "OtherFormat",
+			Data: strings.Replace(data, model.DumpFormat, "other", 1),
+			Err:  ErrInvalidDump,
+		},
+		{
+			Name: "NotJson",
+			Data: "design_code\n105\n",
+			Err:  ErrInvalidDump,
+		},
+	}
+
+	// Without product_aliases only a version 1 dump is valid
+	var file dumpFile
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &file))
+	file.Sections = file.Sections[:len(file.Sections)-1]
+	file.Checksum = fileChecksum(file)
+	withoutAliases, err := json.Marshal(file)
+	require.Nil(t, err)
+	tests = append(tests, struct {
+		Name string
+		Data string
+		Err  error
+	}{Name: "MissingSection", Data: string(withoutAliases), Err: ErrInvalidDump})
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			require.NotEqual(t, data, test.Data)
+			_, err := ReadDump(strings.NewReader(test.Data))
+			require.True(t, errors.Is(err, test.Err), err)
+		})
+	}
+}
+
+func TestReadDump_Version1(t *testing.T) {
+	var buf bytes.Buffer
+	require.Nil(t, WriteDump(&buf, testDump()))
+
+	// A version 1 dump has no product_aliases section
+	var file dumpFile
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &file))
+	require.Equal(t, model.DumpProductAliases, file.Sections[len(file.Sections)-1].Name)
+	file.Version = 1
+	file.Sections = file.Sections[:len(file.Sections)-1]
+	file.Checksum = fileChecksum(file)
+	data, err := json.Marshal(file)
+	require.Nil(t, err)
+
+	read, err := ReadDump(bytes.NewReader(data))
+	require.Nil(t, err)
+	require.Equal(t, testDump().Products, read.Products)
+	require.NotNil(t, read.ProductAliases)
+	require.Equal(t, 0, len(read.ProductAliases))
+}
@@ -0,0 +1,261 @@
+package catalog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/model"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Columns of an export that are not columns of an import
+const (
+	ColumnId                  = "id"
+	ColumnProductId           = "product_id"
+	ColumnStatus              = "status"
+	ColumnCategories          = "categories"
+	ColumnTags                = "tags"
+	ColumnSize                = "size"
+	ColumnColor               = "color"
+	ColumnOnHand              = "on_hand"
+	ColumnAvailable           = "available"
+	ColumnPrice               = "price"
+	ColumnPriceSource         = "price_source"
+	ColumnPricePerSquareMeter = "price_per_square_meter"
+	ColumnCurrency            = "currency"
+)
+
+// ProductColumns are columns of a product export in default order
+var ProductColumns = []string{
+	ColumnId, ColumnDesignCode, ColumnDescription, ColumnStatus, ColumnSizes, ColumnColors, ColumnAttributes,
+	ColumnCategories, ColumnTags, ColumnOnHand, ColumnAvailable, ColumnPricePerSquareMeter, ColumnCurrency,
+}
+
+// CarpetColumns are columns of a carpet export in default order, id is sku of carpet
+var CarpetColumns = []string{
+	ColumnId, ColumnProductId, ColumnDesignCode, ColumnSize, ColumnColor, ColumnOnHand, ColumnAvailable,
+	ColumnPrice, ColumnPriceSource, ColumnCurrency,
+}
+
+// priceColumns need a price list
+var priceColumns = map[string]bool{
+	ColumnPrice:               true,
+	ColumnPriceSource:         true,
+	ColumnPricePerSquareMeter: true,
+	ColumnCurrency:            true,
+}
+
+var (
+	ErrInvalidKind   = errors.New("invalid kind")
+	ErrInvalidFormat = errors.New("invalid format")
+	ErrPriceColumn   = errors.New("price column without price list")
+)
+
+// ExportColumns return `selected` columns of an export of `kind`, or all columns if none is selected.
+// price columns are in all columns only `withPrices`, selecting them without prices is an error
+func ExportColumns(kind string, selected []string, withPrices bool) ([]string, error) {
+	var all []string
+	switch kind {
+	case model.ExportProducts:
+		all = ProductColumns
+	case model.ExportCarpets:
+		all = CarpetColumns
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKind, kind)
+	}
+
+	if len(selected) == 0 {
+		var columns []string
+		for _, c := range all {
+			if withPrices || !priceColumns[c] {
+				columns = append(columns, c)
+			}
+		}
+		return columns, nil
+	}
+
+	known := make(map[string]bool, len(all))
+	for _, c := range all {
+		known[c] = true
+	}
+
+	columns := make([]string, len(selected))
+	seen := make(map[string]bool, len(selected))
+	for i, c := range selected {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if !known[c] {
+			return nil, fmt.Errorf("%w: %v", ErrUnknownColumn, c)
+		}
+		if seen[c] {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicateColumn, c)
+		}
+		if priceColumns[c] && !withPrices {
+			return nil, fmt.Errorf("%w: %v", ErrPriceColumn, c)
+		}
+		seen[c] = true
+		columns[i] = c
+	}
+	return columns, nil
+}
+
+// ProductValues return values of export columns of a product
+func ProductValues(p model.ExportProduct) map[string]interface{} {
+	return map[string]interface{}{
+		ColumnId:                  p.Id,
+		ColumnDesignCode:          p.DesignCode,
+		ColumnDescription:         p.Description,
+		ColumnStatus:              p.Status,
+		ColumnSizes:               p.Sizes,
+		ColumnColors:              p.Colors,
+		ColumnAttributes:          p.Attributes,
+		ColumnCategories:          p.Categories,
+		ColumnTags:                p.Tags,
+		ColumnOnHand:              p.OnHand,
+		ColumnAvailable:           p.Available,
+		ColumnPricePerSquareMeter: p.PricePerSquareMeter,
+		ColumnCurrency:            p.Currency,
+	}
+}
+
+// CarpetValues return values of export columns of a carpet
+func CarpetValues(c model.ExportCarpet) map[string]interface{} {
+	return map[string]interface{}{
+		ColumnId:          c.Id,
+		ColumnProductId:   c.ProductId,
+		ColumnDesignCode:  c.DesignCode,
+		ColumnSize:        c.Dimension,
+		ColumnColor:       c.Color,
+		ColumnOnHand:      c.OnHand,
+		ColumnAvailable:   c.Available,
+		ColumnPrice:       c.Price,
+		ColumnPriceSource: c.PriceSource,
+		ColumnCurrency:    c.Currency,
+	}
+}
+
+// Writer write rows of an export, Flush should be called after last row
+type Writer interface {
+	Write(values map[string]interface{}) error
+	Flush() error
+}
+
+// NewWriter return a writer of `columns` in `format`, a csv starts with a header line if `header` is set.
+// a continued export is written without header so its parts concatenate into one file
+func NewWriter(w io.Writer, format string, columns []string, header bool) (Writer, error) {
+	switch format {
+	case model.ExportCSV:
+		writer := &csvWriter{writer: csv.NewWriter(w), columns: columns}
+		if !header {
+			return writer, nil
+		}
+		if err := writer.writer.Write(columns); err != nil {
+			return nil, err
+		}
+		return writer, nil
+	case model.ExportJSONL:
+		return &jsonlWriter{writer: bufio.NewWriter(w), columns: columns}, nil
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, format)
+	}
+}
+
+// ContentType return media type of an export `format`, csv is default
+func ContentType(format string) string {
+	if format == model.ExportJSONL {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// csvWriter write a line per row, lists are separated like an import
+type csvWriter struct {
+	writer  *csv.Writer
+	columns []string
+}
+
+func (w *csvWriter) Write(values map[string]interface{}) error {
+	record := make([]string, len(w.columns))
+	for i, c := range w.columns {
+		record[i] = csvCell(values[c])
+	}
+	return w.writer.Write(record)
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case *int64:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatInt(*v, 10)
+	case []string:
+		return strings.Join(v, ListSeparator)
+	case []uint:
+		items := make([]string, len(v))
+		for i := range v {
+			items[i] = strconv.FormatUint(uint64(v[i]), 10)
+		}
+		return strings.Join(items, ListSeparator)
+	case map[string]string:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		items := make([]string, len(names))
+		for i, name := range names {
+			items[i] = name + AttributeSeparator + v[name]
+		}
+		return strings.Join(items, ListSeparator)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// jsonlWriter write a json object per row with keys in order of columns
+type jsonlWriter struct {
+	writer  *bufio.Writer
+	columns []string
+}
+
+func (w *jsonlWriter) Write(values map[string]interface{}) error {
+	w.writer.WriteByte('{')
+	for i, c := range w.columns {
+		if i != 0 {
+			w.writer.WriteByte(',')
+		}
+		key, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(values[c])
+		if err != nil {
+			return err
+		}
+		w.writer.Write(key)
+		w.writer.WriteByte(':')
+		w.writer.Write(value)
+	}
+	w.writer.WriteByte('}')
+	return w.writer.WriteByte('\n')
+}
+
+func (w *jsonlWriter) Flush() error {
+	return w.writer.Flush()
+}
@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestExportColumns(t *testing.T) {
+
+	tests := []struct {
+		Name       string
+		Kind       string
+		Selected   []string
+		WithPrices bool
+		Columns    []string
+		Err        error
+	}{
+		{
+			Name:    "AllCarpetColumnsWithoutPrices",
+			Kind:    model.ExportCarpets,
+			Columns: []string{ColumnId, ColumnProductId, ColumnDesignCode, ColumnSize, ColumnColor, ColumnOnHand, ColumnAvailable},
+		},
+		{
+			Name:       "AllCarpetColumnsWithPrices",
+			Kind:       model.ExportCarpets,
+			WithPrices: true,
+			Columns:    CarpetColumns,
+		},
+		{
+			Name:     "Selected",
+			Kind:     model.ExportProducts,
+			Selected: []string{"Design_Code", " on_hand"},
+			Columns:  []string{ColumnDesignCode, ColumnOnHand},
+		},
+		{
+			Name:     "UnknownColumn",
+			Kind:     model.ExportProducts,
+			Selected: []string{ColumnSize},
+			Err:      ErrUnknownColumn,
+		},
+		{
+			Name:     "DuplicateColumn",
+			Kind:     model.ExportProducts,
+			Selected: []string{ColumnId, ColumnId},
+			Err:      ErrDuplicateColumn,
+		},
+		{
+			Name:     "PriceWithoutPriceList",
+			Kind:     model.ExportCarpets,
+			Selected: []string{ColumnId, ColumnPrice},
+			Err:      ErrPriceColumn,
+		},
+		{
+			Name: "InvalidKind",
+			Kind: "prices",
+			Err:  ErrInvalidKind,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			columns, err := ExportColumns(tt.Kind, tt.Selected, tt.WithPrices)
+			if tt.Err != nil {
+				require.True(t, errors.Is(err, tt.Err), err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.Columns, columns)
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	amount := int64(1500000)
+	product := model.ExportProduct{
+		Id:                  12,
+		DesignCode:          "105",
+		Sizes:               []string{"6", "9"},
+		Colors:              []string{"قرمز"},
+		Attributes:          map[string]string{"material": "پشم", "knots": "1200"},
+		Categories:          []uint{3, 4},
+		OnHand:              7,
+		PricePerSquareMeter: &amount,
+	}
+	columns := []string{ColumnId, ColumnDesignCode, ColumnSizes, ColumnAttributes, ColumnCategories, ColumnOnHand, ColumnPricePerSquareMeter, ColumnDescription}
+
+	var csv bytes.Buffer
+	writer, err := NewWriter(&csv, model.ExportCSV, columns, true)
+	require.Nil(t, err)
+	require.Nil(t, writer.Write(ProductValues(product)))
+	product.PricePerSquareMeter = nil
+	require.Nil(t, writer.Write(ProductValues(product)))
+	require.Nil(t, writer.Flush())
+	require.Equal(t, "id,design_code,sizes,attributes,categories,on_hand,price_per_square_meter,description\n"+
+		"12,105,6|9,knots=1200|material=پشم,3|4,7,1500000,\n"+
+		"12,105,6|9,knots=1200|material=پشم,3|4,7,,\n", csv.String())
+
+	var jsonl bytes.Buffer
+	writer, err = NewWriter(&jsonl, model.ExportJSONL, columns, true)
+	require.Nil(t, err)
+	require.Nil(t, writer.Write(ProductValues(product)))
+	require.Nil(t, writer.Flush())
+	require.Equal(t, `{"id":12,"design_code":"105","sizes":["6","9"],"attributes":{"knots":"1200","material":"پشم"},"categories":[3,4],"on_hand":7,"price_per_square_meter":null,"description":""}`+"\n",
+		jsonl.String())
+
+	_, err = NewWriter(&jsonl, "xml", columns, true)
+	require.True(t, errors.Is(err, ErrInvalidFormat))
+}
+
+func TestWriter_ContinuedExport(t *testing.T) {
+	columns := []string{ColumnId, ColumnDesignCode}
+
+	// First part has header, continued part doesn't
+	var export bytes.Buffer
+	for i, designCode := range []string{"105", "106"} {
+		var part bytes.Buffer
+		writer, err := NewWriter(&part, model.ExportCSV, columns, i == 0)
+		require.Nil(t, err)
+		require.Nil(t, writer.Write(ProductValues(model.ExportProduct{Id: uint(i + 1), DesignCode: designCode})))
+		require.Nil(t, writer.Flush())
+		export.Write(part.Bytes())
+	}
+
+	records, err := csv.NewReader(&export).ReadAll()
+	require.Nil(t, err)
+	require.Equal(t, [][]string{{"id", "design_code"}, {"1", "105"}, {"2", "106"}}, records)
+}
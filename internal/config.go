@@ -18,6 +18,7 @@ type Config struct {
 	ServiceTimeout time.Duration
 	ProductRepo    PostgresConfig
 	GRPCPort       string
+	SweepInterval  time.Duration // Time between releases of expired reservations
 }
 
 func NewConfig(prefix string) *Config {
@@ -33,6 +34,7 @@ func NewConfig(prefix string) *Config {
 		ProductRepo: PostgresConfig{
 			DSN: v.GetString("postgres_dsn"),
 		},
-		GRPCPort: v.GetString("grpc_port"),
+		GRPCPort:      v.GetString("grpc_port"),
+		SweepInterval: v.GetDuration("sweep_interval"),
 	}
 }
@@ -57,6 +57,46 @@ var (
 		message: "dimension_not_found",
 		code:    codes.NotFound,
 	}
+	StandardSizeNotFound = serviceError{
+		message: "standard_size_not_found",
+		code:    codes.NotFound,
+	}
+	AttributeNotFound = serviceError{
+		message: "attribute_not_found",
+		code:    codes.NotFound,
+	}
+	CategoryNotFound = serviceError{
+		message: "category_not_found",
+		code:    codes.NotFound,
+	}
+	CarpetNotFound = serviceError{
+		message: "carpet_not_found",
+		code:    codes.NotFound,
+	}
+	PriceListNotFound = serviceError{
+		message: "price_list_not_found",
+		code:    codes.NotFound,
+	}
+	PriceNotFound = serviceError{
+		message: "price_not_found",
+		code:    codes.NotFound,
+	}
+	PriceAdjustmentNotFound = serviceError{
+		message: "price_adjustment_not_found",
+		code:    codes.NotFound,
+	}
+	WarehouseNotFound = serviceError{
+		message: "warehouse_not_found",
+		code:    codes.NotFound,
+	}
+	ReservationNotFound = serviceError{
+		message: "reservation_not_found",
+		code:    codes.NotFound,
+	}
+	ExclusionNotFound = serviceError{
+		message: "exclusion_not_found",
+		code:    codes.NotFound,
+	}
 
 	InvalidColor = serviceError{
 		message: "invalid_color",
@@ -78,6 +118,116 @@ var (
 		message: "invalid_company",
 		code:    codes.InvalidArgument,
 	}
+	InvalidStandardSize = serviceError{
+		message: "invalid_standard_size",
+		code:    codes.InvalidArgument,
+	}
+	InvalidAttribute = serviceError{
+		message: "invalid_attribute",
+		code:    codes.InvalidArgument,
+	}
+	InvalidCategory = serviceError{
+		message: "invalid_category",
+		code:    codes.InvalidArgument,
+	}
+	InvalidTag = serviceError{
+		message: "invalid_tag",
+		code:    codes.InvalidArgument,
+	}
+	InvalidCarpet = serviceError{
+		message: "invalid_carpet",
+		code:    codes.InvalidArgument,
+	}
+	InvalidLabel = serviceError{
+		message: "invalid_label",
+		code:    codes.InvalidArgument,
+	}
+	InvalidStatus = serviceError{
+		message: "invalid_status",
+		code:    codes.InvalidArgument,
+	}
+	InvalidPriceList = serviceError{
+		message: "invalid_price_list",
+		code:    codes.InvalidArgument,
+	}
+	InvalidPrice = serviceError{
+		message: "invalid_price",
+		code:    codes.InvalidArgument,
+	}
+	InvalidWarehouse = serviceError{
+		message: "invalid_warehouse",
+		code:    codes.InvalidArgument,
+	}
+	InvalidStockMovement = serviceError{
+		message: "invalid_stock_movement",
+		code:    codes.InvalidArgument,
+	}
+	InvalidReservation = serviceError{
+		message: "invalid_reservation",
+		code:    codes.InvalidArgument,
+	}
+	InvalidImport = serviceError{
+		message: "invalid_import",
+		code:    codes.InvalidArgument,
+	}
+	InvalidExport = serviceError{
+		message: "invalid_export",
+		code:    codes.InvalidArgument,
+	}
+	InvalidDump = serviceError{
+		message: "invalid_dump",
+		code:    codes.InvalidArgument,
+	}
+
+	StandardSizeInUse = serviceError{
+		message: "standard_size_in_use",
+		code:    codes.FailedPrecondition,
+	}
+	AttributeInUse = serviceError{
+		message: "attribute_in_use",
+		code:    codes.FailedPrecondition,
+	}
+	CategoryNotEmpty = serviceError{
+		message: "category_not_empty",
+		code:    codes.FailedPrecondition,
+	}
+	StatusTransitionNotAllowed = serviceError{
+		message: "status_transition_not_allowed",
+		code:    codes.FailedPrecondition,
+	}
+	ProductDiscontinued = serviceError{
+		message: "product_discontinued",
+		code:    codes.FailedPrecondition,
+	}
+	InsufficientStock = serviceError{
+		message: "insufficient_stock",
+		code:    codes.FailedPrecondition,
+	}
+	WarehouseNotEmpty = serviceError{
+		message: "warehouse_not_empty",
+		code:    codes.FailedPrecondition,
+	}
+	ReservationNotActive = serviceError{
+		message: "reservation_not_active",
+		code:    codes.FailedPrecondition,
+	}
+	CarpetExcluded = serviceError{
+		message: "carpet_excluded",
+		code:    codes.FailedPrecondition,
+	}
+	CarpetInStock = serviceError{
+		message: "carpet_in_stock",
+		code:    codes.FailedPrecondition,
+	}
+	CatalogNotEmpty = serviceError{
+		message: "catalog_not_empty",
+		code:    codes.FailedPrecondition,
+	}
+
+	ProductExists = serviceError{
+		message: "product_exists",
+		code:    codes.AlreadyExists,
+	}
 )
 
 // Create error message formats
@@ -106,6 +256,18 @@ func (se serviceError) Error() string {
 	return fmt.Sprintf("(StatusCode:%d) %s", se.code, se.message)
 }
 
+// Wrap return `err` if it is a service error, otherwise an internal server error describing `err`
+func Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	ce := serviceError{}
+	if errors.As(err, &ce) {
+		return err
+	}
+	return New(InternalServer, err.Error())
+}
+
 func StatusCode(err error) int {
 	ce := serviceError{}
 	if errors.As(err, &ce) {
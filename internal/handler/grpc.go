@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/seed95/product-service/internal"
 	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/catalog"
 	"github.com/seed95/product-service/internal/derror"
 	"github.com/seed95/product-service/internal/service"
 	"github.com/seed95/product-service/pkg/logger"
@@ -13,13 +15,88 @@ import (
 	"github.com/seed95/product-service/pkg/proto/micro"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"strings"
 	"time"
 )
 
 // OpCodes
 const (
-	NewProductOpCode = 1
+	NewProductOpCode          = 1
+	ChangeProductStatusOpCode = 2
+	ImportProductsOpCode      = 3
+	ExportCatalogOpCode       = 4
+	DumpCatalogOpCode         = 5
+	RestoreCatalogOpCode      = 6
+	CloneProductOpCode        = 7
+	BulkEditProductsOpCode    = 8
+
+	NewStandardSizeOpCode         = 10
+	GetStandardSizesOpCode        = 11
+	EditStandardSizeOpCode        = 12
+	DeleteStandardSizeOpCode      = 13
+	GetStandardSizeProductsOpCode = 14
+
+	NewAttributeDefinitionOpCode    = 20
+	GetAttributeDefinitionsOpCode   = 21
+	EditAttributeDefinitionOpCode   = 22
+	DeleteAttributeDefinitionOpCode = 23
+	SearchProductsOpCode            = 24
+
+	NewCategoryOpCode         = 30
+	GetCategoriesOpCode       = 31
+	MoveCategoryOpCode        = 32
+	DeleteCategoryOpCode      = 33
+	GetCategoryProductsOpCode = 34
+
+	AddTagsOpCode    = 40
+	RemoveTagsOpCode = 41
+	GetTagsOpCode    = 42
+
+	GetCarpetsOpCode          = 50
+	GetProductCarpetsOpCode   = 51
+	GetCarpetOpCode           = 52
+	GetCarpetLabelOpCode      = 53
+	GetLabelSheetOpCode       = 54
+	ExcludeCarpetOpCode       = 55
+	IncludeCarpetOpCode       = 56
+	GetCarpetExclusionsOpCode = 57
+
+	NewPriceListOpCode    = 60
+	GetPriceListsOpCode   = 61
+	DeletePriceListOpCode = 62
+	AddPriceOpCode        = 63
+	GetPricesOpCode       = 64
+	DeletePriceOpCode     = 65
+	ResolvePriceOpCode    = 66
+	QuotePriceOpCode      = 67
+
+	AddPriceAdjustmentOpCode    = 70
+	GetPriceAdjustmentsOpCode   = 71
+	DeletePriceAdjustmentOpCode = 72
+
+	NewWarehouseOpCode        = 80
+	GetWarehousesOpCode       = 81
+	DeleteWarehouseOpCode     = 82
+	RecordStockMovementOpCode = 83
+	GetStockMovementsOpCode   = 84
+	GetStockOpCode            = 85
+	ReserveStockOpCode        = 86
+	ReleaseReservationOpCode  = 87
+	GetReservationsOpCode     = 88
+
+	AddThemesOpCode        = 90
+	RemoveThemesOpCode     = 91
+	AddDimensionsOpCode    = 92
+	RemoveDimensionsOpCode = 93
+	RenameThemeOpCode      = 94
+	RenameDimensionOpCode  = 95
+
+	GetProductWithDesignCodeOpCode = 100
+	RenameDesignCodeOpCode         = 101
+	GetProductAliasesOpCode        = 102
+	MergeProductsOpCode            = 103
+	GetProductHistoryOpCode        = 104
 )
 
 type (
@@ -76,6 +153,8 @@ func (h *gRPCHandler) GeneralCall(ctx context.Context, req *micro.RequestMessage
 
 	fmt.Println(common)
 
+	ctx = service.WithRole(ctx, roleFromMetadata(ctx))
+
 	switch req.GetOpCode() {
 
 	case NewProductOpCode:
@@ -90,6 +169,468 @@ func (h *gRPCHandler) GeneralCall(ctx context.Context, req *micro.RequestMessage
 		// Call service
 		payload, err = h.service.CreateNewProduct(ctx, serviceRequest)
 
+	case ChangeProductStatusOpCode:
+		serviceRequest := &api.ChangeProductStatusRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.ChangeProductStatus(ctx, serviceRequest)
+
+	case CloneProductOpCode:
+		serviceRequest := &api.CloneProductRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.CloneProduct(ctx, serviceRequest)
+
+	case BulkEditProductsOpCode:
+		serviceRequest := &api.BulkEditProductsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.BulkEditProducts(ctx, serviceRequest)
+
+	case ImportProductsOpCode:
+		serviceRequest := &api.ImportProductsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.ImportProducts(ctx, serviceRequest)
+
+	case ExportCatalogOpCode:
+		serviceRequest := &api.ExportCatalogRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		// A unary call holds a bounded part of export, client continues with NextAfterId
+		var data bytes.Buffer
+		var nextAfterId uint
+		if nextAfterId, err = h.service.ExportCatalog(ctx, serviceRequest, &data); err != nil {
+			break
+		}
+		payload = &api.ExportCatalogResponse{
+			ContentType: catalog.ContentType(serviceRequest.Format),
+			Data:        data.Bytes(),
+			NextAfterId: nextAfterId,
+		}
+
+	case DumpCatalogOpCode:
+		serviceRequest := &api.DumpCatalogRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		var data bytes.Buffer
+		if err = h.service.DumpCatalog(ctx, serviceRequest, &data); err != nil {
+			break
+		}
+		payload = &api.DumpCatalogResponse{ContentType: service.DumpContentType, Data: data.Bytes()}
+
+	case RestoreCatalogOpCode:
+		serviceRequest := &api.RestoreCatalogRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.RestoreCatalog(ctx, serviceRequest)
+
+	case NewStandardSizeOpCode:
+		serviceRequest := &api.CreateStandardSizeRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.CreateStandardSize(ctx, serviceRequest)
+
+	case GetStandardSizesOpCode:
+		serviceRequest := &api.CompanyRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetStandardSizes(ctx, serviceRequest.CompanyId)
+
+	case EditStandardSizeOpCode:
+		serviceRequest := &api.EditStandardSizeRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.EditStandardSize(ctx, serviceRequest)
+
+	case DeleteStandardSizeOpCode:
+		serviceRequest := &api.DeleteStandardSizeRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		err = h.service.DeleteStandardSize(ctx, serviceRequest)
+
+	case GetStandardSizeProductsOpCode:
+		serviceRequest := &api.CompanyRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetStandardSizeProducts(ctx, serviceRequest.CompanyId)
+
+	case NewAttributeDefinitionOpCode:
+		serviceRequest := &api.CreateAttributeDefinitionRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.CreateAttributeDefinition(ctx, serviceRequest)
+
+	case GetAttributeDefinitionsOpCode:
+		serviceRequest := &api.CompanyRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetAttributeDefinitions(ctx, serviceRequest.CompanyId)
+
+	case EditAttributeDefinitionOpCode:
+		serviceRequest := &api.EditAttributeDefinitionRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.EditAttributeDefinition(ctx, serviceRequest)
+
+	case DeleteAttributeDefinitionOpCode:
+		serviceRequest := &api.DeleteAttributeDefinitionRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		err = h.service.DeleteAttributeDefinition(ctx, serviceRequest)
+
+	case SearchProductsOpCode:
+		serviceRequest := &api.SearchProductsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.SearchProducts(ctx, serviceRequest)
+
+	case NewCategoryOpCode:
+		serviceRequest := &api.CreateCategoryRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.CreateCategory(ctx, serviceRequest)
+
+	case GetCategoriesOpCode:
+		serviceRequest := &api.CompanyRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetCategories(ctx, serviceRequest.CompanyId)
+
+	case MoveCategoryOpCode:
+		serviceRequest := &api.MoveCategoryRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.MoveCategory(ctx, serviceRequest)
+
+	case DeleteCategoryOpCode:
+		serviceRequest := &api.DeleteCategoryRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		err = h.service.DeleteCategory(ctx, serviceRequest)
+
+	case GetCategoryProductsOpCode:
+		serviceRequest := &api.GetCategoryProductsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetCategoryProducts(ctx, serviceRequest)
+
+	case AddTagsOpCode:
+		serviceRequest := &api.TagProductsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.AddTags(ctx, serviceRequest)
+
+	case RemoveTagsOpCode:
+		serviceRequest := &api.TagProductsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.RemoveTags(ctx, serviceRequest)
+
+	case GetTagsOpCode:
+		serviceRequest := &api.CompanyRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetTags(ctx, serviceRequest.CompanyId)
+
+	case GetCarpetsOpCode:
+		serviceRequest := &api.GetCarpetsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetCarpets(ctx, serviceRequest)
+
+	case GetProductCarpetsOpCode:
+		serviceRequest := &api.GetProductCarpetsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetProductCarpets(ctx, serviceRequest)
+
+	case GetCarpetOpCode:
+		serviceRequest := &api.GetCarpetRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetCarpet(ctx, serviceRequest)
+
+	case GetCarpetLabelOpCode:
+		serviceRequest := &api.GetCarpetLabelRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetCarpetLabel(ctx, serviceRequest)
+
+	case GetLabelSheetOpCode:
+		serviceRequest := &api.GetLabelSheetRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetLabelSheet(ctx, serviceRequest)
+
+	case ExcludeCarpetOpCode:
+		serviceRequest := &api.ExcludeCarpetRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.ExcludeCarpet(ctx, serviceRequest)
+
+	case IncludeCarpetOpCode:
+		serviceRequest := &api.IncludeCarpetRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		err = h.service.IncludeCarpet(ctx, serviceRequest)
+
+	case GetCarpetExclusionsOpCode:
+		serviceRequest := &api.GetCarpetExclusionsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetCarpetExclusions(ctx, serviceRequest)
+
+	case NewPriceListOpCode:
+		serviceRequest := &api.CreatePriceListRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.CreatePriceList(ctx, serviceRequest)
+
+	case GetPriceListsOpCode:
+		serviceRequest := &api.CompanyRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetPriceLists(ctx, serviceRequest.CompanyId)
+
+	case DeletePriceListOpCode:
+		serviceRequest := &api.DeletePriceListRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		err = h.service.DeletePriceList(ctx, serviceRequest)
+
+	case AddPriceOpCode:
+		serviceRequest := &api.AddPriceRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.AddPrice(ctx, serviceRequest)
+
+	case GetPricesOpCode:
+		serviceRequest := &api.GetPricesRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetPrices(ctx, serviceRequest)
+
+	case DeletePriceOpCode:
+		serviceRequest := &api.DeletePriceRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		err = h.service.DeletePrice(ctx, serviceRequest)
+
+	case ResolvePriceOpCode:
+		serviceRequest := &api.ResolvePriceRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.ResolvePrice(ctx, serviceRequest)
+
+	case QuotePriceOpCode:
+		serviceRequest := &api.QuotePriceRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.QuotePrice(ctx, serviceRequest)
+
+	case AddPriceAdjustmentOpCode:
+		serviceRequest := &api.AddPriceAdjustmentRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.AddPriceAdjustment(ctx, serviceRequest)
+
+	case GetPriceAdjustmentsOpCode:
+		serviceRequest := &api.GetPriceAdjustmentsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetPriceAdjustments(ctx, serviceRequest)
+
+	case DeletePriceAdjustmentOpCode:
+		serviceRequest := &api.DeletePriceAdjustmentRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		err = h.service.DeletePriceAdjustment(ctx, serviceRequest)
+
+	case NewWarehouseOpCode:
+		serviceRequest := &api.CreateWarehouseRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.CreateWarehouse(ctx, serviceRequest)
+
+	case GetWarehousesOpCode:
+		serviceRequest := &api.CompanyRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetWarehouses(ctx, serviceRequest.CompanyId)
+
+	case DeleteWarehouseOpCode:
+		serviceRequest := &api.DeleteWarehouseRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		err = h.service.DeleteWarehouse(ctx, serviceRequest)
+
+	case RecordStockMovementOpCode:
+		serviceRequest := &api.RecordStockMovementRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.RecordStockMovement(ctx, serviceRequest)
+
+	case GetStockMovementsOpCode:
+		serviceRequest := &api.GetStockMovementsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetStockMovements(ctx, serviceRequest)
+
+	case GetStockOpCode:
+		serviceRequest := &api.GetStockRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetStock(ctx, serviceRequest)
+
+	case ReserveStockOpCode:
+		serviceRequest := &api.ReserveStockRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.ReserveStock(ctx, serviceRequest)
+
+	case ReleaseReservationOpCode:
+		serviceRequest := &api.ReleaseReservationRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.ReleaseReservation(ctx, serviceRequest)
+
+	case GetReservationsOpCode:
+		serviceRequest := &api.GetReservationsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetReservations(ctx, serviceRequest)
+
+	case AddThemesOpCode:
+		serviceRequest := &api.AddThemesRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.AddThemes(ctx, serviceRequest)
+
+	case RemoveThemesOpCode:
+		serviceRequest := &api.RemoveThemesRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.RemoveThemes(ctx, serviceRequest)
+
+	case AddDimensionsOpCode:
+		serviceRequest := &api.AddDimensionsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.AddDimensions(ctx, serviceRequest)
+
+	case RemoveDimensionsOpCode:
+		serviceRequest := &api.RemoveDimensionsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.RemoveDimensions(ctx, serviceRequest)
+
+	case RenameThemeOpCode:
+		serviceRequest := &api.RenameThemeRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.RenameTheme(ctx, serviceRequest)
+
+	case RenameDimensionOpCode:
+		serviceRequest := &api.RenameDimensionRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.RenameDimension(ctx, serviceRequest)
+
+	case GetProductWithDesignCodeOpCode:
+		serviceRequest := &api.GetProductWithDesignCodeRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetProductWithDesignCode(ctx, serviceRequest)
+
+	case RenameDesignCodeOpCode:
+		serviceRequest := &api.RenameDesignCodeRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.RenameDesignCode(ctx, serviceRequest)
+
+	case GetProductAliasesOpCode:
+		serviceRequest := &api.GetProductAliasesRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetProductAliases(ctx, serviceRequest)
+
+	case MergeProductsOpCode:
+		serviceRequest := &api.MergeProductsRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.MergeProducts(ctx, serviceRequest)
+
+	case GetProductHistoryOpCode:
+		serviceRequest := &api.GetProductHistoryRequest{}
+		if err = decodePayload(req, serviceRequest); err != nil {
+			break
+		}
+		payload, err = h.service.GetProductHistory(ctx, serviceRequest)
+
 	default:
 		err = derror.NotImplemented
 
@@ -99,6 +640,26 @@ func (h *gRPCHandler) GeneralCall(ctx context.Context, req *micro.RequestMessage
 	return res, nil
 }
 
+// decodePayload unmarshal payload of `req` to `serviceRequest`
+func decodePayload(req *micro.RequestMessage, serviceRequest interface{}) error {
+	if err := json.Unmarshal([]byte(req.GetPayload()), serviceRequest); err != nil {
+		return derror.New(derror.BadRequest, err.Error())
+	}
+	return nil
+}
+
+// roleFromMetadata return role of caller set by gateway in `role` metadata, empty if not set
+func roleFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if roles := md.Get("role"); len(roles) != 0 {
+		return roles[0]
+	}
+	return ""
+}
+
 func logInterceptor(l logger.Logger) grpc.UnaryServerInterceptor {
 
 	return func(ctx context.Context, req interface{},
@@ -0,0 +1,312 @@
+package label
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/pkg/barcode"
+	"github.com/seed95/product-service/pkg/sku"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// Label formats, png text is drawn with a latin bitmap font so persian letters are transliterated
+// ("قرمز" is printed as GHRMZ), svg keeps original text
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+// Layout of a label in modules of barcode, every length is multiplied by scale
+const (
+	scale         = 2
+	barcodeQuiet  = 10
+	barcodeHeight = 30
+	qrQuiet       = 4
+	qrModule      = 2 // Pixels of a qr module before scale
+	padding       = 4
+	lineSpacing   = 3
+	glyphSpacing  = 1
+)
+
+var (
+	ErrInvalidFormat = errors.New("invalid_label_format")
+	ErrEmptySheet    = errors.New("empty_label_sheet")
+)
+
+type (
+	// Label is printed content of a carpet label, Code is encoded in barcode and qr code
+	Label struct {
+		Code       string
+		DesignCode string
+		Size       string
+		Color      string
+	}
+
+	// layout is rendered parts of a label
+	layout struct {
+		barcode []bool
+		qr      [][]bool
+		lines   []string
+	}
+)
+
+// FromCarpet return label of `carpet`, code is sku of carpet with company prefix and check digit
+func FromCarpet(carpet model.Carpet) Label {
+	return Label{
+		Code: sku.EncodeWithCheck(sku.SKU{
+			CompanyId:   carpet.CompanyId,
+			ProductId:   carpet.ProductId,
+			DimensionId: carpet.DimensionId,
+			ThemeId:     carpet.ThemeId,
+		}),
+		DesignCode: carpet.DesignCode,
+		Size:       carpet.Dimension,
+		Color:      carpet.Color,
+	}
+}
+
+// text return lines printed on label
+func (l Label) text() []string {
+	return []string{l.Code, "DESIGN " + l.DesignCode, "SIZE " + l.Size, "COLOR " + l.Color}
+}
+
+// ContentType return mime type of `format`
+func ContentType(format string) string {
+	if format == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// DefaultFormat return png, or svg if text of a label has persian letters that png transliterates
+func DefaultFormat(labels []Label) string {
+	for _, l := range labels {
+		for _, line := range l.text() {
+			if transliterated(line) {
+				return FormatSVG
+			}
+		}
+	}
+	return FormatPNG
+}
+
+// FormatIsValid check `format` is png or svg
+func FormatIsValid(format string) bool {
+	return format == FormatPNG || format == FormatSVG
+}
+
+// Render write a sheet of `labels` in `format` to `w`, labels are placed in `columns` columns
+func Render(w io.Writer, format string, labels []Label, columns int) error {
+	if !FormatIsValid(format) {
+		return ErrInvalidFormat
+	}
+
+	if len(labels) == 0 {
+		return ErrEmptySheet
+	}
+
+	if columns < 1 {
+		columns = 1
+	}
+	if columns > len(labels) {
+		columns = len(labels)
+	}
+
+	layouts := make([]layout, len(labels))
+	for i, l := range labels {
+		bar, err := barcode.Code128(l.Code)
+		if err != nil {
+			return err
+		}
+		qr, err := barcode.QR([]byte(l.Code))
+		if err != nil {
+			return err
+		}
+		layouts[i] = layout{
+			barcode: bar,
+			qr:      qr,
+			lines:   l.text(),
+		}
+	}
+
+	// All labels of a sheet have same size
+	width, height := 0, 0
+	for _, l := range layouts {
+		w, h := l.size()
+		if w > width {
+			width = w
+		}
+		if h > height {
+			height = h
+		}
+	}
+
+	if format == FormatSVG {
+		return renderSVG(w, layouts, columns, width, height)
+	}
+	return renderPNG(w, layouts, columns, width, height)
+}
+
+func rows(n, columns int) int {
+	if columns == 0 {
+		return 0
+	}
+	return (n + columns - 1) / columns
+}
+
+func (l layout) barcodeSize() (int, int) {
+	return (len(l.barcode) + 2*barcodeQuiet) * scale, barcodeHeight * scale
+}
+
+func (l layout) qrSize() int {
+	return (len(l.qr) + 2*qrQuiet) * qrModule * scale
+}
+
+func (l layout) textSize() (int, int) {
+	width := 0
+	for _, line := range l.lines {
+		if n := len([]rune(bitmapText(line))); n > width {
+			width = n
+		}
+	}
+	return width * (glyphWidth + glyphSpacing) * scale, len(l.lines) * (glyphHeight + lineSpacing) * scale
+}
+
+// size of label: barcode on top, text lines and qr code under it
+func (l layout) size() (int, int) {
+	bw, bh := l.barcodeSize()
+	tw, th := l.textSize()
+	qs := l.qrSize()
+
+	width := bw
+	if tw+padding*scale+qs > width {
+		width = tw + padding*scale + qs
+	}
+	bottom := th
+	if qs > bottom {
+		bottom = qs
+	}
+	return width + 2*padding*scale, bh + bottom + 3*padding*scale
+}
+
+func renderPNG(w io.Writer, layouts []layout, columns, width, height int) error {
+	img := image.NewGray(image.Rect(0, 0, columns*width, rows(len(layouts), columns)*height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	black := color.Gray{}
+	fill := func(x, y, w, h int) {
+		for dy := 0; dy < h; dy++ {
+			for dx := 0; dx < w; dx++ {
+				img.SetGray(x+dx, y+dy, black)
+			}
+		}
+	}
+
+	for i, l := range layouts {
+		x0, y0 := (i%columns)*width+padding*scale, (i/columns)*height+padding*scale
+
+		_, bh := l.barcodeSize()
+		for j, bar := range l.barcode {
+			if bar {
+				fill(x0+(barcodeQuiet+j)*scale, y0, scale, bh)
+			}
+		}
+
+		y := y0 + bh + padding*scale
+		for _, line := range l.lines {
+			x := x0
+			for _, r := range bitmapText(line) {
+				glyph := glyphs[r]
+				for gy, row := range glyph {
+					for gx := 0; gx < glyphWidth; gx++ {
+						if row&(1<<(glyphWidth-1-gx)) != 0 {
+							fill(x+gx*scale, y+gy*scale, scale, scale)
+						}
+					}
+				}
+				x += (glyphWidth + glyphSpacing) * scale
+			}
+			y += (glyphHeight + lineSpacing) * scale
+		}
+
+		qx := (i%columns)*width + width - padding*scale - l.qrSize()
+		qy := y0 + bh + padding*scale
+		m := qrModule * scale
+		for r, row := range l.qr {
+			for c, dark := range row {
+				if dark {
+					fill(qx+(qrQuiet+c)*m, qy+(qrQuiet+r)*m, m, m)
+				}
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+func renderSVG(w io.Writer, layouts []layout, columns, width, height int) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		columns*width, rows(len(layouts), columns)*height, columns*width, rows(len(layouts), columns)*height)
+	fmt.Fprintf(b, `<rect width="100%%" height="100%%" fill="#fff"/>`)
+
+	for i, l := range layouts {
+		fmt.Fprintf(b, `<g transform="translate(%d,%d)">`, (i%columns)*width+padding*scale, (i/columns)*height+padding*scale)
+
+		// Bars, consecutive bar modules are one rect
+		_, bh := l.barcodeSize()
+		for j := 0; j < len(l.barcode); {
+			if !l.barcode[j] {
+				j++
+				continue
+			}
+			k := j
+			for k < len(l.barcode) && l.barcode[k] {
+				k++
+			}
+			fmt.Fprintf(b, `<rect x="%d" y="0" width="%d" height="%d"/>`, (barcodeQuiet+j)*scale, (k-j)*scale, bh)
+			j = k
+		}
+
+		// Text keeps original characters
+		fontSize := (glyphHeight + 1) * scale
+		y := bh + padding*scale
+		for _, text := range l.lines {
+			y += (glyphHeight + lineSpacing) * scale
+			fmt.Fprintf(b, `<text x="0" y="%d" font-family="sans-serif" font-size="%d">`, y-lineSpacing*scale, fontSize)
+			if err := xml.EscapeText(b, []byte(text)); err != nil {
+				return err
+			}
+			b.WriteString("</text>")
+		}
+
+		qx := width - 2*padding*scale - l.qrSize()
+		qy := bh + padding*scale
+		m := qrModule * scale
+		for r, row := range l.qr {
+			for c := 0; c < len(row); {
+				if !row[c] {
+					c++
+					continue
+				}
+				k := c
+				for k < len(row) && row[k] {
+					k++
+				}
+				fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d"/>`, qx+(qrQuiet+c)*m, qy+(qrQuiet+r)*m, (k-c)*m, m)
+				c = k
+			}
+		}
+
+		b.WriteString("</g>")
+	}
+
+	b.WriteString("</svg>")
+	return b.Flush()
+}
@@ -0,0 +1,76 @@
+package label
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func carpets() []model.Carpet {
+	return []model.Carpet{
+		{Id: "P12D14T13", CompanyId: 1, ProductId: 12, DimensionId: 14, ThemeId: 13, DesignCode: "106", Dimension: "6", Color: "آبی"},
+		{Id: "P12D14T14", CompanyId: 1, ProductId: 12, DimensionId: 14, ThemeId: 14, DesignCode: "106", Dimension: "6", Color: "قرمز"},
+		{Id: "P12D13T14", CompanyId: 1, ProductId: 12, DimensionId: 13, ThemeId: 14, DesignCode: "106", Dimension: "9", Color: "قرمز"},
+	}
+}
+
+func TestFromCarpet(t *testing.T) {
+	l := FromCarpet(carpets()[0])
+	require.Regexp(t, `^1-P12D14T13-\d$`, l.Code)
+	require.Equal(t, "106", l.DesignCode)
+	require.Equal(t, "6", l.Size)
+	require.Equal(t, "آبی", l.Color)
+}
+
+func TestRender_PNG(t *testing.T) {
+	labels := []Label{FromCarpet(carpets()[0])}
+
+	var buf bytes.Buffer
+	require.Nil(t, Render(&buf, FormatPNG, labels, 1))
+	single, err := png.Decode(&buf)
+	require.Nil(t, err)
+
+	var labels3 []Label
+	for _, c := range carpets() {
+		labels3 = append(labels3, FromCarpet(c))
+	}
+	buf.Reset()
+	require.Nil(t, Render(&buf, FormatPNG, labels3, 2))
+	sheet, err := png.Decode(&buf)
+	require.Nil(t, err)
+
+	// Two columns and two rows of labels
+	require.Equal(t, 2*single.Bounds().Dx(), sheet.Bounds().Dx())
+	require.Equal(t, 2*single.Bounds().Dy(), sheet.Bounds().Dy())
+}
+
+func TestRender_SVG(t *testing.T) {
+	var buf bytes.Buffer
+	require.Nil(t, Render(&buf, FormatSVG, []Label{FromCarpet(carpets()[1])}, 1))
+
+	// Well formed xml with original text
+	decoder := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		if _, err := decoder.Token(); err != nil {
+			require.Equal(t, "EOF", err.Error())
+			break
+		}
+	}
+	require.True(t, strings.Contains(buf.String(), "COLOR قرمز"))
+	require.True(t, strings.Contains(buf.String(), "DESIGN 106"))
+}
+
+func TestRender_InvalidFormat(t *testing.T) {
+	var buf bytes.Buffer
+	require.Equal(t, ErrInvalidFormat, Render(&buf, "pdf", []Label{FromCarpet(carpets()[0])}, 1))
+}
+
+func TestBitmapText(t *testing.T) {
+	require.Equal(t, "COLOR GHRMZ", bitmapText("color قرمز"))
+	require.Equal(t, "SIZE 2X3", bitmapText("size ۲×۳"))
+	require.Equal(t, "?", bitmapText("😀"))
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"errors"
+	"github.com/seed95/product-service/pkg/normalize"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidSize = errors.New("invalid_size")
+
+// Carpet shapes
+const (
+	ShapeRectangle = "rectangle"
+	ShapeSquare    = "square"
+	ShapeRunner    = "runner"
+	ShapeRound     = "round"
+)
+
+// runnerRatio is the minimum length to width ratio of a runner
+const runnerRatio = 2.5
+
+// sizeSeparators split width and length of a size label
+var sizeSeparators = strings.NewReplacer("×", "x", "*", "x", "X", "x")
+
+// sizeUnits are unit suffixes of a size label, longer suffix first
+var sizeUnits = []struct {
+	suffix string
+	unit   string
+}{
+	{"متری", "m"},
+	{"متر", "m"},
+	{"m2", "m"},
+	{"cm", "cm"},
+	{"m", "m"},
+}
+
+type (
+	// CarpetSize is measure of a carpet, Width and Length are zero if size is known only by its area
+	CarpetSize struct {
+		Width  uint    // Centimeter
+		Length uint    // Centimeter
+		Area   float64 // Square meter
+		Shape  string
+	}
+)
+
+// ShapeIsValid check `shape` is empty or one of carpet shapes
+func ShapeIsValid(shape string) bool {
+	switch shape {
+	case "", ShapeRectangle, ShapeSquare, ShapeRunner, ShapeRound:
+		return true
+	}
+	return false
+}
+
+// SizeOf return measure of a carpet with `width` and `length` in centimeter,
+// if they are zero measure is parsed from `label`.
+// a label is "<width>x<length>" in centimeter or meter (both less than 10) or an area in square meter like "6".
+// empty `shape` is found from width and length, round carpet has diameter `width`
+func SizeOf(label string, width, length uint, shape string) (CarpetSize, error) {
+	if !ShapeIsValid(shape) {
+		return CarpetSize{}, ErrInvalidSize
+	}
+
+	if width == 0 || length == 0 {
+		var area float64
+		var err error
+		width, length, area, err = parseSize(label)
+		if err != nil {
+			return CarpetSize{}, err
+		}
+		if width == 0 {
+			if shape == "" {
+				shape = ShapeRectangle
+			}
+			return CarpetSize{Area: area, Shape: shape}, nil
+		}
+	}
+
+	if width > length {
+		width, length = length, width
+	}
+
+	if shape == "" {
+		switch {
+		case width == length:
+			shape = ShapeSquare
+		case float64(length)/float64(width) >= runnerRatio:
+			shape = ShapeRunner
+		default:
+			shape = ShapeRectangle
+		}
+	}
+
+	area := float64(width) * float64(length) / 10000
+	if shape == ShapeRound {
+		radius := float64(width) / 200
+		area = math.Pi * radius * radius
+	}
+
+	return CarpetSize{Width: width, Length: length, Area: area, Shape: shape}, nil
+}
+
+// parseSize return width and length in centimeter of `label`, or its area in square meter if it has one number
+func parseSize(label string) (width, length uint, area float64, err error) {
+	label = strings.ReplaceAll(sizeSeparators.Replace(normalize.Key(label)), " ", "")
+
+	// Unit suffix, otherwise meter is guessed for small numbers
+	unit := ""
+	for _, suffix := range sizeUnits {
+		if strings.HasSuffix(label, suffix.suffix) {
+			label, unit = strings.TrimSuffix(label, suffix.suffix), suffix.unit
+			break
+		}
+	}
+
+	parts := strings.Split(label, "x")
+	numbers := make([]float64, len(parts))
+	for i, p := range parts {
+		n, err := strconv.ParseFloat(p, 64)
+		if err != nil || !(n > 0) || math.IsInf(n, 0) { // Rejects NaN too
+			return 0, 0, 0, ErrInvalidSize
+		}
+		numbers[i] = n
+	}
+
+	switch len(numbers) {
+	case 1:
+		return 0, 0, numbers[0], nil
+	case 2:
+		w, l := numbers[0], numbers[1]
+		if unit == "m" || (unit == "" && w < 10 && l < 10) {
+			w, l = w*100, l*100
+		}
+		return uint(math.Round(w)), uint(math.Round(l)), 0, nil
+	}
+	return 0, 0, 0, ErrInvalidSize
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+func TestSizeOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		label  string
+		width  uint
+		length uint
+		shape  string
+		want   CarpetSize
+		err    error
+	}{
+		{"catalog size", "6", 200, 300, "", CarpetSize{Width: 200, Length: 300, Area: 6, Shape: ShapeRectangle}, nil},
+		{"centimeter label", "300x200", 0, 0, "", CarpetSize{Width: 200, Length: 300, Area: 6, Shape: ShapeRectangle}, nil},
+		{"meter label", "۲.۵×۳.۵", 0, 0, "", CarpetSize{Width: 250, Length: 350, Area: 8.75, Shape: ShapeRectangle}, nil},
+		{"meter suffix", "1 x 1 متر", 0, 0, "", CarpetSize{Width: 100, Length: 100, Area: 1, Shape: ShapeSquare}, nil},
+		{"centimeter suffix", "8x9cm", 0, 0, "", CarpetSize{Width: 8, Length: 9, Area: 0.0072, Shape: ShapeRectangle}, nil},
+		{"runner", "80x300", 0, 0, "", CarpetSize{Width: 80, Length: 300, Area: 2.4, Shape: ShapeRunner}, nil},
+		{"area label", "۱۲ متری", 0, 0, "", CarpetSize{Area: 12, Shape: ShapeRectangle}, nil},
+		{"explicit shape", "9", 0, 0, ShapeSquare, CarpetSize{Area: 9, Shape: ShapeSquare}, nil},
+		{"invalid label", "بزرگ", 0, 0, "", CarpetSize{}, ErrInvalidSize},
+		{"negative", "-2x3", 0, 0, "", CarpetSize{}, ErrInvalidSize},
+		{"three numbers", "2x3x4", 0, 0, "", CarpetSize{}, ErrInvalidSize},
+		{"not a number", "NaN", 0, 0, "", CarpetSize{}, ErrInvalidSize},
+		{"invalid shape", "6", 0, 0, "oval", CarpetSize{}, ErrInvalidSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SizeOf(tt.label, tt.width, tt.length, tt.shape)
+			require.Equal(t, tt.err, err)
+			require.Equal(t, tt.want.Width, got.Width)
+			require.Equal(t, tt.want.Length, got.Length)
+			require.Equal(t, tt.want.Shape, got.Shape)
+			require.InDelta(t, tt.want.Area, got.Area, 1e-9)
+		})
+	}
+
+	round, err := SizeOf("200x200", 0, 0, ShapeRound)
+	require.Nil(t, err)
+	require.InDelta(t, math.Pi, round.Area, 1e-9)
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var (
+	ErrInvalidAttribute  = errors.New("invalid_attribute")
+	ErrRequiredAttribute = errors.New("required_attribute")
+	ErrUnknownAttribute  = errors.New("unknown_attribute")
+)
+
+// Attribute types
+const (
+	AttributeString = "string"
+	AttributeNumber = "number"
+	AttributeEnum   = "enum"
+	AttributeBool   = "bool"
+)
+
+// Attribute filter operators, a product without a value of the attribute matches none of them
+const (
+	FilterEqual          = "eq"
+	FilterNotEqual       = "ne"
+	FilterGreater        = "gt"
+	FilterGreaterOrEqual = "gte"
+	FilterLess           = "lt"
+	FilterLessOrEqual    = "lte"
+)
+
+type (
+	AttributeDefinition struct {
+		Id        uint
+		CompanyId uint
+		Name      string
+		Type      string
+		Unit      string
+		Required  bool
+		Options   []string // Allowed values of an enum attribute
+	}
+
+	AttributeFilter struct {
+		Name     string
+		Operator string
+		Value    string
+	}
+)
+
+// TypeIsValid check type of attribute is one of supported types
+func (d AttributeDefinition) TypeIsValid() bool {
+	switch d.Type {
+	case AttributeString, AttributeNumber, AttributeBool:
+		return true
+	case AttributeEnum:
+		return len(d.Options) != 0
+	}
+	return false
+}
+
+// Normalize validate `value` against definition and return its canonical form,
+// numbers are formatted without trailing zeros and booleans as true/false
+func (d AttributeDefinition) Normalize(value string) (string, error) {
+	switch d.Type {
+	case AttributeString:
+		return value, nil
+
+	case AttributeNumber:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v is not a number", ErrInvalidAttribute, d.Name)
+		}
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+
+	case AttributeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v is not a bool", ErrInvalidAttribute, d.Name)
+		}
+		return strconv.FormatBool(b), nil
+
+	case AttributeEnum:
+		for _, o := range d.Options {
+			if o == value {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("%w: %v is not an option of %v", ErrInvalidAttribute, value, d.Name)
+	}
+
+	return "", fmt.Errorf("%w: unknown type %v", ErrInvalidAttribute, d.Type)
+}
+
+// NormalizeAttributes validate `values` (attribute name to value) against `definitions`.
+// every required attribute should have a value and an empty value is same as missing value
+func NormalizeAttributes(definitions []AttributeDefinition, values map[string]string) (map[string]string, error) {
+
+	byName := make(map[string]AttributeDefinition, len(definitions))
+	for _, d := range definitions {
+		byName[d.Name] = d
+	}
+
+	result := make(map[string]string, len(values))
+	for name, value := range values {
+		d, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %v", ErrUnknownAttribute, name)
+		}
+		if value == "" {
+			continue
+		}
+		normalized, err := d.Normalize(value)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = normalized
+	}
+
+	for _, d := range definitions {
+		if _, ok := result[d.Name]; d.Required && !ok {
+			return nil, fmt.Errorf("%w: %v", ErrRequiredAttribute, d.Name)
+		}
+	}
+
+	return result, nil
+}
+
+// OperatorIsValid check operator of filter can apply to attribute with type `attributeType`
+func (f AttributeFilter) OperatorIsValid(attributeType string) bool {
+	switch f.Operator {
+	case FilterEqual, FilterNotEqual:
+		return true
+	case FilterGreater, FilterGreaterOrEqual, FilterLess, FilterLessOrEqual:
+		return attributeType == AttributeNumber
+	}
+	return false
+}
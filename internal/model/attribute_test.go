@@ -0,0 +1,115 @@
+package model
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAttributeDefinition_Normalize(t *testing.T) {
+
+	tests := []struct {
+		Name       string
+		Definition AttributeDefinition
+		Value      string
+		Expect     string
+		Err        error
+	}{
+		{
+			Name:       "String",
+			Definition: AttributeDefinition{Name: "material", Type: AttributeString},
+			Value:      "ابریشم",
+			Expect:     "ابریشم",
+		},
+		{
+			Name:       "Number",
+			Definition: AttributeDefinition{Name: "shaneh", Type: AttributeNumber},
+			Value:      "70.50",
+			Expect:     "70.5",
+		},
+		{
+			Name:       "InvalidNumber",
+			Definition: AttributeDefinition{Name: "shaneh", Type: AttributeNumber},
+			Value:      "هفتاد",
+			Err:        ErrInvalidAttribute,
+		},
+		{
+			Name:       "Bool",
+			Definition: AttributeDefinition{Name: "handmade", Type: AttributeBool},
+			Value:      "1",
+			Expect:     "true",
+		},
+		{
+			Name:       "InvalidBool",
+			Definition: AttributeDefinition{Name: "handmade", Type: AttributeBool},
+			Value:      "yes",
+			Err:        ErrInvalidAttribute,
+		},
+		{
+			Name:       "Enum",
+			Definition: AttributeDefinition{Name: "weave", Type: AttributeEnum, Options: []string{"machine", "hand"}},
+			Value:      "hand",
+			Expect:     "hand",
+		},
+		{
+			Name:       "InvalidEnum",
+			Definition: AttributeDefinition{Name: "weave", Type: AttributeEnum, Options: []string{"machine", "hand"}},
+			Value:      "tufted",
+			Err:        ErrInvalidAttribute,
+		},
+		{
+			Name:       "UnknownType",
+			Definition: AttributeDefinition{Name: "weave", Type: "date"},
+			Value:      "2022",
+			Err:        ErrInvalidAttribute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			got, err := tt.Definition.Normalize(tt.Value)
+			if tt.Err != nil {
+				require.True(t, errors.Is(err, tt.Err))
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.Expect, got)
+		})
+	}
+}
+
+func TestNormalizeAttributes(t *testing.T) {
+	definitions := []AttributeDefinition{
+		{Name: "material", Type: AttributeString, Required: true},
+		{Name: "shaneh", Type: AttributeNumber},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		got, err := NormalizeAttributes(definitions, map[string]string{"material": "پشم", "shaneh": "50"})
+		require.Nil(t, err)
+		require.Equal(t, map[string]string{"material": "پشم", "shaneh": "50"}, got)
+	})
+
+	t.Run("empty optional", func(t *testing.T) {
+		got, err := NormalizeAttributes(definitions, map[string]string{"material": "پشم", "shaneh": ""})
+		require.Nil(t, err)
+		require.Equal(t, map[string]string{"material": "پشم"}, got)
+	})
+
+	t.Run("required", func(t *testing.T) {
+		_, err := NormalizeAttributes(definitions, map[string]string{"shaneh": "50"})
+		require.True(t, errors.Is(err, ErrRequiredAttribute))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := NormalizeAttributes(definitions, map[string]string{"material": "پشم", "origin": "کاشان"})
+		require.True(t, errors.Is(err, ErrUnknownAttribute))
+	})
+}
+
+func TestAttributeFilter_OperatorIsValid(t *testing.T) {
+	require.True(t, AttributeFilter{Operator: FilterEqual}.OperatorIsValid(AttributeString))
+	require.True(t, AttributeFilter{Operator: FilterGreater}.OperatorIsValid(AttributeNumber))
+	require.False(t, AttributeFilter{Operator: FilterGreater}.OperatorIsValid(AttributeEnum))
+	require.False(t, AttributeFilter{Operator: "like"}.OperatorIsValid(AttributeString))
+}
@@ -0,0 +1,33 @@
+package model
+
+type (
+	// BulkEdit is same changes on every product of Filter, colors and sizes that a product already has
+	// or doesn't have are skipped. an empty attribute value removes the attribute
+	BulkEdit struct {
+		Filter       ProductFilter
+		AddColors    []string
+		RemoveColors []string
+		AddSizes     []string
+		RemoveSizes  []string
+		Attributes   map[string]string
+		DryRun       bool
+	}
+
+	// BulkEditResult is what a bulk edit did with a product, or would do in a dry run.
+	// a failed product has Error and isn't changed
+	BulkEditResult struct {
+		ProductId     uint
+		DesignCode    string
+		AddedColors   []string
+		RemovedColors []string
+		AddedSizes    []string
+		RemovedSizes  []string
+		Attributes    bool // Attribute values are changed
+		Error         string
+	}
+)
+
+// Changed check bulk edit changed product of result
+func (r BulkEditResult) Changed() bool {
+	return len(r.AddedColors) != 0 || len(r.RemovedColors) != 0 || len(r.AddedSizes) != 0 || len(r.RemovedSizes) != 0 || r.Attributes
+}
@@ -10,5 +10,19 @@ type (
 		DesignCode  string
 		Dimension   string
 		Color       string
+		OnHand      int64 // Pieces in all warehouses
+		Available   int64 // Pieces in all warehouses that are not reserved
+	}
+
+	// CarpetExclusion mark a size and color of a product that is never woven,
+	// on exclude dimension and theme may be selected with Size and Color
+	CarpetExclusion struct {
+		Id          uint
+		ProductId   uint
+		DimensionId uint
+		ThemeId     uint
+		Size        string
+		Color       string
+		Reason      string
 	}
 )
@@ -0,0 +1,23 @@
+package model
+
+// Category kinds
+const (
+	CategoryKindCategory   = "category"
+	CategoryKindCollection = "collection"
+)
+
+type (
+	// Category is a node of company category tree, a root node has nil ParentId
+	Category struct {
+		Id        uint
+		CompanyId uint
+		ParentId  *uint
+		Name      string
+		Kind      string
+	}
+)
+
+// KindIsValid check kind of category is category or collection
+func (c Category) KindIsValid() bool {
+	return c.Kind == CategoryKindCategory || c.Kind == CategoryKindCollection
+}
@@ -0,0 +1,166 @@
+package model
+
+import "time"
+
+// Identity of a catalog dump, a dump of a newer version can't be restored.
+// Version 2 added product_aliases
+const (
+	DumpFormat  = "product-service/catalog"
+	DumpVersion = 2
+)
+
+// Sections of a dump in order of restore, a section refers only to earlier sections
+const (
+	DumpStandardSizes        = "standard_sizes"
+	DumpAttributeDefinitions = "attribute_definitions"
+	DumpCategories           = "categories"
+	DumpTags                 = "tags"
+	DumpProducts             = "products"
+	DumpDimensions           = "dimensions"
+	DumpThemes               = "themes"
+	DumpProductAttributes    = "product_attributes"
+	DumpProductCategories    = "product_categories"
+	DumpProductTags          = "product_tags"
+	DumpCarpetExclusions     = "carpet_exclusions"
+	DumpPriceLists           = "price_lists"
+	DumpPrices               = "prices"
+	DumpPriceAdjustments     = "price_adjustments"
+	DumpProductAliases       = "product_aliases"
+)
+
+type (
+	// CatalogDump is complete catalog of a company with its database ids, records of a dump refer to each other
+	// with these ids and a restore gives every record a new id. stock and reservations are not catalog
+	CatalogDump struct {
+		CompanyId            uint
+		CreatedAt            time.Time
+		StandardSizes        []DumpStandardSize
+		AttributeDefinitions []DumpAttributeDefinition
+		Categories           []DumpCategory
+		Tags                 []DumpTag
+		Products             []DumpProduct
+		Dimensions           []DumpDimension
+		Themes               []DumpTheme
+		ProductAttributes    []DumpProductAttribute
+		ProductCategories    []DumpProductCategory
+		ProductTags          []DumpProductTag
+		CarpetExclusions     []DumpCarpetExclusion
+		PriceLists           []DumpPriceList
+		Prices               []DumpPrice
+		PriceAdjustments     []DumpPriceAdjustment
+		ProductAliases       []DumpProductAlias
+	}
+
+	DumpStandardSize struct {
+		Id        uint   `json:"id"`
+		Label     string `json:"label"`
+		Width     uint   `json:"width"`
+		Length    uint   `json:"length"`
+		SortOrder int    `json:"sort_order"`
+	}
+
+	DumpAttributeDefinition struct {
+		Id       uint     `json:"id"`
+		Name     string   `json:"name"`
+		Type     string   `json:"type"`
+		Unit     string   `json:"unit"`
+		Required bool     `json:"required"`
+		Options  []string `json:"options"`
+	}
+
+	DumpCategory struct {
+		Id       uint   `json:"id"`
+		ParentId *uint  `json:"parent_id"`
+		Name     string `json:"name"`
+		Kind     string `json:"kind"`
+	}
+
+	DumpTag struct {
+		Id   uint   `json:"id"`
+		Name string `json:"name"`
+	}
+
+	DumpProduct struct {
+		Id             uint       `json:"id"`
+		DesignCode     string     `json:"design_code"`
+		Description    string     `json:"description"`
+		Status         string     `json:"status"`
+		PublishedAt    *time.Time `json:"published_at"`
+		ArchivedAt     *time.Time `json:"archived_at"`
+		DiscontinuedAt *time.Time `json:"discontinued_at"`
+	}
+
+	DumpDimension struct {
+		Id             uint   `json:"id"`
+		ProductId      uint   `json:"product_id"`
+		Size           string `json:"size"`
+		StandardSizeId *uint  `json:"standard_size_id"`
+	}
+
+	DumpTheme struct {
+		Id        uint   `json:"id"`
+		ProductId uint   `json:"product_id"`
+		Color     string `json:"color"`
+	}
+
+	DumpProductAttribute struct {
+		ProductId   uint   `json:"product_id"`
+		AttributeId uint   `json:"attribute_id"`
+		Value       string `json:"value"`
+	}
+
+	DumpProductCategory struct {
+		ProductId  uint `json:"product_id"`
+		CategoryId uint `json:"category_id"`
+	}
+
+	DumpProductTag struct {
+		ProductId uint `json:"product_id"`
+		TagId     uint `json:"tag_id"`
+	}
+
+	DumpCarpetExclusion struct {
+		ProductId   uint   `json:"product_id"`
+		DimensionId uint   `json:"dimension_id"`
+		ThemeId     uint   `json:"theme_id"`
+		Reason      string `json:"reason"`
+	}
+
+	DumpPriceList struct {
+		Id       uint   `json:"id"`
+		Name     string `json:"name"`
+		Kind     string `json:"kind"`
+		Currency string `json:"currency"`
+	}
+
+	// DumpPrice has zero DimensionId for a price per square meter and nil ThemeId for a price of every color
+	DumpPrice struct {
+		PriceListId uint       `json:"price_list_id"`
+		ProductId   uint       `json:"product_id"`
+		DimensionId uint       `json:"dimension_id"`
+		ThemeId     *uint      `json:"theme_id"`
+		Amount      int64      `json:"amount"`
+		ValidFrom   time.Time  `json:"valid_from"`
+		ValidTo     *time.Time `json:"valid_to"`
+	}
+
+	// DumpPriceAdjustment has zero ProductId for an adjustment of every product
+	DumpPriceAdjustment struct {
+		PriceListId uint    `json:"price_list_id"`
+		ProductId   uint    `json:"product_id"`
+		Kind        string  `json:"kind"`
+		Value       string  `json:"value"`
+		Percent     float64 `json:"percent"`
+	}
+
+	DumpProductAlias struct {
+		ProductId  uint   `json:"product_id"`
+		DesignCode string `json:"design_code"`
+	}
+
+	// RestoreResult has number of restored records of each section of a dump and new id of every dumped product
+	RestoreResult struct {
+		Counts     map[string]int
+		ProductIds map[uint]uint
+	}
+)
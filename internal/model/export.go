@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// Kinds of an export
+const (
+	ExportProducts = "products" // A row per product
+	ExportCarpets  = "carpets"  // A row per carpet (SKU)
+)
+
+// Formats of an export
+const (
+	ExportCSV   = "csv"
+	ExportJSONL = "jsonl" // A json object per line
+)
+
+type (
+	// ExportFilter select products of an export, prices are resolved in PriceListId at At if it isn't zero.
+	// Products with id greater than AfterId are exported, in at most MaxBatches batches if it isn't zero
+	ExportFilter struct {
+		ProductFilter
+		PriceListId uint
+		At          time.Time
+		AfterId     uint
+		MaxBatches  int
+	}
+
+	// ExportProduct is a row of a product export, PricePerSquareMeter is nil without a price
+	ExportProduct struct {
+		Id                  uint
+		DesignCode          string
+		Description         string
+		Status              string
+		Sizes               []string
+		Colors              []string
+		Attributes          map[string]string
+		Categories          []uint
+		Tags                []string
+		OnHand              int64
+		Available           int64
+		PricePerSquareMeter *int64
+		Currency            string
+	}
+
+	// ExportCarpet is a row of a carpet export, Price is nil without a price
+	ExportCarpet struct {
+		Carpet
+		Price       *int64
+		PriceSource string
+		Currency    string
+	}
+)
+
+func ExportKindIsValid(kind string) bool {
+	return kind == ExportProducts || kind == ExportCarpets
+}
+
+func ExportFormatIsValid(format string) bool {
+	return format == ExportCSV || format == ExportJSONL
+}
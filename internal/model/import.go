@@ -0,0 +1,26 @@
+package model
+
+// Actions of an import row
+const (
+	ImportCreate = "create"
+	ImportUpdate = "update"
+	ImportFail   = "fail"
+)
+
+type (
+	// ImportRow is a product of a line of an import file
+	ImportRow struct {
+		Line    int
+		Product Product
+	}
+
+	// ImportResult is what an import did with a row, or would do in a dry run.
+	// a failed row has Error and isn't saved
+	ImportResult struct {
+		Line       int
+		DesignCode string
+		Action     string
+		ProductId  uint
+		Error      string
+	}
+)
@@ -0,0 +1,33 @@
+package model
+
+// Page sizes of paged listings
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+type (
+	// Page select a window of a listing, Number starts from 1
+	Page struct {
+		Number int
+		Size   int
+	}
+)
+
+// Normalize return page with first page and default size for zero values, size is limited to MaxPageSize
+func (p Page) Normalize() Page {
+	if p.Number < 1 {
+		p.Number = 1
+	}
+	if p.Size < 1 {
+		p.Size = DefaultPageSize
+	} else if p.Size > MaxPageSize {
+		p.Size = MaxPageSize
+	}
+	return p
+}
+
+// Offset return number of items before page, page should be normalized
+func (p Page) Offset() int {
+	return (p.Number - 1) * p.Size
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPage_Normalize(t *testing.T) {
+	require.Equal(t, Page{Number: 1, Size: DefaultPageSize}, Page{}.Normalize())
+	require.Equal(t, Page{Number: 3, Size: MaxPageSize}, Page{Number: 3, Size: 10000}.Normalize())
+	require.Equal(t, Page{Number: 1, Size: 20}, Page{Number: -2, Size: 20}.Normalize())
+}
+
+func TestPage_Offset(t *testing.T) {
+	require.Equal(t, 0, Page{Number: 1, Size: 20}.Offset())
+	require.Equal(t, 40, Page{Number: 3, Size: 20}.Offset())
+}
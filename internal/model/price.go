@@ -0,0 +1,114 @@
+package model
+
+import "time"
+
+// Price list kinds
+const (
+	PriceListRetail    = "retail"
+	PriceListWholesale = "wholesale"
+	PriceListDealer    = "dealer"
+)
+
+// Sources of an effective price
+const (
+	PriceSourceCarpet = "carpet" // Price of the size and color
+	PriceSourceSize   = "size"   // Price of the size for every color
+	PriceSourceArea   = "area"   // Price per square meter of the product
+)
+
+type (
+	// PriceList is a named set of prices of a company in one currency
+	PriceList struct {
+		Id        uint
+		CompanyId uint
+		Name      string
+		Kind      string
+		Currency  string // ISO 4217 code
+	}
+
+	// Price is amount of a carpet of a product, if ThemeId is zero the price is for every color of the size.
+	// a PerSquareMeter price has no dimension and theme, it is base of area prices of the product.
+	// a price is valid from ValidFrom until ValidTo, nil ValidTo is open ended
+	Price struct {
+		Id             uint
+		PriceListId    uint
+		ProductId      uint
+		DimensionId    uint
+		Size           string // Used to find dimension if DimensionId is zero
+		ThemeId        uint
+		PerSquareMeter bool
+		Amount         int64 // Smallest unit of currency
+		ValidFrom      time.Time
+		ValidTo        *time.Time
+	}
+
+	// EffectivePrice is price of a carpet in a price list at a time,
+	// Breakdown is calculation of an area price and Price.Amount is its total
+	EffectivePrice struct {
+		Carpet    Carpet
+		PriceList PriceList
+		Price     Price
+		Source    string
+		Breakdown *PriceBreakdown
+	}
+)
+
+// KindIsValid check kind of price list is retail, wholesale or dealer
+func (l PriceList) KindIsValid() bool {
+	return l.Kind == PriceListRetail || l.Kind == PriceListWholesale || l.Kind == PriceListDealer
+}
+
+// CurrencyIsValid check `currency` is an ISO 4217 code, three upper case letters
+func CurrencyIsValid(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for _, c := range currency {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidAt check price is valid at `at`, ValidFrom is inclusive and ValidTo is exclusive
+func (p Price) ValidAt(at time.Time) bool {
+	return !at.Before(p.ValidFrom) && (p.ValidTo == nil || at.Before(*p.ValidTo))
+}
+
+// Source return PriceSourceCarpet for price of a color, PriceSourceSize for price of every color
+// and PriceSourceArea for price per square meter
+func (p Price) Source() string {
+	if p.PerSquareMeter {
+		return PriceSourceArea
+	}
+	if p.ThemeId != 0 {
+		return PriceSourceCarpet
+	}
+	return PriceSourceSize
+}
+
+// ResolvePrice return price of `prices` that is effective at `at` for carpet of `dimensionId` and `themeId`.
+// price of the color wins over price of the size, between prices of same source the latest ValidFrom wins.
+// prices per square meter are resolved with zero `dimensionId` and `themeId`
+func ResolvePrice(prices []Price, dimensionId, themeId uint, at time.Time) (Price, bool) {
+	var effective Price
+	found := false
+	for _, p := range prices {
+		if p.PerSquareMeter != (dimensionId == 0) || p.DimensionId != dimensionId || (p.ThemeId != 0 && p.ThemeId != themeId) || !p.ValidAt(at) {
+			continue
+		}
+
+		if !found {
+			effective, found = p, true
+			continue
+		}
+
+		if p.ThemeId != 0 && effective.ThemeId == 0 {
+			effective = p
+		} else if (p.ThemeId == 0) == (effective.ThemeId == 0) && p.ValidFrom.After(effective.ValidFrom) {
+			effective = p
+		}
+	}
+	return effective, found
+}
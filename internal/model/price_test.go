@@ -0,0 +1,72 @@
+package model
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCurrencyIsValid(t *testing.T) {
+	require.True(t, CurrencyIsValid("IRR"))
+	require.True(t, CurrencyIsValid("USD"))
+	require.False(t, CurrencyIsValid("irr"))
+	require.False(t, CurrencyIsValid("RIAL"))
+	require.False(t, CurrencyIsValid(""))
+}
+
+func TestPrice_ValidAt(t *testing.T) {
+	from := time.Date(2022, 3, 21, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	p := Price{ValidFrom: from, ValidTo: &to}
+	require.True(t, p.ValidAt(from))
+	require.True(t, p.ValidAt(to.Add(-time.Second)))
+	require.False(t, p.ValidAt(to))
+	require.False(t, p.ValidAt(from.Add(-time.Second)))
+
+	p.ValidTo = nil
+	require.True(t, p.ValidAt(to.AddDate(10, 0, 0)))
+}
+
+func TestResolvePrice(t *testing.T) {
+	jan := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+	mar := jan.AddDate(0, 2, 0)
+
+	prices := []Price{
+		{Id: 1, DimensionId: 10, Amount: 100, ValidFrom: jan},
+		{Id: 2, DimensionId: 10, Amount: 120, ValidFrom: feb},
+		{Id: 3, DimensionId: 10, ThemeId: 20, Amount: 150, ValidFrom: feb, ValidTo: &mar},
+		{Id: 4, DimensionId: 11, Amount: 200, ValidFrom: jan},
+	}
+
+	tests := []struct {
+		name        string
+		dimensionId uint
+		themeId     uint
+		at          time.Time
+		id          uint
+		found       bool
+	}{
+		{"size price", 10, 21, jan, 1, true},
+		{"latest size price", 10, 21, feb, 2, true},
+		{"color price wins", 10, 20, feb, 3, true},
+		{"expired color price", 10, 20, mar, 2, true},
+		{"other size", 11, 20, feb, 4, true},
+		{"before any price", 10, 20, jan.AddDate(0, 0, -1), 0, false},
+		{"no price of size", 12, 20, feb, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, found := ResolvePrice(prices, tt.dimensionId, tt.themeId, tt.at)
+			require.Equal(t, tt.found, found)
+			require.Equal(t, tt.id, p.Id)
+		})
+	}
+
+	p, _ := ResolvePrice(prices, 10, 20, feb)
+	require.Equal(t, PriceSourceCarpet, p.Source())
+	p, _ = ResolvePrice(prices, 10, 21, feb)
+	require.Equal(t, PriceSourceSize, p.Source())
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"github.com/seed95/product-service/pkg/normalize"
+	"math"
+)
+
+// Price adjustment kinds
+const (
+	AdjustmentColor = "color"
+	AdjustmentShape = "shape"
+)
+
+type (
+	// PriceAdjustment is a surcharge (positive Percent) or discount (negative Percent) of area prices of a price list
+	// for carpets with a color or shape. zero ProductId applies to every product of the price list
+	PriceAdjustment struct {
+		Id          uint
+		PriceListId uint
+		ProductId   uint
+		Kind        string
+		Value       string // Color or shape
+		Percent     float64
+	}
+
+	AppliedAdjustment struct {
+		PriceAdjustment
+		Amount int64
+	}
+
+	// PriceBreakdown is calculation of an area price, Total is Base with Adjustments
+	PriceBreakdown struct {
+		Size           CarpetSize
+		PerSquareMeter int64
+		Base           int64
+		Adjustments    []AppliedAdjustment
+		Total          int64
+	}
+
+	// PriceQuote ask area price of a product in a price list for a size and color.
+	// size is Size label of product or Width and Length of a custom size
+	PriceQuote struct {
+		PriceListId uint
+		ProductId   uint
+		Size        string
+		Width       uint // Centimeter
+		Length      uint // Centimeter
+		Shape       string
+		Color       string
+	}
+)
+
+// KindIsValid check kind of adjustment is color or shape
+func (a PriceAdjustment) KindIsValid() bool {
+	return a.Kind == AdjustmentColor || a.Kind == AdjustmentShape
+}
+
+// matches check adjustment applies to a carpet with `color` and `shape`
+func (a PriceAdjustment) matches(color, shape string) bool {
+	switch a.Kind {
+	case AdjustmentColor:
+		return normalize.Key(a.Value) == normalize.Key(color)
+	case AdjustmentShape:
+		return a.Value == shape
+	}
+	return false
+}
+
+// CalculateAreaPrice return price of a carpet of `size` and `color` with `perSquareMeter` base price.
+// at most one adjustment of each kind is applied, adjustment of the product wins over adjustment of every product.
+// percents of adjustments are applied to the base price and amounts are rounded to the smallest unit of currency
+func CalculateAreaPrice(perSquareMeter int64, size CarpetSize, color string, adjustments []PriceAdjustment) PriceBreakdown {
+	breakdown := PriceBreakdown{
+		Size:           size,
+		PerSquareMeter: perSquareMeter,
+		Base:           int64(math.Round(size.Area * float64(perSquareMeter))),
+	}
+	breakdown.Total = breakdown.Base
+
+	for _, kind := range []string{AdjustmentColor, AdjustmentShape} {
+		var applied *PriceAdjustment
+		for i, a := range adjustments {
+			if a.Kind != kind || !a.matches(color, size.Shape) {
+				continue
+			}
+			if applied == nil || (applied.ProductId == 0 && a.ProductId != 0) {
+				applied = &adjustments[i]
+			}
+		}
+		if applied == nil {
+			continue
+		}
+
+		amount := int64(math.Round(float64(breakdown.Base) * applied.Percent / 100))
+		breakdown.Adjustments = append(breakdown.Adjustments, AppliedAdjustment{PriceAdjustment: *applied, Amount: amount})
+		breakdown.Total += amount
+	}
+
+	return breakdown
+}
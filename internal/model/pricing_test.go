@@ -0,0 +1,42 @@
+package model
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCalculateAreaPrice(t *testing.T) {
+	adjustments := []PriceAdjustment{
+		{Id: 1, Kind: AdjustmentColor, Value: "لاکی", Percent: 10},
+		{Id: 2, ProductId: 5, Kind: AdjustmentColor, Value: "لاكي", Percent: 20},
+		{Id: 3, Kind: AdjustmentShape, Value: ShapeRunner, Percent: -15},
+		{Id: 4, Kind: AdjustmentColor, Value: "کرم", Percent: 5},
+	}
+
+	runner := CarpetSize{Width: 80, Length: 300, Area: 2.4, Shape: ShapeRunner}
+
+	t.Run("no adjustment", func(t *testing.T) {
+		got := CalculateAreaPrice(1000000, CarpetSize{Area: 6, Shape: ShapeRectangle}, "سرمه ای", adjustments)
+		require.Equal(t, int64(6000000), got.Base)
+		require.Equal(t, int64(6000000), got.Total)
+		require.Empty(t, got.Adjustments)
+	})
+
+	t.Run("product adjustment wins", func(t *testing.T) {
+		got := CalculateAreaPrice(1000000, runner, "لاکی", adjustments)
+		require.Equal(t, int64(2400000), got.Base)
+		require.Equal(t, 2, len(got.Adjustments))
+		require.Equal(t, uint(2), got.Adjustments[0].Id)
+		require.Equal(t, int64(480000), got.Adjustments[0].Amount)
+		require.Equal(t, uint(3), got.Adjustments[1].Id)
+		require.Equal(t, int64(-360000), got.Adjustments[1].Amount)
+		require.Equal(t, int64(2520000), got.Total)
+	})
+
+	t.Run("rounding", func(t *testing.T) {
+		got := CalculateAreaPrice(333, CarpetSize{Area: 1.5, Shape: ShapeRectangle}, "کرم", adjustments)
+		require.Equal(t, int64(500), got.Base)
+		require.Equal(t, int64(25), got.Adjustments[0].Amount)
+		require.Equal(t, int64(525), got.Total)
+	})
+}
@@ -1,12 +1,20 @@
 package model
 
-import "errors"
+import (
+	"errors"
+	"github.com/seed95/product-service/pkg/normalize"
+)
 
 var (
 	ErrInvalidNumberOfCarpet = errors.New("invalid_number_of_carpet")
 	ErrInvalidCarpet         = errors.New("invalid_carpet")
 )
 
+// Kinds of product history records
+const (
+	HistoryMerge = "merge"
+)
+
 type (
 	Product struct {
 		Id          uint
@@ -16,32 +24,149 @@ type (
 		Colors      []string
 		Sizes       []string
 		Description string
+		// StandardSizes are ids of company catalog sizes the product offers
+		StandardSizes []uint
+		// Attributes are values of company attribute definitions keyed by attribute name
+		Attributes map[string]string
+		// Categories are ids of category tree nodes the product is a member of
+		Categories []uint
+		// Status is lifecycle status, only used on create and empty means published
+		Status string
+	}
+
+	ProductFilter struct {
+		CompanyId  uint
+		Attributes []AttributeFilter
+		CategoryId uint // Members of the category or its descendants
+		ProductIds []uint
+		Tags       []string
+		TagMatch   string // One of TagMatchAny (default), TagMatchAll
+		Statuses   []string
+		HideDrafts bool
+	}
+
+	// ProductClone copy product `ProductId` of `CompanyId` to a new design code,
+	// zero TargetCompanyId means same company
+	ProductClone struct {
+		CompanyId       uint
+		ProductId       uint
+		DesignCode      string
+		TargetCompanyId uint
+		WithPrices      bool
 	}
 )
 
-func CarpetsToProduct(carpets []Carpet) (*Product, error) {
+// NormalizeText return canonical form of a design code, size or color so persian variants of it are stored the same,
+// letter case is kept
+func NormalizeText(s string) string {
+	return normalize.String(s)
+}
+
+// Normalize replace design code, sizes and colors of product with their canonical forms
+func (p *Product) Normalize() {
+	p.DesignCode = NormalizeText(p.DesignCode)
+	for i := range p.Sizes {
+		p.Sizes[i] = NormalizeText(p.Sizes[i])
+	}
+	for i := range p.Colors {
+		p.Colors[i] = NormalizeText(p.Colors[i])
+	}
+}
+
+// CarpetsToProduct fold carpets of one product back into the product.
+// sizes and colors are deduplicated in order of first appearance and carpets
+// should be the full cross product of them except `exclusions` of the product
+func CarpetsToProduct(carpets []Carpet, exclusions []CarpetExclusion) (*Product, error) {
 
 	result := Product{}
 
-	//if len(carpets) == 0 {
-	//	return &result, nil
-	//}
-	//
-	//// Number of carpets should be a multiple of 2
-	//if len(carpets)%2 != 0 {
-	//	return nil, ErrInvalidNumberOfCarpet
-	//}
-	//
-	//companyId := carpets[0].CompanyId
-	//productId := carpets[0].Id
-	//for _, c := range carpets {
-	//	if c.Id != productId || c.CompanyId != companyId {
-	//		return nil, ErrInvalidCarpet
-	//	}
-	//	result.Colors = append(result.Colors, c.Color)
-	//	result.Sizes = append(result.Sizes, c.Dimension)
-	//}
+	if len(carpets) == 0 {
+		return &result, nil
+	}
+
+	result.Id = carpets[0].ProductId
+	result.CompanyId = carpets[0].CompanyId
+	result.DesignCode = carpets[0].DesignCode
+
+	sizes := make(map[uint]string)
+	colors := make(map[uint]string)
+	pairs := make(map[[2]uint]bool, len(carpets))
+	for _, c := range carpets {
+		if c.ProductId != result.Id || c.CompanyId != result.CompanyId {
+			return nil, ErrInvalidCarpet
+		}
+
+		if size, ok := sizes[c.DimensionId]; !ok {
+			sizes[c.DimensionId] = c.Dimension
+			result.Sizes = append(result.Sizes, c.Dimension)
+		} else if size != c.Dimension {
+			return nil, ErrInvalidCarpet
+		}
+
+		if color, ok := colors[c.ThemeId]; !ok {
+			colors[c.ThemeId] = c.Color
+			result.Colors = append(result.Colors, c.Color)
+		} else if color != c.Color {
+			return nil, ErrInvalidCarpet
+		}
+
+		pair := [2]uint{c.DimensionId, c.ThemeId}
+		if pairs[pair] {
+			return nil, ErrInvalidCarpet
+		}
+		pairs[pair] = true
+	}
+
+	// An excluded combination of sizes and colors of product has no carpet
+	excluded := make(map[[2]uint]bool, len(exclusions))
+	for _, e := range exclusions {
+		pair := [2]uint{e.DimensionId, e.ThemeId}
+		if e.ProductId != result.Id || sizes[e.DimensionId] == "" || colors[e.ThemeId] == "" || excluded[pair] {
+			continue
+		}
+		if pairs[pair] {
+			return nil, ErrInvalidCarpet
+		}
+		excluded[pair] = true
+	}
+
+	// Every size should come in every color
+	if len(carpets) != len(result.Sizes)*len(result.Colors)-len(excluded) {
+		return nil, ErrInvalidNumberOfCarpet
+	}
 
 	return &result, nil
+}
+
+// CarpetsToProducts fold carpets of a company into products in order of first appearance of each product,
+// `exclusions` are excluded carpets of the products
+func CarpetsToProducts(carpets []Carpet, exclusions []CarpetExclusion) ([]Product, error) {
+
+	if len(carpets) == 0 {
+		return nil, nil
+	}
+
+	companyId := carpets[0].CompanyId
+	var productIds []uint
+	groups := make(map[uint][]Carpet)
+	for _, c := range carpets {
+		if c.CompanyId != companyId {
+			return nil, ErrInvalidCarpet
+		}
+		if _, ok := groups[c.ProductId]; !ok {
+			productIds = append(productIds, c.ProductId)
+		}
+		groups[c.ProductId] = append(groups[c.ProductId], c)
+	}
+
+	result := make([]Product, len(productIds))
+	for i, id := range productIds {
+		product, err := CarpetsToProduct(groups[id], exclusions)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = *product
+	}
 
+	return result, nil
 }
@@ -53,8 +53,96 @@ func TestCarpetsToProduct(t *testing.T) {
 
 	carpets := []Carpet{c1, c2, c3, c4}
 
-	product, err := CarpetsToProduct(carpets)
+	product, err := CarpetsToProduct(carpets, nil)
 	require.Nil(t, err)
 	require.NotNil(t, product)
+	require.Equal(t, uint(12), product.Id)
+	require.Equal(t, uint(1), product.CompanyId)
+	require.Equal(t, "106", product.DesignCode)
+	require.Equal(t, []string{"6", "9"}, product.Sizes)
+	require.Equal(t, []string{"آبی", "قرمز"}, product.Colors)
 
+	t.Run("empty", func(t *testing.T) {
+		product, err := CarpetsToProduct(nil, nil)
+		require.Nil(t, err)
+		require.Equal(t, &Product{}, product)
+	})
+
+	t.Run("mixed product", func(t *testing.T) {
+		other := c4
+		other.ProductId = 13
+		product, err := CarpetsToProduct([]Carpet{c1, c2, c3, other}, nil)
+		require.Equal(t, ErrInvalidCarpet, err)
+		require.Nil(t, product)
+	})
+
+	t.Run("mixed company", func(t *testing.T) {
+		other := c4
+		other.CompanyId = 2
+		product, err := CarpetsToProduct([]Carpet{c1, c2, c3, other}, nil)
+		require.Equal(t, ErrInvalidCarpet, err)
+		require.Nil(t, product)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		product, err := CarpetsToProduct([]Carpet{c1, c2, c3, c3}, nil)
+		require.Equal(t, ErrInvalidCarpet, err)
+		require.Nil(t, product)
+	})
+
+	t.Run("incomplete cross product", func(t *testing.T) {
+		product, err := CarpetsToProduct([]Carpet{c1, c2, c3}, nil)
+		require.Equal(t, ErrInvalidNumberOfCarpet, err)
+		require.Nil(t, product)
+	})
+
+	t.Run("excluded carpet", func(t *testing.T) {
+		// c4 (size 9 in آبی) is excluded, exclusion of another product is ignored
+		exclusions := []CarpetExclusion{
+			{ProductId: 12, DimensionId: 13, ThemeId: 13},
+			{ProductId: 15, DimensionId: 14, ThemeId: 14},
+		}
+		product, err := CarpetsToProduct([]Carpet{c1, c2, c3}, exclusions)
+		require.Nil(t, err)
+		require.Equal(t, []string{"6", "9"}, product.Sizes)
+		require.Equal(t, []string{"آبی", "قرمز"}, product.Colors)
+
+		// An excluded carpet can't be listed
+		product, err = CarpetsToProduct(carpets, exclusions)
+		require.Equal(t, ErrInvalidCarpet, err)
+		require.Nil(t, product)
+	})
+}
+
+func TestCarpetsToProducts(t *testing.T) {
+	carpets := []Carpet{
+		{Id: "P2D1T1", CompanyId: 1, ProductId: 2, DimensionId: 1, ThemeId: 1, DesignCode: "105", Dimension: "6", Color: "قرمز"},
+		{Id: "P1D2T2", CompanyId: 1, ProductId: 1, DimensionId: 2, ThemeId: 2, DesignCode: "104", Dimension: "9", Color: "آبی"},
+		{Id: "P2D3T1", CompanyId: 1, ProductId: 2, DimensionId: 3, ThemeId: 1, DesignCode: "105", Dimension: "12", Color: "قرمز"},
+	}
+
+	products, err := CarpetsToProducts(carpets, nil)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(products))
+	require.Equal(t, uint(2), products[0].Id)
+	require.Equal(t, []string{"6", "12"}, products[0].Sizes)
+	require.Equal(t, []string{"قرمز"}, products[0].Colors)
+	require.Equal(t, uint(1), products[1].Id)
+
+	carpets[1].CompanyId = 2
+	products, err = CarpetsToProducts(carpets, nil)
+	require.Equal(t, ErrInvalidCarpet, err)
+	require.Nil(t, products)
+}
+
+func TestProduct_Normalize(t *testing.T) {
+	p := Product{
+		DesignCode: " ۱۰۵A ",
+		Sizes:      []string{"۶", "2x3"},
+		Colors:     []string{"آبي", "سرمه‌اي  روشن"},
+	}
+	p.Normalize()
+	require.Equal(t, "105A", p.DesignCode)
+	require.Equal(t, []string{"6", "2x3"}, p.Sizes)
+	require.Equal(t, []string{"آبی", "سرمه ای روشن"}, p.Colors)
 }
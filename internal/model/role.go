@@ -0,0 +1,18 @@
+package model
+
+// Caller roles
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// RoleCanSeeDrafts check a caller with `role` can see draft products, empty role is a viewer
+func RoleCanSeeDrafts(role string) bool {
+	return role == RoleEditor || role == RoleAdmin
+}
+
+// RoleCanPublish check a caller with `role` can move a product to published or draft status
+func RoleCanPublish(role string) bool {
+	return role == RoleEditor || role == RoleAdmin
+}
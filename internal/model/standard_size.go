@@ -0,0 +1,23 @@
+package model
+
+type (
+	StandardSize struct {
+		Id        uint
+		CompanyId uint
+		Label     string
+		Width     uint // Centimeter
+		Length    uint // Centimeter
+		SortOrder int
+	}
+
+	ProductCode struct {
+		ProductId  uint
+		DesignCode string
+	}
+
+	// StandardSizeProducts lists products that offer a standard size
+	StandardSizeProducts struct {
+		StandardSize StandardSize
+		Products     []ProductCode
+	}
+)
@@ -0,0 +1,39 @@
+package model
+
+// Product lifecycle statuses
+const (
+	StatusDraft        = "draft"
+	StatusPublished    = "published"
+	StatusArchived     = "archived"
+	StatusDiscontinued = "discontinued"
+)
+
+// statusTransitions are allowed next statuses of each status
+var statusTransitions = map[string][]string{
+	StatusDraft:        {StatusPublished, StatusArchived},
+	StatusPublished:    {StatusArchived, StatusDiscontinued},
+	StatusArchived:     {StatusDraft, StatusPublished},
+	StatusDiscontinued: {StatusPublished, StatusArchived},
+}
+
+// StatusIsValid check `status` is one of lifecycle statuses
+func StatusIsValid(status string) bool {
+	_, ok := statusTransitions[status]
+	return ok
+}
+
+// StatusTransitionIsValid check a product in `from` status can move to `to` status
+func StatusTransitionIsValid(from, to string) bool {
+	for _, s := range statusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusAcceptsEntries check new stock and price entries can be added for a product in `status`,
+// a discontinued product stays queryable but doesn't accept new entries
+func StatusAcceptsEntries(status string) bool {
+	return status != StatusDiscontinued
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStatusTransitionIsValid(t *testing.T) {
+	tests := []struct {
+		from  string
+		to    string
+		valid bool
+	}{
+		{StatusDraft, StatusPublished, true},
+		{StatusDraft, StatusDiscontinued, false},
+		{StatusPublished, StatusDiscontinued, true},
+		{StatusPublished, StatusDraft, false},
+		{StatusPublished, StatusPublished, false},
+		{StatusArchived, StatusDraft, true},
+		{StatusDiscontinued, StatusPublished, true},
+		{"", StatusPublished, false},
+		{StatusDraft, "deleted", false},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.valid, StatusTransitionIsValid(tt.from, tt.to), "%v -> %v", tt.from, tt.to)
+	}
+}
+
+func TestStatusIsValid(t *testing.T) {
+	require.True(t, StatusIsValid(StatusDiscontinued))
+	require.False(t, StatusIsValid(""))
+	require.False(t, StatusIsValid("deleted"))
+}
+
+func TestRoleCanSeeDrafts(t *testing.T) {
+	require.True(t, RoleCanSeeDrafts(RoleEditor))
+	require.True(t, RoleCanSeeDrafts(RoleAdmin))
+	require.False(t, RoleCanSeeDrafts(RoleViewer))
+	require.False(t, RoleCanSeeDrafts(""))
+}
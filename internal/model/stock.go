@@ -0,0 +1,181 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidMovement    = errors.New("invalid_movement")
+	ErrInvalidReservation = errors.New("invalid_reservation")
+)
+
+// Reasons of releasing a reservation
+const (
+	ReleaseFulfilled = "fulfilled"
+	ReleaseCancelled = "cancelled"
+	ReleaseExpired   = "expired"
+	ReleaseMerged    = "merged" // Carpet of a merged product has no carpet in target
+)
+
+// Stock movement kinds
+const (
+	MovementReceipt    = "receipt"
+	MovementSale       = "sale"
+	MovementAdjustment = "adjustment"
+	MovementTransfer   = "transfer"
+)
+
+type (
+	Warehouse struct {
+		Id        uint
+		CompanyId uint
+		Name      string
+	}
+
+	// StockMovement is an entry of stock ledger of a carpet in a warehouse.
+	// Quantity of receipt, sale and transfer is positive, quantity of adjustment is signed.
+	// a transfer moves Quantity from WarehouseId to ToWarehouseId.
+	// a sale of reserved pieces has ReservationId and fulfills the reservation
+	StockMovement struct {
+		Id            uint
+		CompanyId     uint
+		WarehouseId   uint
+		ToWarehouseId uint
+		ProductId     uint
+		DimensionId   uint
+		ThemeId       uint
+		Kind          string
+		Quantity      int64
+		ReservationId uint
+		Reference     string // Invoice, order or count sheet of movement
+		Note          string
+		CreatedAt     time.Time
+	}
+
+	// Reservation hold Quantity pieces of a carpet in a warehouse for Holder until ExpiresAt
+	Reservation struct {
+		Id            uint
+		CompanyId     uint
+		WarehouseId   uint
+		ProductId     uint
+		DimensionId   uint
+		ThemeId       uint
+		Quantity      int64
+		Holder        string // Salesperson or customer pieces are promised to
+		Reason        string
+		ExpiresAt     time.Time
+		ReleasedAt    *time.Time
+		ReleaseReason string // One of fulfilled, cancelled, expired
+		CreatedAt     time.Time
+	}
+
+	// ReservationFilter select reservations of a company, zero fields match everything
+	ReservationFilter struct {
+		CompanyId   uint
+		WarehouseId uint
+		ProductId   uint
+		Holder      string
+		ActiveOnly  bool
+	}
+
+	// MovementFilter select entries of stock ledger of a company, zero fields match everything
+	MovementFilter struct {
+		CompanyId   uint
+		WarehouseId uint
+		ProductId   uint
+		DimensionId uint
+		ThemeId     uint
+		Kind        string
+	}
+
+	// StockLevel is quantity of a carpet in a warehouse, Available is on hand pieces that are not reserved
+	StockLevel struct {
+		WarehouseId uint
+		Carpet      Carpet
+		OnHand      int64
+		Reserved    int64
+		Available   int64
+	}
+)
+
+// MovementKindIsValid check `kind` is one of stock movement kinds
+func MovementKindIsValid(kind string) bool {
+	switch kind {
+	case MovementReceipt, MovementSale, MovementAdjustment, MovementTransfer:
+		return true
+	}
+	return false
+}
+
+// Validate check quantity and warehouses of movement match its kind
+func (m StockMovement) Validate() error {
+	if !MovementKindIsValid(m.Kind) {
+		return ErrInvalidMovement
+	}
+
+	if m.Kind == MovementAdjustment {
+		if m.Quantity == 0 {
+			return ErrInvalidMovement
+		}
+	} else if m.Quantity <= 0 {
+		return ErrInvalidMovement
+	}
+
+	if m.Kind == MovementTransfer {
+		if m.ToWarehouseId == 0 || m.ToWarehouseId == m.WarehouseId {
+			return ErrInvalidMovement
+		}
+	} else if m.ToWarehouseId != 0 {
+		return ErrInvalidMovement
+	}
+
+	if m.ReservationId != 0 && m.Kind != MovementSale {
+		return ErrInvalidMovement
+	}
+
+	return nil
+}
+
+// Delta return change of on hand quantity of WarehouseId by movement
+func (m StockMovement) Delta() int64 {
+	switch m.Kind {
+	case MovementReceipt, MovementAdjustment:
+		return m.Quantity
+	case MovementSale, MovementTransfer:
+		return -m.Quantity
+	}
+	return 0
+}
+
+// IncreasesStock check movement adds pieces to stock of company, a transfer only moves pieces
+func (m StockMovement) IncreasesStock() bool {
+	return m.Kind != MovementTransfer && m.Delta() > 0
+}
+
+// ReducesAvailable check movement takes pieces out of a warehouse, that can't take reserved pieces.
+// an adjustment corrects on hand quantity to a count and isn't limited by reservations
+func (m StockMovement) ReducesAvailable() bool {
+	return m.Kind == MovementSale || m.Kind == MovementTransfer
+}
+
+// Validate check quantity of reservation is positive and it has a holder
+func (r Reservation) Validate() error {
+	if r.Quantity <= 0 || r.Holder == "" {
+		return ErrInvalidReservation
+	}
+	return nil
+}
+
+// ActiveAt check reservation is not released and not expired at `t`
+func (r Reservation) ActiveAt(t time.Time) bool {
+	return r.ReleasedAt == nil && t.Before(r.ExpiresAt)
+}
+
+// Available return pieces of `onHand` that are not `reserved`, zero if reservations exceed on hand pieces
+func Available(onHand, reserved int64) int64 {
+	if onHand < reserved {
+		return 0
+	}
+	return onHand - reserved
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestStockMovement_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		movement StockMovement
+		err      error
+	}{
+		{"receipt", StockMovement{WarehouseId: 1, Kind: MovementReceipt, Quantity: 3}, nil},
+		{"negative adjustment", StockMovement{WarehouseId: 1, Kind: MovementAdjustment, Quantity: -2}, nil},
+		{"transfer", StockMovement{WarehouseId: 1, ToWarehouseId: 2, Kind: MovementTransfer, Quantity: 1}, nil},
+		{"invalid kind", StockMovement{WarehouseId: 1, Kind: "return", Quantity: 1}, ErrInvalidMovement},
+		{"zero sale", StockMovement{WarehouseId: 1, Kind: MovementSale}, ErrInvalidMovement},
+		{"negative receipt", StockMovement{WarehouseId: 1, Kind: MovementReceipt, Quantity: -1}, ErrInvalidMovement},
+		{"zero adjustment", StockMovement{WarehouseId: 1, Kind: MovementAdjustment}, ErrInvalidMovement},
+		{"transfer to same warehouse", StockMovement{WarehouseId: 1, ToWarehouseId: 1, Kind: MovementTransfer, Quantity: 1}, ErrInvalidMovement},
+		{"transfer without destination", StockMovement{WarehouseId: 1, Kind: MovementTransfer, Quantity: 1}, ErrInvalidMovement},
+		{"sale with destination", StockMovement{WarehouseId: 1, ToWarehouseId: 2, Kind: MovementSale, Quantity: 1}, ErrInvalidMovement},
+		{"sale of reservation", StockMovement{WarehouseId: 1, Kind: MovementSale, Quantity: 1, ReservationId: 1}, nil},
+		{"receipt of reservation", StockMovement{WarehouseId: 1, Kind: MovementReceipt, Quantity: 1, ReservationId: 1}, ErrInvalidMovement},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.err, tt.movement.Validate())
+		})
+	}
+}
+
+func TestStockMovement_Delta(t *testing.T) {
+	require.Equal(t, int64(3), StockMovement{Kind: MovementReceipt, Quantity: 3}.Delta())
+	require.Equal(t, int64(-3), StockMovement{Kind: MovementSale, Quantity: 3}.Delta())
+	require.Equal(t, int64(-3), StockMovement{Kind: MovementAdjustment, Quantity: -3}.Delta())
+	require.Equal(t, int64(-3), StockMovement{Kind: MovementTransfer, Quantity: 3}.Delta())
+
+	require.True(t, StockMovement{Kind: MovementReceipt, Quantity: 3}.IncreasesStock())
+	require.True(t, StockMovement{Kind: MovementAdjustment, Quantity: 1}.IncreasesStock())
+	require.False(t, StockMovement{Kind: MovementAdjustment, Quantity: -1}.IncreasesStock())
+	require.False(t, StockMovement{Kind: MovementTransfer, Quantity: 3}.IncreasesStock())
+	require.False(t, StockMovement{Kind: MovementSale, Quantity: 3}.IncreasesStock())
+}
+
+func TestReservation_ActiveAt(t *testing.T) {
+	now := time.Now()
+	reservation := Reservation{Quantity: 1, Holder: "فروشگاه شیراز", ExpiresAt: now.Add(time.Hour)}
+	require.Nil(t, reservation.Validate())
+	require.True(t, reservation.ActiveAt(now))
+	require.False(t, reservation.ActiveAt(now.Add(time.Hour)))
+
+	reservation.ReleasedAt = &now
+	require.False(t, reservation.ActiveAt(now))
+
+	require.Equal(t, ErrInvalidReservation, Reservation{Quantity: 1}.Validate())
+	require.Equal(t, ErrInvalidReservation, Reservation{Holder: "فروشگاه شیراز"}.Validate())
+}
+
+func TestAvailable(t *testing.T) {
+	require.Equal(t, int64(3), Available(5, 2))
+	require.Equal(t, int64(0), Available(2, 5))
+}
@@ -0,0 +1,37 @@
+package model
+
+import "github.com/seed95/product-service/pkg/normalize"
+
+// Tag filter matches
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+type (
+	// TagUsage is a tag of a company with number of products that have it
+	TagUsage struct {
+		Name     string
+		Products int64
+	}
+)
+
+// NormalizeTags return normalized form of `tags` without empty and duplicate tags, order of first occurrence is kept
+func NormalizeTags(tags []string) []string {
+	var result []string
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		t = normalize.Key(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		result = append(result, t)
+	}
+	return result
+}
+
+// TagMatchIsValid check `match` is empty (any) or one of tag matches
+func TagMatchIsValid(match string) bool {
+	return match == "" || match == TagMatchAny || match == TagMatchAll
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags := NormalizeTags([]string{"كلاسيك", " کلاسیک ", "", "New  Arrival", "new arrival", "فرش‌دستباف"})
+	require.Equal(t, []string{"کلاسیک", "new arrival", "فرش دستباف"}, tags)
+
+	require.Nil(t, NormalizeTags(nil))
+}
+
+func TestTagMatchIsValid(t *testing.T) {
+	require.True(t, TagMatchIsValid(""))
+	require.True(t, TagMatchIsValid(TagMatchAny))
+	require.True(t, TagMatchIsValid(TagMatchAll))
+	require.False(t, TagMatchIsValid("none"))
+}
@@ -0,0 +1,148 @@
+package product
+
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetProductWithDesignCode return product of `companyId` with `designCode`, or a product with `designCode` as an alias.
+// current design code of a product comes before an alias
+func (r *productRepo) GetProductWithDesignCode(companyId uint, designCode string) (product *schema.Product, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("design_code", designCode),
+			keyval.String("product", fmt.Sprintf("%+v", product)),
+		}
+		logger.LogReqRes(r.logger, "alias.GetProductWithDesignCode", err, commonKeyVal...)
+	}()
+
+	productId, err := productIdOfDesignCode(r.db, companyId, designCode)
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return r.GetProductWithId(productId)
+}
+
+// RenameDesignCode change design code of product `productId` of `companyId` and keep its old code as an alias.
+// `designCode` should not be code or alias of another product, an alias of the product itself is taken back
+func (r *productRepo) RenameDesignCode(companyId, productId uint, designCode string) (product *schema.Product, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("design_code", designCode),
+			keyval.String("product", fmt.Sprintf("%+v", product)),
+		}
+		logger.LogReqRes(r.logger, "alias.RenameDesignCode", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		locked, err := lockProduct(tx, companyId, productId)
+		if err != nil {
+			return err
+		}
+		if locked.DesignCode == designCode {
+			return nil
+		}
+
+		if err := takeAlias(tx, companyId, productId, designCode); err != nil {
+			return err
+		}
+		if err := checkDesignCodeFree(tx, companyId, designCode); err != nil {
+			return err
+		}
+
+		if err := addAlias(tx, companyId, productId, locked.DesignCode); err != nil {
+			return err
+		}
+		return tx.Model(locked).Update("design_code", designCode).Error
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return r.GetProductWithId(productId)
+}
+
+// GetProductAliases return old design codes of product `productId` of `companyId`
+func (r *productRepo) GetProductAliases(companyId, productId uint) (aliases []schema.ProductAlias, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("aliases", fmt.Sprintf("%+v", aliases)),
+		}
+		logger.LogReqRes(r.logger, "alias.GetProductAliases", err, commonKeyVal...)
+	}()
+
+	var count int64
+	if err := r.db.Model(&schema.Product{}).Where("company_id = ? AND id = ?", companyId, productId).Count(&count).Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	if count == 0 {
+		return nil, derror.New(derror.ProductNotFound, fmt.Sprintf("product id %v", productId))
+	}
+
+	aliases = []schema.ProductAlias{}
+	if err := r.db.Where("company_id = ? AND product_id = ?", companyId, productId).Order("id ASC").Find(&aliases).Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	return aliases, nil
+}
+
+// productIdOfDesignCode return id of product of `companyId` with `designCode` as its code or an alias
+func productIdOfDesignCode(db *gorm.DB, companyId uint, designCode string) (uint, error) {
+	var ids []uint
+	if err := db.Model(&schema.Product{}).Where("company_id = ? AND design_code = ?", companyId, designCode).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) != 0 {
+		return ids[0], nil
+	}
+
+	if err := db.Model(&schema.ProductAlias{}).Where("company_id = ? AND design_code = ?", companyId, designCode).Pluck("product_id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) != 0 {
+		return ids[0], nil
+	}
+
+	return 0, derror.New(derror.ProductNotFound, fmt.Sprintf("design code %v", designCode))
+}
+
+// takeAlias delete alias `designCode` of `productId` before it is used as code of product again,
+// if it is an alias of another product return derror.ProductExists
+func takeAlias(tx *gorm.DB, companyId, productId uint, designCode string) error {
+	alias := &schema.ProductAlias{}
+	result := tx.Unscoped().Where("company_id = ? AND design_code = ?", companyId, designCode).Limit(1).Find(alias)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	if alias.ProductId != productId && !alias.DeletedAt.Valid {
+		return derror.New(derror.ProductExists, fmt.Sprintf("design code %v is an alias of product id %v", designCode, alias.ProductId))
+	}
+
+	// A removed alias keeps its code in product_alias_unique_id
+	return tx.Unscoped().Delete(alias).Error
+}
+
+// addAlias keep `designCode` as an alias of `productId`, an existing alias of the code is not changed
+func addAlias(tx *gorm.DB, companyId, productId uint, designCode string) error {
+	alias := &schema.ProductAlias{CompanyId: companyId, DesignCode: designCode, ProductId: productId}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(alias).Error
+}
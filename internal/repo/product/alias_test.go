@@ -0,0 +1,115 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestProductRepo_RenameDesignCode(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := CreateProduct1(pRepo, t)
+	p2 := CreateProduct2(pRepo, t)
+
+	product, err := pRepo.RenameDesignCode(1, p1.ID, "105A")
+	require.Nil(t, err)
+	require.Equal(t, "105A", product.DesignCode)
+
+	// Old and new code find the product
+	product, err = pRepo.GetProductWithDesignCode(1, "105")
+	require.Nil(t, err)
+	require.Equal(t, p1.ID, product.ID)
+	product, err = pRepo.GetProductWithDesignCode(1, "105A")
+	require.Nil(t, err)
+	require.Equal(t, p1.ID, product.ID)
+
+	aliases, err := pRepo.GetProductAliases(1, p1.ID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(aliases))
+	require.Equal(t, "105", aliases[0].DesignCode)
+
+	// Code and alias of another product
+	_, err = pRepo.RenameDesignCode(1, p2.ID, "105A")
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.ProductExists), derror.StatusText(err))
+	_, err = pRepo.RenameDesignCode(1, p2.ID, "105")
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.ProductExists), derror.StatusText(err))
+
+	// Old code is taken back
+	product, err = pRepo.RenameDesignCode(1, p1.ID, "105")
+	require.Nil(t, err)
+	require.Equal(t, "105", product.DesignCode)
+	aliases, err = pRepo.GetProductAliases(1, p1.ID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(aliases))
+	require.Equal(t, "105A", aliases[0].DesignCode)
+
+	// Another company
+	_, err = pRepo.GetProductWithDesignCode(2, "105")
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.ProductNotFound), derror.StatusText(err))
+}
+
+func TestProductRepo_CreateProduct_AliasCode(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := CreateProduct1(pRepo, t)
+	_, err = pRepo.RenameDesignCode(1, p1.ID, "105A")
+	require.Nil(t, err)
+
+	// "105" is an alias of p1
+	_, err = pRepo.CreateProduct(model.Product{CompanyId: 1, DesignCode: "105", Sizes: []string{"6"}, Colors: []string{"قرمز"}})
+	require.Equal(t, derror.StatusText(derror.ProductExists), derror.StatusText(err))
+
+	_, err = pRepo.CloneProduct(model.ProductClone{CompanyId: 1, ProductId: p1.ID, DesignCode: "105"})
+	require.Equal(t, derror.StatusText(derror.ProductExists), derror.StatusText(err))
+
+	rows := []model.ImportRow{{Line: 2, Product: model.Product{CompanyId: 1, DesignCode: "105", Sizes: []string{"6"}, Colors: []string{"قرمز"}}}}
+	results, err := pRepo.ImportProducts(1, rows, 1, false)
+	require.Nil(t, err)
+	require.Equal(t, model.ImportFail, results[0].Action)
+	require.NotEmpty(t, results[0].Error)
+
+	// Alias of another company
+	_, err = pRepo.CreateProduct(model.Product{CompanyId: 2, DesignCode: "105", Sizes: []string{"6"}, Colors: []string{"قرمز"}})
+	require.Nil(t, err)
+}
+
+func TestProductRepo_EditProduct_DesignCodeAlias(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := CreateProduct1(pRepo, t)
+	p2 := CreateProduct2(pRepo, t)
+	_, err = pRepo.RenameDesignCode(1, p2.ID, "106A")
+	require.Nil(t, err)
+
+	edit := model.Product{Id: p1.ID, CompanyId: 1, DesignCode: "106", Sizes: []string{"6", "9"}, Colors: []string{"قرمز", "آبی"}}
+
+	// "106" is an alias of p2
+	_, err = pRepo.EditProduct(edit)
+	require.Equal(t, derror.StatusText(derror.ProductExists), derror.StatusText(err))
+
+	// Old code is kept as an alias
+	edit.DesignCode = "105A"
+	product, err := pRepo.EditProduct(edit)
+	require.Nil(t, err)
+	require.Equal(t, "105A", product.DesignCode)
+	product, err = pRepo.GetProductWithDesignCode(1, "105")
+	require.Nil(t, err)
+	require.Equal(t, p1.ID, product.ID)
+}
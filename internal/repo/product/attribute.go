@@ -0,0 +1,232 @@
+package product
+
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+)
+
+// Sql operators of attribute filters
+var filterOperators = map[string]string{
+	model.FilterEqual:          "=",
+	model.FilterNotEqual:       "<>",
+	model.FilterGreater:        ">",
+	model.FilterGreaterOrEqual: ">=",
+	model.FilterLess:           "<",
+	model.FilterLessOrEqual:    "<=",
+}
+
+// CreateAttributeDefinition add an attribute definition for `definition.CompanyId`,
+// a required attribute can't be added while company has products
+func (r *productRepo) CreateAttributeDefinition(definition model.AttributeDefinition) (schemaDefinition *schema.AttributeDefinition, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("model_definition", fmt.Sprintf("%+v", definition)),
+			keyval.String("schema_definition", fmt.Sprintf("%+v", schemaDefinition)),
+		}
+		logger.LogReqRes(r.logger, "attribute.CreateAttributeDefinition", err, commonKeyVal...)
+	}()
+
+	schemaDefinition = schema.AttributeDefinitionModelToSchema(definition)
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(schemaDefinition).Error; err != nil {
+			return err
+		}
+		return checkRequiredAttributeSet(tx, schemaDefinition)
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return schemaDefinition, nil
+}
+
+// GetAttributeDefinitions return all attribute definitions of `companyId`
+func (r *productRepo) GetAttributeDefinitions(companyId uint) (definitions []schema.AttributeDefinition, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("definitions", fmt.Sprintf("%+v", definitions)),
+		}
+		logger.LogReqRes(r.logger, "attribute.GetAttributeDefinitions", err, commonKeyVal...)
+	}()
+
+	definitions, err = getAttributeDefinitions(r.db, companyId)
+	return definitions, err
+}
+
+// EditAttributeDefinition update an attribute definition, values of products should be valid for edited definition
+// and a required attribute should have a value in every product of company
+func (r *productRepo) EditAttributeDefinition(definition model.AttributeDefinition) (schemaDefinition *schema.AttributeDefinition, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("model_definition", fmt.Sprintf("%+v", definition)),
+			keyval.String("schema_definition", fmt.Sprintf("%+v", schemaDefinition)),
+		}
+		logger.LogReqRes(r.logger, "attribute.EditAttributeDefinition", err, commonKeyVal...)
+	}()
+
+	schemaDefinition = schema.AttributeDefinitionModelToSchema(definition)
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&schema.AttributeDefinition{}).
+			Where("id = ? AND company_id = ?", schemaDefinition.ID, schemaDefinition.CompanyId).
+			Updates(map[string]interface{}{
+				"name":     schemaDefinition.Name,
+				"type":     schemaDefinition.Type,
+				"unit":     schemaDefinition.Unit,
+				"required": schemaDefinition.Required,
+				"options":  schemaDefinition.Options,
+			})
+		if err := result.Error; err != nil {
+			return err
+		} else if result.RowsAffected < 1 {
+			return derror.AttributeNotFound
+		}
+
+		if err := checkRequiredAttributeSet(tx, schemaDefinition); err != nil {
+			return err
+		}
+
+		// Existing values should be valid for edited definition
+		var values []schema.ProductAttribute
+		if err := tx.Where("attribute_id = ?", schemaDefinition.ID).Find(&values).Error; err != nil {
+			return err
+		}
+		for _, v := range values {
+			normalized, err := definition.Normalize(v.Value)
+			if err != nil {
+				return derror.New(derror.InvalidAttribute, err.Error())
+			}
+			if normalized == v.Value {
+				continue
+			}
+			if err := tx.Model(&v).Update("value", normalized).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.First(schemaDefinition).Error
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return schemaDefinition, nil
+}
+
+// checkRequiredAttributeSet return derror.InvalidAttribute if `definition` is required
+// and a product of its company has no value of it
+func checkRequiredAttributeSet(tx *gorm.DB, definition *schema.AttributeDefinition) error {
+	if !definition.Required {
+		return nil
+	}
+
+	var productIds []uint
+	result := tx.Model(&schema.Product{}).
+		Where("company_id = ? AND id NOT IN (?)", definition.CompanyId,
+			tx.Model(&schema.ProductAttribute{}).Select("product_id").Where("attribute_id = ?", definition.ID)).
+		Order("id").Pluck("id", &productIds)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if len(productIds) != 0 {
+		return derror.New(derror.InvalidAttribute, fmt.Sprintf("product ids %v have no value of %v", productIds, definition.Name))
+	}
+	return nil
+}
+
+// DeleteAttributeDefinition soft delete an attribute definition, a definition with product values can't delete
+func (r *productRepo) DeleteAttributeDefinition(companyId, attributeId uint) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("attribute_id", fmt.Sprintf("%v", attributeId)),
+		}
+		logger.LogReqRes(r.logger, "attribute.DeleteAttributeDefinition", err, commonKeyVal...)
+	}()
+
+	var used int64
+	if err := r.db.Model(&schema.ProductAttribute{}).Where("attribute_id = ?", attributeId).Count(&used).Error; err != nil {
+		return derror.New(derror.InternalServer, err.Error())
+	}
+	if used != 0 {
+		return derror.AttributeInUse
+	}
+
+	tx := r.db.Where("company_id = ?", companyId).Delete(&schema.AttributeDefinition{Model: gorm.Model{ID: attributeId}})
+	if err := tx.Error; err != nil {
+		return derror.New(derror.InternalServer, err.Error())
+	} else if tx.RowsAffected < 1 {
+		return derror.AttributeNotFound
+	}
+
+	return nil
+}
+
+func getAttributeDefinitions(db *gorm.DB, companyId uint) (definitions []schema.AttributeDefinition, err error) {
+	tx := db.Order("id ASC").Where("company_id = ?", companyId).Find(&definitions)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	return definitions, nil
+}
+
+// withAttributes validate `values` against attribute definitions of `companyId`
+// and return product attributes with their definitions
+func withAttributes(db *gorm.DB, companyId, productId uint, values map[string]string) ([]schema.ProductAttribute, error) {
+	definitions, err := getAttributeDefinitions(db, companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	modelDefinitions := make([]model.AttributeDefinition, len(definitions))
+	for i := range definitions {
+		modelDefinitions[i] = schema.AttributeDefinitionToModel(&definitions[i])
+	}
+
+	normalized, err := model.NormalizeAttributes(modelDefinitions, values)
+	if err != nil {
+		return nil, derror.New(derror.InvalidAttribute, err.Error())
+	}
+
+	var attributes []schema.ProductAttribute
+	for _, d := range definitions {
+		value, ok := normalized[d.Name]
+		if !ok {
+			continue
+		}
+		attributes = append(attributes, schema.ProductAttribute{
+			ProductId:   productId,
+			AttributeId: d.ID,
+			Attribute:   d,
+			Value:       value,
+		})
+	}
+
+	return attributes, nil
+}
+
+// replaceAttributes replace all attribute values of `productId` with `attributes`
+func replaceAttributes(tx *gorm.DB, productId uint, attributes []schema.ProductAttribute) error {
+	if err := tx.Unscoped().Where("product_id = ?", productId).Delete(&schema.ProductAttribute{}).Error; err != nil {
+		return err
+	}
+
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	return tx.Omit("Attribute").Create(&attributes).Error
+}
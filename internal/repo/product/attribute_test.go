@@ -0,0 +1,299 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func CreateAttributeDefinitions(repo *productRepo, t *testing.T) (material, shaneh *schema.AttributeDefinition) {
+	material, err := repo.CreateAttributeDefinition(model.AttributeDefinition{
+		CompanyId: 1,
+		Name:      "material",
+		Type:      model.AttributeEnum,
+		Required:  true,
+		Options:   []string{"پشم", "ابریشم"},
+	})
+	require.Nil(t, err)
+
+	shaneh, err = repo.CreateAttributeDefinition(model.AttributeDefinition{
+		CompanyId: 1,
+		Name:      "shaneh",
+		Type:      model.AttributeNumber,
+	})
+	require.Nil(t, err)
+	return material, shaneh
+}
+
+func TestProductRepo_CreateAttributeDefinition_Duplicate(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = CreateAttributeDefinitions(pRepo, t)
+
+	d, err := pRepo.CreateAttributeDefinition(model.AttributeDefinition{CompanyId: 1, Name: "shaneh", Type: model.AttributeString})
+	require.NotNil(t, err)
+	require.Nil(t, d)
+
+	definitions, err := pRepo.GetAttributeDefinitions(1)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(definitions))
+	require.Equal(t, []string{"پشم", "ابریشم"}, []string(definitions[0].Options))
+}
+
+func TestProductRepo_CreateProduct_Attributes(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = CreateAttributeDefinitions(pRepo, t)
+
+	p1 := model.Product{
+		CompanyId:  1,
+		DesignCode: "105",
+		Colors:     []string{"قرمز"},
+		Sizes:      []string{"6"},
+		Attributes: map[string]string{"material": "ابریشم", "shaneh": "70.0"},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		p, err := pRepo.CreateProduct(p1)
+		require.Nil(t, err)
+
+		gotP, err := pRepo.GetProductWithId(p.ID)
+		require.Nil(t, err)
+		require.Equal(t, map[string]string{"material": "ابریشم", "shaneh": "70"}, schema.GetAttributes(gotP.Attributes))
+	})
+
+	t.Run("required", func(t *testing.T) {
+		p2 := p1
+		p2.DesignCode = "106"
+		p2.Attributes = map[string]string{"shaneh": "70"}
+		p, err := pRepo.CreateProduct(p2)
+		require.Equal(t, derror.StatusText(derror.InvalidAttribute), derror.StatusText(err))
+		require.Nil(t, p)
+	})
+
+	t.Run("invalid option", func(t *testing.T) {
+		p2 := p1
+		p2.DesignCode = "106"
+		p2.Attributes = map[string]string{"material": "نخ"}
+		p, err := pRepo.CreateProduct(p2)
+		require.Equal(t, derror.StatusText(derror.InvalidAttribute), derror.StatusText(err))
+		require.Nil(t, p)
+	})
+}
+
+func TestProductRepo_EditProduct_Attributes(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = CreateAttributeDefinitions(pRepo, t)
+
+	p1 := model.Product{
+		CompanyId:  1,
+		DesignCode: "105",
+		Colors:     []string{"قرمز"},
+		Sizes:      []string{"6"},
+		Attributes: map[string]string{"material": "ابریشم", "shaneh": "70"},
+	}
+	p, err := pRepo.CreateProduct(p1)
+	require.Nil(t, err)
+
+	p1.Id = p.ID
+	p1.Attributes = map[string]string{"material": "پشم"}
+	_, err = pRepo.EditProduct(p1)
+	require.Nil(t, err)
+
+	gotP, err := pRepo.GetProductWithId(p.ID)
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"material": "پشم"}, schema.GetAttributes(gotP.Attributes))
+}
+
+func TestProductRepo_DeleteAttributeDefinition(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	material, shaneh := CreateAttributeDefinitions(pRepo, t)
+	_, err = pRepo.CreateProduct(model.Product{
+		CompanyId:  1,
+		DesignCode: "105",
+		Colors:     []string{"قرمز"},
+		Sizes:      []string{"6"},
+		Attributes: map[string]string{"material": "ابریشم"},
+	})
+	require.Nil(t, err)
+
+	require.Equal(t, derror.AttributeInUse, pRepo.DeleteAttributeDefinition(1, material.ID))
+	require.Nil(t, pRepo.DeleteAttributeDefinition(1, shaneh.ID))
+	require.Equal(t, derror.AttributeNotFound, pRepo.DeleteAttributeDefinition(1, shaneh.ID))
+}
+
+func TestProductRepo_EditAttributeDefinition_InvalidValues(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	material, _ := CreateAttributeDefinitions(pRepo, t)
+	_, err = pRepo.CreateProduct(model.Product{
+		CompanyId:  1,
+		DesignCode: "105",
+		Colors:     []string{"قرمز"},
+		Sizes:      []string{"6"},
+		Attributes: map[string]string{"material": "ابریشم"},
+	})
+	require.Nil(t, err)
+
+	// Removing an option in use
+	edited := schema.AttributeDefinitionToModel(material)
+	edited.Options = []string{"پشم"}
+	d, err := pRepo.EditAttributeDefinition(edited)
+	require.Equal(t, derror.StatusText(derror.InvalidAttribute), derror.StatusText(err))
+	require.Nil(t, d)
+
+	edited.Options = []string{"پشم", "ابریشم", "نخ"}
+	d, err = pRepo.EditAttributeDefinition(edited)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(d.Options))
+}
+
+func TestProductRepo_EditAttributeDefinition_Required(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, shaneh := CreateAttributeDefinitions(pRepo, t)
+	p, err := pRepo.CreateProduct(model.Product{
+		CompanyId:  1,
+		DesignCode: "105",
+		Colors:     []string{"قرمز"},
+		Sizes:      []string{"6"},
+		Attributes: map[string]string{"material": "ابریشم"},
+	})
+	require.Nil(t, err)
+
+	// A product has no value of shaneh
+	edited := schema.AttributeDefinitionToModel(shaneh)
+	edited.Required = true
+	d, err := pRepo.EditAttributeDefinition(edited)
+	require.Equal(t, derror.StatusText(derror.InvalidAttribute), derror.StatusText(err))
+	require.Nil(t, d)
+
+	_, err = pRepo.EditProduct(model.Product{
+		Id:         p.ID,
+		CompanyId:  1,
+		DesignCode: "105",
+		Colors:     []string{"قرمز"},
+		Sizes:      []string{"6"},
+		Attributes: map[string]string{"material": "ابریشم", "shaneh": "70"},
+	})
+	require.Nil(t, err)
+	d, err = pRepo.EditAttributeDefinition(edited)
+	require.Nil(t, err)
+	require.True(t, d.Required)
+
+	// A new required attribute has no value in existing products
+	d, err = pRepo.CreateAttributeDefinition(model.AttributeDefinition{CompanyId: 1, Name: "knots", Type: model.AttributeNumber, Required: true})
+	require.Equal(t, derror.StatusText(derror.InvalidAttribute), derror.StatusText(err))
+	require.Nil(t, d)
+
+	// Another company has no products
+	d, err = pRepo.CreateAttributeDefinition(model.AttributeDefinition{CompanyId: 2, Name: "knots", Type: model.AttributeNumber, Required: true})
+	require.Nil(t, err)
+	require.True(t, d.Required)
+}
+
+func TestProductRepo_SearchProducts_Attributes(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = CreateAttributeDefinitions(pRepo, t)
+	for code, attributes := range map[string]map[string]string{
+		"105": {"material": "ابریشم", "shaneh": "70"},
+		"106": {"material": "پشم", "shaneh": "50"},
+		"107": {"material": "پشم"},
+	} {
+		_, err := pRepo.CreateProduct(model.Product{
+			CompanyId:  1,
+			DesignCode: code,
+			Colors:     []string{"قرمز"},
+			Sizes:      []string{"6"},
+			Attributes: attributes,
+		})
+		require.Nil(t, err)
+	}
+
+	tests := []struct {
+		Name    string
+		Filters []model.AttributeFilter
+		Codes   []string
+	}{
+		{
+			Name:    "Equal",
+			Filters: []model.AttributeFilter{{Name: "material", Operator: model.FilterEqual, Value: "پشم"}},
+			Codes:   []string{"106", "107"},
+		},
+		{
+			Name:    "NotEqual",
+			Filters: []model.AttributeFilter{{Name: "material", Operator: model.FilterNotEqual, Value: "پشم"}},
+			Codes:   []string{"105"},
+		},
+		{
+			Name:    "NotEqualWithoutValue",
+			Filters: []model.AttributeFilter{{Name: "shaneh", Operator: model.FilterNotEqual, Value: "70"}},
+			Codes:   []string{"106"},
+		},
+		{
+			Name:    "Greater",
+			Filters: []model.AttributeFilter{{Name: "shaneh", Operator: model.FilterGreater, Value: "60"}},
+			Codes:   []string{"105"},
+		},
+		{
+			Name: "All",
+			Filters: []model.AttributeFilter{
+				{Name: "material", Operator: model.FilterEqual, Value: "پشم"},
+				{Name: "shaneh", Operator: model.FilterLessOrEqual, Value: "50"},
+			},
+			Codes: []string{"106"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			products, err := pRepo.SearchProducts(model.ProductFilter{CompanyId: 1, Attributes: tt.Filters})
+			require.Nil(t, err)
+			var codes []string
+			for _, p := range products {
+				codes = append(codes, p.DesignCode)
+			}
+			require.ElementsMatch(t, tt.Codes, codes)
+		})
+	}
+
+	t.Run("unknown attribute", func(t *testing.T) {
+		filter := model.ProductFilter{CompanyId: 1, Attributes: []model.AttributeFilter{{Name: "origin", Operator: model.FilterEqual, Value: "کاشان"}}}
+		products, err := pRepo.SearchProducts(filter)
+		require.Equal(t, derror.StatusText(derror.AttributeNotFound), derror.StatusText(err))
+		require.Nil(t, products)
+	})
+}
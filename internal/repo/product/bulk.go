@@ -0,0 +1,240 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"github.com/seed95/product-service/pkg/normalize"
+	"gorm.io/gorm"
+)
+
+// BulkEditProducts apply `edit` to every product of `edit.Filter` in a transaction per product,
+// a failed product is rolled back alone and a dry run rolls back every product
+func (r *productRepo) BulkEditProducts(edit model.BulkEdit) (results []model.BulkEditResult, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("edit", fmt.Sprintf("%+v", edit)),
+			keyval.String("results", fmt.Sprintf("%+v", results)),
+		}
+		logger.LogReqRes(r.logger, "bulk.BulkEditProducts", err, commonKeyVal...)
+	}()
+
+	query, err := filterProducts(r.db, edit.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var products []schema.Product
+	if err := query.Select("tbl_product.id", "tbl_product.company_id", "tbl_product.design_code").
+		Order("tbl_product.id ASC").Find(&products).Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	results = make([]model.BulkEditResult, len(products))
+	for i, product := range products {
+		result := model.BulkEditResult{ProductId: product.ID, DesignCode: product.DesignCode}
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			if err := r.bulkEditProduct(tx, product, edit, &result); err != nil {
+				return err
+			}
+			if edit.DryRun {
+				return errDryRun
+			}
+			return nil
+		})
+
+		if err != nil && !errors.Is(err, errDryRun) {
+			result = model.BulkEditResult{ProductId: product.ID, DesignCode: product.DesignCode, Error: err.Error()}
+		}
+		results[i] = result
+	}
+
+	return results, nil
+}
+
+// bulkEditProduct apply `edit` to `product` in `tx` and record changes in `result`
+func (r *productRepo) bulkEditProduct(tx *gorm.DB, product schema.Product, edit model.BulkEdit, result *model.BulkEditResult) error {
+	themes, err := r.theme.GetThemesWithProductId(tx, product.ID)
+	if err != nil {
+		return err
+	}
+	dimensions, err := r.dimension.GetDimensionsWithProductId(tx, product.ID)
+	if err != nil {
+		return err
+	}
+
+	var removedThemes []schema.Theme
+	for _, t := range themes {
+		if containsLabel(edit.RemoveColors, t.Color) {
+			removedThemes = append(removedThemes, t)
+			result.RemovedColors = append(result.RemovedColors, t.Color)
+		}
+	}
+	for _, c := range edit.AddColors {
+		if !themesHaveColor(themes, c) && !containsLabel(result.AddedColors, c) {
+			result.AddedColors = append(result.AddedColors, c)
+		}
+	}
+	if len(themes)-len(removedThemes)+len(result.AddedColors) == 0 {
+		return derror.New(derror.InvalidProduct, "no color is left")
+	}
+
+	var removedDimensions []schema.Dimension
+	for _, d := range dimensions {
+		if containsLabel(edit.RemoveSizes, d.Size) {
+			removedDimensions = append(removedDimensions, d)
+			result.RemovedSizes = append(result.RemovedSizes, d.Size)
+		}
+	}
+	for _, s := range edit.AddSizes {
+		if !dimensionsHaveSize(dimensions, s) && !containsLabel(result.AddedSizes, s) {
+			result.AddedSizes = append(result.AddedSizes, s)
+		}
+	}
+	if len(dimensions)-len(removedDimensions)+len(result.AddedSizes) == 0 {
+		return derror.New(derror.InvalidProduct, "no size is left")
+	}
+
+	if err := checkRemovedCarpetsNotInStock(tx, product.ID, removedDimensions, removedThemes); err != nil {
+		return err
+	}
+
+	if len(removedThemes) != 0 {
+		if err := r.theme.DeleteThemesWithId(tx, product.ID, removedThemes); err != nil {
+			return err
+		}
+	}
+	if len(result.AddedColors) != 0 {
+		if _, err := r.theme.InsertThemesWithColor(tx, product.ID, result.AddedColors); err != nil {
+			return err
+		}
+	}
+	if len(removedDimensions) != 0 {
+		if err := r.dimension.DeleteDimensionsWithId(tx, product.ID, removedDimensions); err != nil {
+			return err
+		}
+	}
+	if len(result.AddedSizes) != 0 {
+		if _, err := r.dimension.InsertDimensions(tx, product.ID, result.AddedSizes); err != nil {
+			return err
+		}
+	}
+
+	// Carpets of deleted sizes and colors lose their prices and exclusions
+	if len(removedThemes) != 0 || len(removedDimensions) != 0 {
+		if err := deleteDetachedPrices(tx, product.ID); err != nil {
+			return err
+		}
+		if err := deleteDetachedExclusions(tx, product.ID); err != nil {
+			return err
+		}
+	}
+
+	if len(edit.Attributes) != 0 {
+		changed, err := mergeAttributes(tx, product, edit.Attributes)
+		if err != nil {
+			return err
+		}
+		result.Attributes = changed
+	}
+
+	return nil
+}
+
+// mergeAttributes set `values` on attribute values of `product` and report a change,
+// attributes not in `values` keep their values
+func mergeAttributes(tx *gorm.DB, product schema.Product, values map[string]string) (bool, error) {
+	var current []schema.ProductAttribute
+	if err := tx.Preload("Attribute").Where("product_id = ?", product.ID).Find(&current).Error; err != nil {
+		return false, err
+	}
+
+	merged := make(map[string]string, len(current)+len(values))
+	for _, a := range current {
+		merged[a.Attribute.Name] = a.Value
+	}
+	for name, value := range values {
+		merged[name] = value
+	}
+
+	attributes, err := withAttributes(tx, product.CompanyId, product.ID, merged)
+	if err != nil {
+		return false, err
+	}
+
+	if sameAttributes(current, attributes) {
+		return false, nil
+	}
+	return true, replaceAttributes(tx, product.ID, attributes)
+}
+
+func sameAttributes(a, b []schema.ProductAttribute) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	values := make(map[uint]string, len(a))
+	for _, attribute := range a {
+		values[attribute.AttributeId] = attribute.Value
+	}
+	for _, attribute := range b {
+		if value, ok := values[attribute.AttributeId]; !ok || value != attribute.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// checkRemovedCarpetsNotInStock return derror.CarpetInStock if a carpet of removed dimensions or themes has pieces on hand
+func checkRemovedCarpetsNotInStock(tx *gorm.DB, productId uint, dimensions []schema.Dimension, themes []schema.Theme) error {
+	if len(dimensions) == 0 && len(themes) == 0 {
+		return nil
+	}
+
+	dimensionIds := []uint{0}
+	for _, d := range dimensions {
+		dimensionIds = append(dimensionIds, d.ID)
+	}
+	themeIds := []uint{0}
+	for _, t := range themes {
+		themeIds = append(themeIds, t.ID)
+	}
+
+	var onHand int64
+	stock := tx.Model(&schema.Stock{}).Select("COALESCE(SUM(on_hand), 0)").
+		Where("product_id = ? AND (dimension_id IN ? OR theme_id IN ?)", productId, dimensionIds, themeIds).
+		Scan(&onHand)
+	if err := stock.Error; err != nil {
+		return err
+	}
+	if onHand != 0 {
+		return derror.New(derror.CarpetInStock, fmt.Sprintf("%v pieces on hand", onHand))
+	}
+	return nil
+}
+
+// themesHaveColor report whether one of `themes` has `color`, a color stored before normalization matches too
+func themesHaveColor(themes []schema.Theme, color string) bool {
+	_, found := themeWithColor(themes, color)
+	return found
+}
+
+// dimensionsHaveSize report whether one of `dimensions` has `size`, a size stored before normalization matches too
+func dimensionsHaveSize(dimensions []schema.Dimension, size string) bool {
+	_, found := dimensionWithSize(dimensions, size)
+	return found
+}
+
+// containsLabel report whether `list` has `label` compared by normalize.Key
+func containsLabel(list []string, label string) bool {
+	for _, item := range list {
+		if normalize.Key(item) == normalize.Key(label) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestProductRepo_BulkEditProducts(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := CreateProduct1(pRepo, t)
+	p2 := CreateProduct2(pRepo, t)
+
+	edit := model.BulkEdit{
+		Filter:      model.ProductFilter{CompanyId: 1, ProductIds: []uint{p1.ID, p2.ID}},
+		AddColors:   []string{"سبز", "قرمز"},
+		RemoveSizes: []string{"9"},
+		DryRun:      true,
+	}
+
+	// Dry run reports changes without saving
+	results, err := pRepo.BulkEditProducts(edit)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(results))
+	for _, r := range results {
+		require.Equal(t, "", r.Error)
+		require.Equal(t, []string{"سبز"}, r.AddedColors)
+		require.Equal(t, []string{"9"}, r.RemovedSizes)
+	}
+	product, err := pRepo.GetProductWithId(p1.ID)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(product.Themes))
+	require.Equal(t, 2, len(product.Dimensions))
+
+	edit.DryRun = false
+	results, err = pRepo.BulkEditProducts(edit)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(results))
+	product, err = pRepo.GetProductWithId(p2.ID)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(product.Themes))
+	require.Equal(t, 1, len(product.Dimensions))
+	require.Equal(t, "6", product.Dimensions[0].Size)
+
+	// Same edit again changes nothing
+	results, err = pRepo.BulkEditProducts(edit)
+	require.Nil(t, err)
+	require.False(t, results[0].Changed())
+
+	// Last size of a product can't be removed
+	results, err = pRepo.BulkEditProducts(model.BulkEdit{
+		Filter:      model.ProductFilter{CompanyId: 1, ProductIds: []uint{p1.ID}},
+		RemoveSizes: []string{"6"},
+	})
+	require.Nil(t, err)
+	require.NotEqual(t, "", results[0].Error)
+}
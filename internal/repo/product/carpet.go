@@ -1,17 +1,24 @@
 package product
 
 import (
+	"errors"
+	"fmt"
 	"github.com/seed95/product-service/internal/derror"
 	"github.com/seed95/product-service/internal/model"
 	"github.com/seed95/product-service/internal/repo/product/schema"
-	"strconv"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"github.com/seed95/product-service/pkg/sku"
+	"gorm.io/gorm"
 )
 
 // GetAllCarpet return all carpets for `companyId` in view
 func (r *productRepo) GetAllCarpet(companyId uint) ([]model.Carpet, error) {
 	var schemaCarpets []schema.Carpet
-	viewName := "view_carpet_company_id_" + strconv.FormatUint(uint64(companyId), 10)
-	if err := r.db.Table(viewName).Find(&schemaCarpets).Error; err != nil {
+	err := r.withCarpetView(companyId, func(view string) error {
+		return r.db.Table(view).Find(&schemaCarpets).Error
+	})
+	if err != nil {
 		return nil, derror.New(derror.InternalServer, err.Error())
 	}
 
@@ -20,15 +27,20 @@ func (r *productRepo) GetAllCarpet(companyId uint) ([]model.Carpet, error) {
 		carpets[i] = schema.CarpetToModel(&c, companyId)
 	}
 
+	if err := setCarpetsStock(r.db, carpets); err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
 	return carpets, nil
 }
 
 // GetAllCarpetWithProductId return all carpets for `companyId` and `productId` in view
 func (r *productRepo) GetAllCarpetWithProductId(companyId, productId uint) ([]model.Carpet, error) {
-
 	var schemaCarpets []schema.Carpet
-	viewName := "view_carpet_company_id_" + strconv.FormatUint(uint64(companyId), 10)
-	if err := r.db.Table(viewName).Where("product_id = ?", productId).Find(&schemaCarpets).Error; err != nil {
+	err := r.withCarpetView(companyId, func(view string) error {
+		return r.db.Table(view).Where("product_id = ?", productId).Find(&schemaCarpets).Error
+	})
+	if err != nil {
 		return nil, derror.New(derror.InternalServer, err.Error())
 	}
 
@@ -36,5 +48,95 @@ func (r *productRepo) GetAllCarpetWithProductId(companyId, productId uint) ([]mo
 	for i, c := range schemaCarpets {
 		carpets[i] = schema.CarpetToModel(&c, companyId)
 	}
+
+	if err := setCarpetsStock(r.db, carpets); err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
 	return carpets, nil
 }
+
+// SearchCarpets return a page of carpets of products that match `filter` and total number of matched carpets
+func (r *productRepo) SearchCarpets(filter model.ProductFilter, page model.Page) (carpets []model.Carpet, total int64, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("filter", fmt.Sprintf("%+v", filter)),
+			keyval.String("page", fmt.Sprintf("%+v", page)),
+			keyval.String("total", fmt.Sprintf("%v", total)),
+		}
+		logger.LogReqRes(r.logger, "carpet.SearchCarpets", err, commonKeyVal...)
+	}()
+
+	page = page.Normalize()
+
+	products, err := filterProducts(r.db, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var schemaCarpets []schema.Carpet
+	err = r.withCarpetView(filter.CompanyId, func(view string) error {
+		query := func() *gorm.DB {
+			return r.db.Table(view).Where("product_id IN (?)", products.Select("tbl_product.id"))
+		}
+
+		if err := query().Count(&total).Error; err != nil {
+			return err
+		}
+
+		return query().Order("product_id ASC").Order("dimension_id ASC").Order("theme_id ASC").
+			Offset(page.Offset()).Limit(page.Size).Find(&schemaCarpets).Error
+	})
+	if err != nil {
+		return nil, 0, derror.New(derror.InternalServer, err.Error())
+	}
+
+	carpets = make([]model.Carpet, len(schemaCarpets))
+	for i, c := range schemaCarpets {
+		carpets[i] = schema.CarpetToModel(&c, filter.CompanyId)
+	}
+
+	if err := setCarpetsStock(r.db, carpets); err != nil {
+		return nil, 0, derror.New(derror.InternalServer, err.Error())
+	}
+
+	return carpets, total, nil
+}
+
+// GetCarpet return carpet of `carpetSku` if its product match `filter`
+func (r *productRepo) GetCarpet(filter model.ProductFilter, carpetSku sku.SKU) (carpet *model.Carpet, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("filter", fmt.Sprintf("%+v", filter)),
+			keyval.String("carpet_sku", sku.Encode(carpetSku)),
+			keyval.String("carpet", fmt.Sprintf("%+v", carpet)),
+		}
+		logger.LogReqRes(r.logger, "carpet.GetCarpet", err, commonKeyVal...)
+	}()
+
+	products, err := filterProducts(r.db, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaCarpet := schema.Carpet{}
+	err = r.withCarpetView(filter.CompanyId, func(view string) error {
+		return r.db.Table(view).
+			Where("product_id = ? AND dimension_id = ? AND theme_id = ?", carpetSku.ProductId, carpetSku.DimensionId, carpetSku.ThemeId).
+			Where("product_id IN (?)", products.Select("tbl_product.id")).
+			Take(&schemaCarpet).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, derror.CarpetNotFound
+		}
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	result := []model.Carpet{schema.CarpetToModel(&schemaCarpet, filter.CompanyId)}
+	if err := setCarpetsStock(r.db, result); err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	return &result[0], nil
+}
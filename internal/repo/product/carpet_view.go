@@ -0,0 +1,92 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jackc/pgconn"
+	"github.com/seed95/product-service/pkg/sku"
+	"gorm.io/gorm"
+	"strconv"
+)
+
+// undefinedTable is postgres error code of a missing table or view
+const undefinedTable = "42P01"
+
+// carpetViewQuery select carpets (every live dimension with every live theme that is not excluded)
+// of live products of a company (format arg)
+var carpetViewQuery = "SELECT " + sku.SQL("p.id", "d.id", "t.id") + " AS id, " +
+	"p.id AS product_id, d.id AS dimension_id, t.id AS theme_id, p.design_code, d.size, t.color " +
+	"FROM tbl_product p " +
+	"JOIN tbl_dimension d ON d.product_id = p.id AND d.deleted_at IS NULL " +
+	"JOIN tbl_theme t ON t.product_id = p.id AND t.deleted_at IS NULL " +
+	"WHERE p.company_id = %d AND p.deleted_at IS NULL " +
+	"AND NOT EXISTS (SELECT 1 FROM tbl_carpet_exclusion e WHERE e.product_id = p.id AND e.dimension_id = d.id AND e.theme_id = t.id)"
+
+func carpetViewName(companyId uint) string {
+	return "view_carpet_company_id_" + strconv.FormatUint(uint64(companyId), 10)
+}
+
+// carpetView create carpet view of `companyId` once per repo and return its name
+func (r *productRepo) carpetView(companyId uint) (string, error) {
+	name := carpetViewName(companyId)
+	if _, ok := r.carpetViews.Load(companyId); ok {
+		return name, nil
+	}
+
+	query := fmt.Sprintf("CREATE OR REPLACE VIEW %s AS "+carpetViewQuery, name, companyId)
+	if err := r.db.Exec(query).Error; err != nil {
+		return "", err
+	}
+
+	r.carpetViews.Store(companyId, true)
+	return name, nil
+}
+
+// withCarpetView run `fn` with carpet view of `companyId`. a view that is dropped after this repo created it
+// (migration, refresh of another instance, restore into a fresh database) is forgotten, created again and `fn` is run once more
+func (r *productRepo) withCarpetView(companyId uint, fn func(view string) error) error {
+	view, err := r.carpetView(companyId)
+	if err != nil {
+		return err
+	}
+
+	err = fn(view)
+	if !isUndefinedTable(err) {
+		return err
+	}
+
+	r.carpetViews.Delete(companyId)
+	if view, err = r.carpetView(companyId); err != nil {
+		return err
+	}
+	return fn(view)
+}
+
+// isUndefinedTable check `err` is a query on a missing table or view
+func isUndefinedTable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == undefinedTable
+}
+
+// refreshCarpetViews recreate carpet views of every company that has products,
+// so views created by hand or by an older definition follow the current one
+func (r *productRepo) refreshCarpetViews() error {
+	var companyIds []uint
+	if err := r.db.Table("tbl_product").Distinct("company_id").Pluck("company_id", &companyIds).Error; err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, companyId := range companyIds {
+			name := carpetViewName(companyId)
+			if err := tx.Exec("DROP VIEW IF EXISTS " + name).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(fmt.Sprintf("CREATE VIEW %s AS "+carpetViewQuery, name, companyId)).Error; err != nil {
+				return err
+			}
+			r.carpetViews.Store(companyId, true)
+		}
+		return nil
+	})
+}
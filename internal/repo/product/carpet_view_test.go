@@ -0,0 +1,99 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/pkg/sku"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestProductRepo_CarpetView_FirstProduct(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Company without product has no carpet
+	carpets, err := pRepo.GetAllCarpet(1000)
+	require.Nil(t, err)
+	require.Equal(t, 0, len(carpets))
+
+	_, err = pRepo.CreateProduct(model.Product{CompanyId: 1001, DesignCode: "105", Colors: []string{"قرمز", "آبی"}, Sizes: []string{"6", "9"}})
+	require.Nil(t, err)
+
+	carpets, err = pRepo.GetAllCarpet(1001)
+	require.Nil(t, err)
+	require.Equal(t, 4, len(carpets))
+}
+
+func TestProductRepo_CarpetView_Deleted(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+
+	// Remove a color
+	_, err = pRepo.EditProduct(model.Product{Id: p.ID, DesignCode: p.DesignCode, Colors: []string{"قرمز"}, Sizes: []string{"6", "9"}})
+	require.Nil(t, err)
+
+	carpets, err := pRepo.GetAllCarpetWithProductId(1, p.ID)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(carpets))
+	for _, c := range carpets {
+		require.Equal(t, "قرمز", c.Color)
+	}
+
+	require.Nil(t, pRepo.DeleteProduct(p.ID))
+	carpets, err = pRepo.GetAllCarpet(1)
+	require.Nil(t, err)
+	require.Equal(t, 0, len(carpets))
+}
+
+func TestProductRepo_SearchCarpets(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := CreateProduct1(pRepo, t)
+	_ = CreateProduct2(pRepo, t)
+
+	carpets, total, err := pRepo.SearchCarpets(model.ProductFilter{CompanyId: 1}, model.Page{Number: 2, Size: 3})
+	require.Nil(t, err)
+	require.Equal(t, int64(8), total)
+	require.Equal(t, 3, len(carpets))
+
+	carpets, total, err = pRepo.SearchCarpets(model.ProductFilter{CompanyId: 1, ProductIds: []uint{p1.ID}}, model.Page{})
+	require.Nil(t, err)
+	require.Equal(t, int64(4), total)
+
+	carpetSku, err := sku.Decode(carpets[0].Id)
+	require.Nil(t, err)
+	require.Equal(t, sku.SKU{ProductId: p1.ID, DimensionId: carpets[0].DimensionId, ThemeId: carpets[0].ThemeId}, carpetSku)
+
+	carpet, err := pRepo.GetCarpet(model.ProductFilter{CompanyId: 1}, carpetSku)
+	require.Nil(t, err)
+	require.Equal(t, carpets[0], *carpet)
+}
+
+func TestProductRepo_CarpetView_Dropped(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+
+	// View is dropped after repo created it
+	require.Nil(t, pRepo.db.Exec("DROP VIEW "+carpetViewName(1)).Error)
+
+	carpets, err := pRepo.GetAllCarpetWithProductId(1, p.ID)
+	require.Nil(t, err)
+	require.Equal(t, 4, len(carpets))
+}
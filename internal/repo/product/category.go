@@ -0,0 +1,233 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+)
+
+// categoryTreeQuery select id of a category (first arg) of a company (second arg) and ids of all its descendants
+const categoryTreeQuery = "WITH RECURSIVE tree AS (" +
+	"SELECT id FROM tbl_category WHERE id = ? AND company_id = ? AND deleted_at IS NULL " +
+	"UNION ALL " +
+	"SELECT c.id FROM tbl_category c JOIN tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL" +
+	") SELECT id FROM tree"
+
+// CreateCategory add a node to category tree of `category.CompanyId`, parent should be in same company
+func (r *productRepo) CreateCategory(category model.Category) (schemaCategory *schema.Category, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("model_category", fmt.Sprintf("%+v", category)),
+			keyval.String("schema_category", fmt.Sprintf("%+v", schemaCategory)),
+		}
+		logger.LogReqRes(r.logger, "category.CreateCategory", err, commonKeyVal...)
+	}()
+
+	schemaCategory = schema.CategoryModelToSchema(category)
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if schemaCategory.ParentId != nil {
+			if _, err := getCategory(tx, schemaCategory.CompanyId, *schemaCategory.ParentId); err != nil {
+				return err
+			}
+		}
+
+		if err := checkSiblingName(tx, schemaCategory.CompanyId, schemaCategory.ParentId, schemaCategory.Name, 0); err != nil {
+			return err
+		}
+
+		return tx.Create(schemaCategory).Error
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return schemaCategory, nil
+}
+
+// GetCategories return all nodes of category tree of `companyId`
+func (r *productRepo) GetCategories(companyId uint) (categories []schema.Category, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("categories", fmt.Sprintf("%+v", categories)),
+		}
+		logger.LogReqRes(r.logger, "category.GetCategories", err, commonKeyVal...)
+	}()
+
+	tx := r.db.Order("id ASC").Where("company_id = ?", companyId).Find(&categories)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	return categories, nil
+}
+
+// MoveCategory change parent of a category with its subtree, nil `parentId` move category to root.
+// a category can't move under itself or its descendants
+func (r *productRepo) MoveCategory(companyId, categoryId uint, parentId *uint) (category *schema.Category, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("category_id", fmt.Sprintf("%v", categoryId)),
+			keyval.String("parent_id", fmt.Sprintf("%v", parentId)),
+			keyval.String("category", fmt.Sprintf("%+v", category)),
+		}
+		logger.LogReqRes(r.logger, "category.MoveCategory", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		category, err = getCategory(tx, companyId, categoryId)
+		if err != nil {
+			return err
+		}
+
+		if parentId != nil {
+			if _, err := getCategory(tx, companyId, *parentId); err != nil {
+				return err
+			}
+
+			var subtree []uint
+			if err := tx.Raw(categoryTreeQuery, categoryId, companyId).Scan(&subtree).Error; err != nil {
+				return err
+			}
+			for _, id := range subtree {
+				if id == *parentId {
+					return derror.New(derror.InvalidCategory, "move under own subtree")
+				}
+			}
+		}
+
+		if err := checkSiblingName(tx, companyId, parentId, category.Name, categoryId); err != nil {
+			return err
+		}
+
+		if err := tx.Model(category).Update("parent_id", parentId).Error; err != nil {
+			return err
+		}
+		category.ParentId = parentId
+		return nil
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return category, nil
+}
+
+// DeleteCategory soft delete a category and remove its product memberships,
+// a category with children can't delete
+func (r *productRepo) DeleteCategory(companyId, categoryId uint) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("category_id", fmt.Sprintf("%v", categoryId)),
+		}
+		logger.LogReqRes(r.logger, "category.DeleteCategory", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := getCategory(tx, companyId, categoryId); err != nil {
+			return err
+		}
+
+		var children int64
+		if err := tx.Model(&schema.Category{}).Where("parent_id = ?", categoryId).Count(&children).Error; err != nil {
+			return err
+		}
+		if children != 0 {
+			return derror.CategoryNotEmpty
+		}
+
+		if err := tx.Exec("DELETE FROM tbl_product_category WHERE category_id = ?", categoryId).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&schema.Category{Model: gorm.Model{ID: categoryId}}).Error
+	})
+
+	return derror.Wrap(err)
+}
+
+// GetCategoryProducts return products that are members of a category or its descendants
+func (r *productRepo) GetCategoryProducts(companyId, categoryId uint) (products []schema.Product, err error) {
+	if _, err := getCategory(r.db, companyId, categoryId); err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return r.SearchProducts(model.ProductFilter{CompanyId: companyId, CategoryId: categoryId})
+}
+
+func getCategory(db *gorm.DB, companyId, categoryId uint) (*schema.Category, error) {
+	category := &schema.Category{}
+	if err := db.Where("company_id = ?", companyId).First(category, categoryId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, derror.New(derror.CategoryNotFound, fmt.Sprintf("category id %v", categoryId))
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+// checkSiblingName return derror.InvalidCategory if a category other than `categoryId` under `parentId` has `name`
+func checkSiblingName(db *gorm.DB, companyId uint, parentId *uint, name string, categoryId uint) error {
+	query := db.Model(&schema.Category{}).Where("company_id = ? AND name = ? AND id <> ?", companyId, name, categoryId)
+	if parentId == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentId)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count != 0 {
+		return derror.New(derror.InvalidCategory, "duplicate name "+name)
+	}
+	return nil
+}
+
+// withCategories return categories of `companyId` with `categoryIds`
+// if a `categoryId` not found for `companyId` return derror.CategoryNotFound
+func withCategories(db *gorm.DB, companyId uint, categoryIds []uint) ([]schema.Category, error) {
+	if len(categoryIds) == 0 {
+		return nil, nil
+	}
+
+	var categories []schema.Category
+	if err := db.Where("company_id = ? AND id IN ?", companyId, categoryIds).Find(&categories).Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	found := make(map[uint]bool, len(categories))
+	for _, c := range categories {
+		found[c.ID] = true
+	}
+	for _, id := range categoryIds {
+		if !found[id] {
+			return nil, derror.New(derror.CategoryNotFound, fmt.Sprintf("category id %v", id))
+		}
+	}
+
+	return categories, nil
+}
+
+// replaceCategories replace memberships of `productId` with `categories`
+func replaceCategories(tx *gorm.DB, productId uint, categories []schema.Category) error {
+	association := tx.Model(&schema.Product{Model: gorm.Model{ID: productId}}).Association("Categories")
+	if len(categories) == 0 {
+		return association.Clear()
+	}
+	return association.Replace(categories)
+}
@@ -0,0 +1,132 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func CreateCategory(repo *productRepo, t *testing.T, name string, parent *schema.Category) *schema.Category {
+	c := model.Category{CompanyId: 1, Name: name, Kind: model.CategoryKindCategory}
+	if parent != nil {
+		c.ParentId = &parent.ID
+	}
+	category, err := repo.CreateCategory(c)
+	require.Nil(t, err)
+	require.NotNil(t, category)
+	return category
+}
+
+func TestProductRepo_CreateCategory(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	silk := CreateCategory(pRepo, t, "ابریشم", nil)
+	_ = CreateCategory(pRepo, t, "کاشان", silk)
+
+	t.Run("duplicate sibling", func(t *testing.T) {
+		c, err := pRepo.CreateCategory(model.Category{CompanyId: 1, ParentId: &silk.ID, Name: "کاشان", Kind: model.CategoryKindCategory})
+		require.Equal(t, derror.StatusText(derror.InvalidCategory), derror.StatusText(err))
+		require.Nil(t, c)
+	})
+
+	t.Run("parent of another company", func(t *testing.T) {
+		c, err := pRepo.CreateCategory(model.Category{CompanyId: 2, ParentId: &silk.ID, Name: "قم", Kind: model.CategoryKindCategory})
+		require.Equal(t, derror.StatusText(derror.CategoryNotFound), derror.StatusText(err))
+		require.Nil(t, c)
+	})
+
+	categories, err := pRepo.GetCategories(1)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(categories))
+}
+
+func TestProductRepo_MoveCategory(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	silk := CreateCategory(pRepo, t, "ابریشم", nil)
+	kashan := CreateCategory(pRepo, t, "کاشان", silk)
+	modern := CreateCategory(pRepo, t, "مدرن", nil)
+
+	t.Run("under descendant", func(t *testing.T) {
+		c, err := pRepo.MoveCategory(1, silk.ID, &kashan.ID)
+		require.Equal(t, derror.StatusText(derror.InvalidCategory), derror.StatusText(err))
+		require.Nil(t, c)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		c, err := pRepo.MoveCategory(1, kashan.ID, &modern.ID)
+		require.Nil(t, err)
+		require.Equal(t, modern.ID, *c.ParentId)
+	})
+
+	t.Run("root", func(t *testing.T) {
+		c, err := pRepo.MoveCategory(1, kashan.ID, nil)
+		require.Nil(t, err)
+		require.Nil(t, c.ParentId)
+	})
+}
+
+func TestProductRepo_DeleteCategory(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	silk := CreateCategory(pRepo, t, "ابریشم", nil)
+	kashan := CreateCategory(pRepo, t, "کاشان", silk)
+	p, err := pRepo.CreateProduct(model.Product{CompanyId: 1, DesignCode: "105", Colors: []string{"قرمز"}, Sizes: []string{"6"}, Categories: []uint{kashan.ID}})
+	require.Nil(t, err)
+
+	require.Equal(t, derror.CategoryNotEmpty, pRepo.DeleteCategory(1, silk.ID))
+	require.Nil(t, pRepo.DeleteCategory(1, kashan.ID))
+	require.Nil(t, pRepo.DeleteCategory(1, silk.ID))
+	require.Equal(t, derror.StatusText(derror.CategoryNotFound), derror.StatusText(pRepo.DeleteCategory(1, silk.ID)))
+
+	gotP, err := pRepo.GetProductWithId(p.ID)
+	require.Nil(t, err)
+	require.Equal(t, 0, len(gotP.Categories))
+}
+
+func TestProductRepo_GetCategoryProducts(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	silk := CreateCategory(pRepo, t, "ابریشم", nil)
+	kashan := CreateCategory(pRepo, t, "کاشان", silk)
+	modern := CreateCategory(pRepo, t, "مدرن", nil)
+
+	p1, err := pRepo.CreateProduct(model.Product{CompanyId: 1, DesignCode: "105", Colors: []string{"قرمز"}, Sizes: []string{"6"}, Categories: []uint{silk.ID}})
+	require.Nil(t, err)
+	p2, err := pRepo.CreateProduct(model.Product{CompanyId: 1, DesignCode: "106", Colors: []string{"قرمز"}, Sizes: []string{"6"}, Categories: []uint{kashan.ID, modern.ID}})
+	require.Nil(t, err)
+
+	products, err := pRepo.GetCategoryProducts(1, silk.ID)
+	require.Nil(t, err)
+	require.Equal(t, []uint{p1.ID, p2.ID}, []uint{products[0].ID, products[1].ID})
+
+	products, err = pRepo.GetCategoryProducts(1, modern.ID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(products))
+	require.ElementsMatch(t, []uint{kashan.ID, modern.ID}, schema.GetCategoryIds(products[0].Categories))
+
+	// Edit replace memberships
+	_, err = pRepo.EditProduct(model.Product{Id: p2.ID, DesignCode: "106", Colors: []string{"قرمز"}, Sizes: []string{"6"}, Categories: []uint{modern.ID}})
+	require.Nil(t, err)
+	products, err = pRepo.GetCategoryProducts(1, silk.ID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(products))
+}
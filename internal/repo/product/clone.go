@@ -0,0 +1,292 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+)
+
+// CloneProduct create a draft copy of a product with design code `clone.DesignCode`, dimensions, themes,
+// attributes and categories are copied and prices too if `clone.WithPrices`. in another company
+// catalog sizes, attributes, categories and price lists of product are matched by their names
+func (r *productRepo) CloneProduct(clone model.ProductClone) (product *schema.Product, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("clone", fmt.Sprintf("%+v", clone)),
+			keyval.String("product", fmt.Sprintf("%+v", product)),
+		}
+		logger.LogReqRes(r.logger, "clone.CloneProduct", err, commonKeyVal...)
+	}()
+
+	source, err := r.GetProductWithId(clone.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	if source.CompanyId != clone.CompanyId {
+		return nil, derror.New(derror.ProductNotFound, fmt.Sprintf("product id %v", clone.ProductId))
+	}
+
+	companyId := clone.TargetCompanyId
+	if companyId == 0 {
+		companyId = source.CompanyId
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		newProduct, err := cloneModel(tx, source, companyId, clone.DesignCode)
+		if err != nil {
+			return err
+		}
+
+		product, err = createProduct(tx, newProduct)
+		if err != nil {
+			return err
+		}
+
+		if clone.WithPrices {
+			return clonePrices(tx, source, product)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	// First product of a company creates its carpet view
+	if _, err := r.carpetView(companyId); err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	return product, nil
+}
+
+// checkDesignCodeFree return derror.ProductExists if `designCode` is used in `companyId` by a product or an alias,
+// a deleted product keeps its design code in product_unique_id
+func checkDesignCodeFree(db *gorm.DB, companyId uint, designCode string) error {
+	var count int64
+	tx := db.Unscoped().Model(&schema.Product{}).Where("company_id = ? AND design_code = ?", companyId, designCode).Count(&count)
+	if err := tx.Error; err != nil {
+		return err
+	}
+	if count != 0 {
+		return derror.New(derror.ProductExists, fmt.Sprintf("design code %v of company id %v", designCode, companyId))
+	}
+
+	var productIds []uint
+	tx = db.Model(&schema.ProductAlias{}).Where("company_id = ? AND design_code = ?", companyId, designCode).Limit(1).Pluck("product_id", &productIds)
+	if err := tx.Error; err != nil {
+		return err
+	}
+	if len(productIds) != 0 {
+		return derror.New(derror.ProductExists, fmt.Sprintf("design code %v is an alias of product id %v", designCode, productIds[0]))
+	}
+	return nil
+}
+
+// cloneModel return a draft product of `companyId` with relations of `source`
+func cloneModel(db *gorm.DB, source *schema.Product, companyId uint, designCode string) (model.Product, error) {
+	product := model.Product{
+		CompanyId:   companyId,
+		DesignCode:  designCode,
+		Description: source.Description,
+		Status:      model.StatusDraft,
+	}
+
+	var standardSizes []uint
+	for _, d := range source.Dimensions {
+		product.Sizes = append(product.Sizes, d.Size)
+		if d.StandardSizeId != nil {
+			standardSizes = append(standardSizes, *d.StandardSizeId)
+		}
+	}
+	for _, t := range source.Themes {
+		product.Colors = append(product.Colors, t.Color)
+	}
+
+	if len(source.Attributes) != 0 {
+		product.Attributes = make(map[string]string, len(source.Attributes))
+		for _, a := range source.Attributes {
+			product.Attributes[a.Attribute.Name] = a.Value
+		}
+	}
+
+	var categories []uint
+	for _, c := range source.Categories {
+		categories = append(categories, c.ID)
+	}
+
+	if companyId == source.CompanyId {
+		product.StandardSizes, product.Categories = standardSizes, categories
+		return product, nil
+	}
+
+	// Attributes are already keyed by name
+	var err error
+	if product.StandardSizes, err = sameStandardSizes(db, source, companyId); err != nil {
+		return model.Product{}, err
+	}
+	if product.Categories, err = sameCategories(db, source.Categories, companyId); err != nil {
+		return model.Product{}, err
+	}
+	return product, nil
+}
+
+// sameStandardSizes return ids of catalog sizes of `companyId` with labels of catalog sizes of `source`
+func sameStandardSizes(db *gorm.DB, source *schema.Product, companyId uint) ([]uint, error) {
+	var labels []string
+	for _, d := range source.Dimensions {
+		if d.StandardSizeId != nil {
+			labels = append(labels, d.Size)
+		}
+	}
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	var standardSizes []schema.StandardSize
+	if err := db.Where("company_id = ? AND label IN ?", companyId, labels).Find(&standardSizes).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]uint, len(standardSizes))
+	for _, s := range standardSizes {
+		ids[s.Label] = s.ID
+	}
+
+	result := make([]uint, len(labels))
+	for i, label := range labels {
+		id, ok := ids[label]
+		if !ok {
+			return nil, derror.New(derror.StandardSizeNotFound, fmt.Sprintf("standard size %v of company id %v", label, companyId))
+		}
+		result[i] = id
+	}
+	return result, nil
+}
+
+// sameCategories return ids of categories of `companyId` with names and kinds of `categories`,
+// a name should be on one category of the kind
+func sameCategories(db *gorm.DB, categories []schema.Category, companyId uint) ([]uint, error) {
+	result := make([]uint, 0, len(categories))
+	for _, c := range categories {
+		var ids []uint
+		tx := db.Model(&schema.Category{}).Where("company_id = ? AND name = ? AND kind = ?", companyId, c.Name, c.Kind).Pluck("id", &ids)
+		if err := tx.Error; err != nil {
+			return nil, err
+		}
+
+		switch len(ids) {
+		case 0:
+			return nil, derror.New(derror.CategoryNotFound, fmt.Sprintf("category %v of company id %v", c.Name, companyId))
+		case 1:
+			result = append(result, ids[0])
+		default:
+			return nil, derror.New(derror.InvalidCategory, fmt.Sprintf("%v categories named %v in company id %v", len(ids), c.Name, companyId))
+		}
+	}
+	return result, nil
+}
+
+// clonePrices copy prices and adjustments of `source` to carpets of `product` with the same size and color,
+// in another company a price list is matched by its name
+func clonePrices(db *gorm.DB, source, product *schema.Product) error {
+	var prices []schema.Price
+	if err := db.Where("product_id = ?", source.ID).Order("id").Find(&prices).Error; err != nil {
+		return err
+	}
+	var adjustments []schema.PriceAdjustment
+	if err := db.Where("product_id = ?", source.ID).Order("id").Find(&adjustments).Error; err != nil {
+		return err
+	}
+	if len(prices) == 0 && len(adjustments) == 0 {
+		return nil
+	}
+
+	dimensions := make(map[uint]uint, len(source.Dimensions))
+	for _, d := range source.Dimensions {
+		for _, newDimension := range product.Dimensions {
+			if newDimension.Size == d.Size {
+				dimensions[d.ID] = newDimension.ID
+			}
+		}
+	}
+	themes := make(map[uint]uint, len(source.Themes))
+	for _, t := range source.Themes {
+		for _, newTheme := range product.Themes {
+			if newTheme.Color == t.Color {
+				themes[t.ID] = newTheme.ID
+			}
+		}
+	}
+
+	priceLists := make(map[uint]uint)
+	priceList := func(id uint) (uint, error) {
+		if product.CompanyId == source.CompanyId {
+			return id, nil
+		}
+		if newId, ok := priceLists[id]; ok {
+			return newId, nil
+		}
+		newId, err := samePriceList(db, source.CompanyId, id, product.CompanyId)
+		priceLists[id] = newId
+		return newId, err
+	}
+
+	for i := range prices {
+		var err error
+		prices[i].Model = gorm.Model{}
+		prices[i].ProductId = product.ID
+		if prices[i].PriceListId, err = priceList(prices[i].PriceListId); err != nil {
+			return err
+		}
+		// Price per square meter has no dimension
+		if prices[i].DimensionId != 0 {
+			prices[i].DimensionId = dimensions[prices[i].DimensionId]
+		}
+		if prices[i].ThemeId != nil {
+			themeId := themes[*prices[i].ThemeId]
+			prices[i].ThemeId = &themeId
+		}
+	}
+	if len(prices) != 0 {
+		if err := db.Create(&prices).Error; err != nil {
+			return err
+		}
+	}
+
+	for i := range adjustments {
+		var err error
+		adjustments[i].Model = gorm.Model{}
+		adjustments[i].ProductId = product.ID
+		if adjustments[i].PriceListId, err = priceList(adjustments[i].PriceListId); err != nil {
+			return err
+		}
+	}
+	if len(adjustments) != 0 {
+		return db.Create(&adjustments).Error
+	}
+	return nil
+}
+
+// samePriceList return id of price list of `companyId` with name of price list `priceListId` of `sourceCompanyId`
+func samePriceList(db *gorm.DB, sourceCompanyId, priceListId, companyId uint) (uint, error) {
+	source, err := getPriceList(db, sourceCompanyId, priceListId)
+	if err != nil {
+		return 0, err
+	}
+
+	list := &schema.PriceList{}
+	if err := db.Where("company_id = ? AND name = ?", companyId, source.Name).First(list).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, derror.New(derror.PriceListNotFound, fmt.Sprintf("price list %v of company id %v", source.Name, companyId))
+		}
+		return 0, err
+	}
+	return list.ID, nil
+}
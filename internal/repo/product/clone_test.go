@@ -0,0 +1,57 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestProductRepo_CloneProduct(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := CreateProduct1(pRepo, t)
+	p2 := CreateProduct2(pRepo, t)
+
+	list, err := pRepo.CreatePriceList(model.PriceList{CompanyId: 1, Name: "عمده", Kind: model.PriceListWholesale, Currency: "IRR"})
+	require.Nil(t, err)
+	_, err = pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p1.ID, DimensionId: p1.Dimensions[0].ID,
+		ThemeId: p1.Themes[0].ID, Amount: 1500, ValidFrom: time.Now().Add(-time.Hour)})
+	require.Nil(t, err)
+
+	clone, err := pRepo.CloneProduct(model.ProductClone{CompanyId: 1, ProductId: p1.ID, DesignCode: "107", WithPrices: true})
+	require.Nil(t, err)
+	require.NotEqual(t, p1.ID, clone.ID)
+	require.Equal(t, "107", clone.DesignCode)
+	require.Equal(t, model.StatusDraft, clone.Status)
+	require.Equal(t, len(p1.Dimensions), len(clone.Dimensions))
+	require.Equal(t, len(p1.Themes), len(clone.Themes))
+
+	prices, err := pRepo.GetPrices(1, list.ID, clone.ID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(prices))
+	require.Equal(t, int64(1500), prices[0].Amount)
+	require.Equal(t, clone.Dimensions[0].ID, prices[0].DimensionId)
+
+	// Design code of a product of company
+	_, err = pRepo.CloneProduct(model.ProductClone{CompanyId: 1, ProductId: p1.ID, DesignCode: p2.DesignCode})
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.ProductExists), derror.StatusText(err))
+
+	// Same design code in another company, price list of company 2 is matched by name
+	_, err = pRepo.CloneProduct(model.ProductClone{CompanyId: 1, ProductId: p1.ID, DesignCode: p1.DesignCode, TargetCompanyId: 2, WithPrices: true})
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.PriceListNotFound), derror.StatusText(err))
+
+	_, err = pRepo.CreatePriceList(model.PriceList{CompanyId: 2, Name: "عمده", Kind: model.PriceListWholesale, Currency: "IRR"})
+	require.Nil(t, err)
+	other, err := pRepo.CloneProduct(model.ProductClone{CompanyId: 1, ProductId: p1.ID, DesignCode: p1.DesignCode, TargetCompanyId: 2, WithPrices: true})
+	require.Nil(t, err)
+	require.Equal(t, uint(2), other.CompanyId)
+	require.Equal(t, p1.DesignCode, other.DesignCode)
+}
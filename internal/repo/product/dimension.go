@@ -1,11 +1,13 @@
 package product
 
 import (
+	"errors"
 	"fmt"
 	"github.com/seed95/product-service/internal/derror"
 	"github.com/seed95/product-service/internal/repo/product/schema"
 	"github.com/seed95/product-service/pkg/logger"
 	"github.com/seed95/product-service/pkg/logger/keyval"
+	"github.com/seed95/product-service/pkg/normalize"
 	"gorm.io/gorm"
 )
 
@@ -19,6 +21,7 @@ type (
 		InsertDimensions(tx *gorm.DB, productId uint, sizes []string) ([]schema.Dimension, error)
 		DeleteDimensionsWithId(tx *gorm.DB, productId uint, dimensions []schema.Dimension) error
 		EditDimensions(tx *gorm.DB, productId uint, editedDimensions []schema.Dimension) ([]schema.Dimension, error)
+		RenameDimension(tx *gorm.DB, productId, dimensionId uint, size string) (*schema.Dimension, error)
 	}
 )
 
@@ -46,7 +49,9 @@ func (r *dimensionRepo) GetDimensionsWithProductId(db *gorm.DB, productId uint)
 	return dimensions, nil
 }
 
-// InsertDimensions if a size for `productId` is duplicate, no add any dimensions
+// InsertDimensions if a size for `productId` is duplicate, no add any dimensions.
+// a removed size of product is restored with its id instead of a new dimension, so its carpets keep their SKU.
+// a restored dimension isn't a catalog size until it is linked again
 // support roll back
 func (r *dimensionRepo) InsertDimensions(tx *gorm.DB, productId uint, sizes []string) (dimensions []schema.Dimension, err error) {
 	// Log request response
@@ -63,17 +68,60 @@ func (r *dimensionRepo) InsertDimensions(tx *gorm.DB, productId uint, sizes []st
 		return nil, derror.InvalidDimension
 	}
 
+	seen := make(map[string]bool, len(sizes))
+	for _, v := range sizes {
+		if seen[v] {
+			return nil, derror.New(derror.InvalidDimension, fmt.Sprintf("size %v is repeated", v))
+		}
+		seen[v] = true
+	}
+
+	// A removed size of product is restored with its id
+	var removed []schema.Dimension
+	result := tx.Unscoped().Where("product_id = ? AND size IN ? AND deleted_at IS NOT NULL", productId, sizes).Find(&removed)
+	if err := result.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	restored := make(map[string]schema.Dimension, len(removed))
+	for _, d := range removed {
+		restored[d.Size] = d
+	}
+
 	dimensions = make([]schema.Dimension, len(sizes))
-	for i, s := range sizes {
-		dimensions[i] = schema.Dimension{
+	var newIndexes []int
+	var newDimensions []schema.Dimension
+	for i, v := range sizes {
+		if d, ok := restored[v]; ok {
+			dimensions[i] = d
+			continue
+		}
+		newIndexes = append(newIndexes, i)
+		newDimensions = append(newDimensions, schema.Dimension{
 			ProductId: productId,
-			Size:      s,
+			Size:      v,
+		})
+	}
+
+	for i := range dimensions {
+		if dimensions[i].ID == 0 {
+			continue
 		}
+		result = tx.Unscoped().Model(&dimensions[i]).Updates(map[string]interface{}{"deleted_at": nil, "standard_size_id": nil})
+		if err := result.Error; err != nil {
+			return nil, derror.New(derror.InternalServer, err.Error())
+		}
+		dimensions[i].DeletedAt, dimensions[i].StandardSizeId = gorm.DeletedAt{}, nil
 	}
 
-	if err := tx.Create(&dimensions).Error; err != nil {
-		return nil, derror.New(derror.InternalServer, err.Error())
+	if len(newDimensions) != 0 {
+		if err := tx.Create(&newDimensions).Error; err != nil {
+			return nil, derror.New(derror.InternalServer, err.Error())
+		}
+		for j, i := range newIndexes {
+			dimensions[i] = newDimensions[j]
+		}
 	}
+
 	return dimensions, nil
 }
 
@@ -131,7 +179,7 @@ func (r *dimensionRepo) EditDimensions(tx *gorm.DB, productId uint, editedDimens
 OriginalLoop:
 	for _, od := range originalDimensions {
 		for _, ed := range editedDimensions {
-			if od.Size == ed.Size {
+			if normalize.Key(od.Size) == normalize.Key(ed.Size) {
 				dimensions = append(dimensions, od)
 				continue OriginalLoop
 			}
@@ -144,7 +192,7 @@ OriginalLoop:
 EditedLoop:
 	for _, ed := range editedDimensions {
 		for _, od := range originalDimensions {
-			if od.Size == ed.Size {
+			if normalize.Key(od.Size) == normalize.Key(ed.Size) {
 				continue EditedLoop
 			}
 		}
@@ -166,6 +214,213 @@ EditedLoop:
 		dimensions = append(dimensions, newDimensions...)
 	}
 
+	if err = r.linkStandardSizes(tx, dimensions, editedDimensions); err != nil {
+		return nil, err
+	}
+
 	return dimensions, nil
 
 }
+
+// linkStandardSizes set catalog size of each dimension in `dimensions` same as edited dimension with equal size
+func (r *dimensionRepo) linkStandardSizes(tx *gorm.DB, dimensions, editedDimensions []schema.Dimension) error {
+	for i := range dimensions {
+		for _, ed := range editedDimensions {
+			if dimensions[i].Size != ed.Size || sameStandardSize(dimensions[i].StandardSizeId, ed.StandardSizeId) {
+				continue
+			}
+			if err := tx.Model(&dimensions[i]).Update("standard_size_id", ed.StandardSizeId).Error; err != nil {
+				return derror.New(derror.InternalServer, err.Error())
+			}
+			dimensions[i].StandardSizeId = ed.StandardSizeId
+		}
+	}
+	return nil
+}
+
+func sameStandardSize(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// RenameDimension change size of `dimensionId` of `productId` to `size` and keep its id, so carpets of dimension keep
+// their SKU, stock and prices. a renamed dimension is no longer a catalog size.
+// if `size` is another dimension of product, even a removed one, return derror.InvalidDimension
+func (r *dimensionRepo) RenameDimension(tx *gorm.DB, productId, dimensionId uint, size string) (dimension *schema.Dimension, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("dimension_id", fmt.Sprintf("%v", dimensionId)),
+			keyval.String("size", size),
+			keyval.String("dimension", fmt.Sprintf("%+v", dimension)),
+		}
+		logger.LogReqRes(r.logger, "dimension.RenameDimension", err, commonKeyVal...)
+	}()
+
+	if size == "" {
+		return nil, derror.InvalidDimension
+	}
+
+	dimension = &schema.Dimension{}
+	if err := tx.Where("product_id = ?", productId).First(dimension, dimensionId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, derror.DimensionNotFound
+		}
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	if dimension.Size == size {
+		return dimension, nil
+	}
+
+	// A removed dimension keeps its size in dimension_unique_id
+	other := &schema.Dimension{}
+	result := tx.Unscoped().Where("product_id = ? AND size = ?", productId, size).Limit(1).Find(other)
+	if err := result.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	if result.RowsAffected != 0 {
+		if other.DeletedAt.Valid {
+			return nil, derror.New(derror.InvalidDimension, fmt.Sprintf("size %v is a removed size of product, add it to restore it", size))
+		}
+		return nil, derror.New(derror.InvalidDimension, fmt.Sprintf("size %v exists", size))
+	}
+
+	dimension.Size, dimension.StandardSizeId = size, nil
+	if err := tx.Model(dimension).Select("size", "standard_size_id").Updates(dimension).Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	return dimension, nil
+}
+
+// AddDimensions add `sizes` to product `productId` of `companyId` and return updated product,
+// if a size already exists for product return derror.InvalidDimension
+func (r *productRepo) AddDimensions(companyId, productId uint, sizes []string) (product *schema.Product, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("sizes", fmt.Sprintf("%v", sizes)),
+			keyval.String("product", fmt.Sprintf("%+v", product)),
+		}
+		logger.LogReqRes(r.logger, "dimension.AddDimensions", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, companyId, productId); err != nil {
+			return err
+		}
+
+		dimensions, err := r.dimension.GetDimensionsWithProductId(tx, productId)
+		if err != nil {
+			return err
+		}
+		for _, s := range sizes {
+			if dimensionsHaveSize(dimensions, s) {
+				return derror.New(derror.InvalidDimension, fmt.Sprintf("size %v exists", s))
+			}
+		}
+
+		_, err = r.dimension.InsertDimensions(tx, productId, sizes)
+		return err
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return r.GetProductWithId(productId)
+}
+
+// RemoveDimensions delete `dimensionIds` of product `productId` of `companyId` and return updated product.
+// a product keeps at least one size and a size with pieces on hand can't be removed,
+// prices and exclusions of carpets of removed sizes are deleted
+func (r *productRepo) RemoveDimensions(companyId, productId uint, dimensionIds []uint) (product *schema.Product, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("dimension_ids", fmt.Sprintf("%v", dimensionIds)),
+			keyval.String("product", fmt.Sprintf("%+v", product)),
+		}
+		logger.LogReqRes(r.logger, "dimension.RemoveDimensions", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, companyId, productId); err != nil {
+			return err
+		}
+
+		dimensions, err := r.dimension.GetDimensionsWithProductId(tx, productId)
+		if err != nil {
+			return err
+		}
+
+		removed := make([]schema.Dimension, 0, len(dimensionIds))
+	IdLoop:
+		for _, id := range dimensionIds {
+			for _, d := range dimensions {
+				if d.ID == id {
+					removed = append(removed, d)
+					continue IdLoop
+				}
+			}
+			return derror.New(derror.DimensionNotFound, fmt.Sprintf("dimension id %v", id))
+		}
+		if len(removed) == len(dimensions) {
+			return derror.New(derror.InvalidDimension, "no size is left")
+		}
+
+		if err := checkRemovedCarpetsNotInStock(tx, productId, removed, nil); err != nil {
+			return err
+		}
+		if err := r.dimension.DeleteDimensionsWithId(tx, productId, removed); err != nil {
+			return err
+		}
+
+		// Carpets of deleted sizes lose their prices and exclusions
+		if err := deleteDetachedPrices(tx, productId); err != nil {
+			return err
+		}
+		return deleteDetachedExclusions(tx, productId)
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return r.GetProductWithId(productId)
+}
+
+// RenameDimension change size of dimension `dimensionId` of product `productId` of `companyId` in place and return updated product
+func (r *productRepo) RenameDimension(companyId, productId, dimensionId uint, size string) (product *schema.Product, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("dimension_id", fmt.Sprintf("%v", dimensionId)),
+			keyval.String("size", size),
+			keyval.String("product", fmt.Sprintf("%+v", product)),
+		}
+		logger.LogReqRes(r.logger, "dimension.RenameDimension", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, companyId, productId); err != nil {
+			return err
+		}
+		_, err := r.dimension.RenameDimension(tx, productId, dimensionId, size)
+		return err
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return r.GetProductWithId(productId)
+}
@@ -468,3 +468,113 @@ func TestDimensionRepo_EditDimensions_NotChange(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, len(dimensions), len(gotDimensions))
 }
+
+func TestProductRepo_AddRemoveDimensions(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := CreateProduct1(pRepo, t)
+
+	product, err := pRepo.AddDimensions(1, p1.ID, []string{"12"})
+	require.Nil(t, err)
+	require.Equal(t, 3, len(product.Dimensions))
+
+	// Existing size
+	_, err = pRepo.AddDimensions(1, p1.ID, []string{"6"})
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.InvalidDimension), derror.StatusText(err))
+
+	// Size with pieces on hand
+	warehouse, err := pRepo.CreateWarehouse(model.Warehouse{CompanyId: 1, Name: "مرکزی"})
+	require.Nil(t, err)
+	_, err = pRepo.RecordStockMovement(model.StockMovement{CompanyId: 1, WarehouseId: warehouse.ID, ProductId: p1.ID,
+		DimensionId: p1.Dimensions[0].ID, ThemeId: p1.Themes[0].ID, Kind: model.MovementReceipt, Quantity: 1})
+	require.Nil(t, err)
+	_, err = pRepo.RemoveDimensions(1, p1.ID, []uint{p1.Dimensions[0].ID})
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.CarpetInStock), derror.StatusText(err))
+
+	product, err = pRepo.RemoveDimensions(1, p1.ID, []uint{p1.Dimensions[1].ID})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(product.Dimensions))
+}
+
+func TestProductRepo_RenameDimension(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := CreateProduct1(pRepo, t)
+	six := p1.Dimensions[0]
+
+	product, err := pRepo.RenameDimension(1, p1.ID, six.ID, "6.5")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(product.Dimensions))
+	for _, dimension := range product.Dimensions {
+		if dimension.ID == six.ID {
+			require.Equal(t, "6.5", dimension.Size)
+		}
+	}
+
+	// Size of another dimension
+	_, err = pRepo.RenameDimension(1, p1.ID, six.ID, p1.Dimensions[1].Size)
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.InvalidDimension), derror.StatusText(err))
+
+	// Product of another company
+	_, err = pRepo.RenameDimension(2, p1.ID, six.ID, "12")
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.ProductNotFound), derror.StatusText(err))
+}
+
+func TestDimensionRepo_EditDimensions_RestoreRemovedSize(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create product
+	gotP1 := CreateProduct1(pRepo, t)
+	removed := gotP1.Dimensions[1]
+
+	// Dimension repo
+	dRepo := NewDimensionRepoMock()
+
+	// Remove second size
+	err = pRepo.db.Transaction(func(tx *gorm.DB) error {
+		_, err = dRepo.EditDimensions(tx, gotP1.ID, []schema.Dimension{{Size: gotP1.Dimensions[0].Size}})
+		return err
+	})
+	require.Nil(t, err)
+
+	// Add it back
+	var gotDimensions []schema.Dimension
+	err = pRepo.db.Transaction(func(tx *gorm.DB) error {
+		gotDimensions, err = dRepo.EditDimensions(tx, gotP1.ID, []schema.Dimension{{Size: gotP1.Dimensions[0].Size}, {Size: removed.Size}})
+		return err
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(gotDimensions))
+	require.Equal(t, removed.ID, gotDimensions[1].ID)
+	require.False(t, gotDimensions[1].DeletedAt.Valid)
+
+	gotDimensions, err = dRepo.GetDimensionsWithProductId(pRepo.db, gotP1.ID)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(gotDimensions))
+	require.Equal(t, removed.ID, gotDimensions[1].ID)
+
+	// Incremental add restores too
+	product, err := pRepo.RemoveDimensions(1, gotP1.ID, []uint{removed.ID})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(product.Dimensions))
+	product, err = pRepo.AddDimensions(1, gotP1.ID, []string{removed.Size})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(product.Dimensions))
+	require.Equal(t, removed.ID, product.Dimensions[1].ID)
+}
@@ -0,0 +1,509 @@
+package product
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// DumpCatalog return complete catalog of `companyId`, it is read in one snapshot of database
+func (r *productRepo) DumpCatalog(companyId uint) (dump *model.CatalogDump, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+		}
+		if dump != nil {
+			commonKeyVal = append(commonKeyVal, keyval.String("products", fmt.Sprintf("%v", len(dump.Products))))
+		}
+		logger.LogReqRes(r.logger, "dump.DumpCatalog", err, commonKeyVal...)
+	}()
+
+	dump = &model.CatalogDump{CompanyId: companyId, CreatedAt: time.Now()}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		products := tx.Model(&schema.Product{}).Select("id").Where("company_id = ?", companyId)
+		priceLists := tx.Model(&schema.PriceList{}).Select("id").Where("company_id = ?", companyId)
+
+		queries := []struct {
+			query *gorm.DB
+			rows  interface{}
+		}{
+			{tx.Model(&schema.StandardSize{}).Where("company_id = ?", companyId).Order("id ASC"), &dump.StandardSizes},
+			{tx.Model(&schema.Category{}).Where("company_id = ?", companyId).Order("id ASC"), &dump.Categories},
+			{tx.Model(&schema.Tag{}).Where("company_id = ?", companyId).Order("id ASC"), &dump.Tags},
+			{tx.Model(&schema.Product{}).Where("company_id = ?", companyId).Order("id ASC"), &dump.Products},
+			{tx.Model(&schema.Dimension{}).Where("product_id IN (?)", products).Order("id ASC"), &dump.Dimensions},
+			{tx.Model(&schema.Theme{}).Where("product_id IN (?)", products).Order("id ASC"), &dump.Themes},
+			{tx.Model(&schema.ProductAttribute{}).Where("product_id IN (?)", products).Order("id ASC"), &dump.ProductAttributes},
+			{tx.Table("tbl_product_category").Where("product_id IN (?)", products).Order("product_id ASC, category_id ASC"), &dump.ProductCategories},
+			{tx.Table("tbl_product_tag").Where("product_id IN (?)", products).Order("product_id ASC, tag_id ASC"), &dump.ProductTags},
+			{tx.Model(&schema.CarpetExclusion{}).Where("product_id IN (?)", products).Order("id ASC"), &dump.CarpetExclusions},
+			{tx.Model(&schema.PriceList{}).Where("company_id = ?", companyId).Order("id ASC"), &dump.PriceLists},
+			{tx.Model(&schema.Price{}).Where("price_list_id IN (?)", priceLists).Order("id ASC"), &dump.Prices},
+			{tx.Model(&schema.PriceAdjustment{}).Where("price_list_id IN (?)", priceLists).Order("id ASC"), &dump.PriceAdjustments},
+			{tx.Model(&schema.ProductAlias{}).Where("product_id IN (?)", products).Order("id ASC"), &dump.ProductAliases},
+		}
+
+		for _, q := range queries {
+			if err := q.query.Find(q.rows).Error; err != nil {
+				return err
+			}
+		}
+
+		// Options of a definition are a json text column
+		var definitions []schema.AttributeDefinition
+		if err := tx.Where("company_id = ?", companyId).Order("id ASC").Find(&definitions).Error; err != nil {
+			return err
+		}
+		for _, d := range definitions {
+			dump.AttributeDefinitions = append(dump.AttributeDefinitions, model.DumpAttributeDefinition{
+				Id:       d.ID,
+				Name:     d.Name,
+				Type:     d.Type,
+				Unit:     d.Unit,
+				Required: d.Required,
+				Options:  d.Options,
+			})
+		}
+
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+
+	if err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	return dump, nil
+}
+
+// restoreBatchSize is number of records of a section inserted by one statement
+const restoreBatchSize = 500
+
+// RestoreCatalog insert catalog of `dump` for `companyId` in one transaction. every record gets a new id and
+// references between records are remapped, company should have no catalog, even a deleted one
+func (r *productRepo) RestoreCatalog(companyId uint, dump model.CatalogDump) (result *model.RestoreResult, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("dump_company_id", fmt.Sprintf("%v", dump.CompanyId)),
+			keyval.String("result", fmt.Sprintf("%+v", result)),
+		}
+		logger.LogReqRes(r.logger, "dump.RestoreCatalog", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkCatalogEmpty(tx, companyId); err != nil {
+			return err
+		}
+
+		restore := &catalogRestore{tx: tx, companyId: companyId}
+		if err := restore.run(dump); err != nil {
+			return err
+		}
+		result = &model.RestoreResult{Counts: restore.counts, ProductIds: restore.products}
+		return nil
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	if len(dump.Products) != 0 {
+		if _, err := r.carpetView(companyId); err != nil {
+			return nil, derror.New(derror.InternalServer, err.Error())
+		}
+	}
+
+	return result, nil
+}
+
+// checkCatalogEmpty return derror.CatalogNotEmpty if `companyId` has a record of catalog,
+// deleted records are counted because they keep their unique names
+func checkCatalogEmpty(tx *gorm.DB, companyId uint) error {
+	tables := []struct {
+		name  string
+		model interface{}
+	}{
+		{model.DumpStandardSizes, &schema.StandardSize{}},
+		{model.DumpAttributeDefinitions, &schema.AttributeDefinition{}},
+		{model.DumpCategories, &schema.Category{}},
+		{model.DumpTags, &schema.Tag{}},
+		{model.DumpProducts, &schema.Product{}},
+		{model.DumpProductAliases, &schema.ProductAlias{}},
+		{model.DumpPriceLists, &schema.PriceList{}},
+	}
+
+	for _, t := range tables {
+		var count int64
+		result := tx.Unscoped().Model(t.model).Where("company_id = ?", companyId).Count(&count)
+		if err := result.Error; err != nil {
+			return err
+		}
+		if count != 0 {
+			return derror.New(derror.CatalogNotEmpty, fmt.Sprintf("company id %v has %v %v", companyId, count, t.name))
+		}
+	}
+	return nil
+}
+
+// catalogRestore insert sections of a dump in order and keep new id of every dumped id
+type catalogRestore struct {
+	tx        *gorm.DB
+	companyId uint
+	counts    map[string]int
+
+	standardSizes map[uint]uint
+	attributes    map[uint]uint
+	categories    map[uint]uint
+	tags          map[uint]uint
+	products      map[uint]uint
+	dimensions    map[uint]uint
+	themes        map[uint]uint
+	priceLists    map[uint]uint
+}
+
+func (c *catalogRestore) run(dump model.CatalogDump) error {
+	c.counts = make(map[string]int)
+	steps := []func(model.CatalogDump) error{
+		c.restoreStandardSizes,
+		c.restoreAttributeDefinitions,
+		c.restoreCategories,
+		c.restoreTags,
+		c.restoreProducts,
+		c.restoreCarpets,
+		c.restoreProductRelations,
+		c.restorePrices,
+		c.restoreAliases,
+	}
+	for _, step := range steps {
+		if err := step(dump); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remap return new id of dumped `id` of `section`
+func remap(ids map[uint]uint, section string, id uint) (uint, error) {
+	newId, ok := ids[id]
+	if !ok {
+		return 0, derror.New(derror.InvalidDump, fmt.Sprintf("%v id %v not found", section, id))
+	}
+	return newId, nil
+}
+
+// create insert `rows`, a slice of schema records, and count them for `section`
+func (c *catalogRestore) create(section string, rows interface{}, length int) error {
+	c.counts[section] = length
+	if length == 0 {
+		return nil
+	}
+	return c.tx.Omit(clause.Associations).CreateInBatches(rows, restoreBatchSize).Error
+}
+
+func (c *catalogRestore) restoreStandardSizes(dump model.CatalogDump) error {
+	rows := make([]schema.StandardSize, len(dump.StandardSizes))
+	for i, s := range dump.StandardSizes {
+		rows[i] = schema.StandardSize{CompanyId: c.companyId, Label: s.Label, Width: s.Width, Length: s.Length, SortOrder: s.SortOrder}
+	}
+	if err := c.create(model.DumpStandardSizes, &rows, len(rows)); err != nil {
+		return err
+	}
+
+	c.standardSizes = make(map[uint]uint, len(rows))
+	for i, s := range dump.StandardSizes {
+		c.standardSizes[s.Id] = rows[i].ID
+	}
+	return nil
+}
+
+func (c *catalogRestore) restoreAttributeDefinitions(dump model.CatalogDump) error {
+	rows := make([]schema.AttributeDefinition, len(dump.AttributeDefinitions))
+	for i, d := range dump.AttributeDefinitions {
+		rows[i] = schema.AttributeDefinition{CompanyId: c.companyId, Name: d.Name, Type: d.Type, Unit: d.Unit, Required: d.Required, Options: d.Options}
+	}
+	if err := c.create(model.DumpAttributeDefinitions, &rows, len(rows)); err != nil {
+		return err
+	}
+
+	c.attributes = make(map[uint]uint, len(rows))
+	for i, d := range dump.AttributeDefinitions {
+		c.attributes[d.Id] = rows[i].ID
+	}
+	return nil
+}
+
+// restoreCategories insert categories level by level so a parent has its new id before its children
+func (c *catalogRestore) restoreCategories(dump model.CatalogDump) error {
+	c.categories = make(map[uint]uint, len(dump.Categories))
+	c.counts[model.DumpCategories] = len(dump.Categories)
+
+	pending := dump.Categories
+	for len(pending) != 0 {
+		var rows []schema.Category
+		var restored, waiting []model.DumpCategory
+		for _, category := range pending {
+			row := schema.Category{CompanyId: c.companyId, Name: category.Name, Kind: category.Kind}
+			if category.ParentId != nil {
+				parentId, ok := c.categories[*category.ParentId]
+				if !ok {
+					waiting = append(waiting, category)
+					continue
+				}
+				row.ParentId = &parentId
+			}
+			rows = append(rows, row)
+			restored = append(restored, category)
+		}
+
+		if len(rows) == 0 {
+			return derror.New(derror.InvalidDump, fmt.Sprintf("parent of category id %v not found", waiting[0].Id))
+		}
+		if err := c.tx.CreateInBatches(&rows, restoreBatchSize).Error; err != nil {
+			return err
+		}
+		for i, category := range restored {
+			c.categories[category.Id] = rows[i].ID
+		}
+
+		pending = waiting
+	}
+	return nil
+}
+
+func (c *catalogRestore) restoreTags(dump model.CatalogDump) error {
+	rows := make([]schema.Tag, len(dump.Tags))
+	for i, t := range dump.Tags {
+		rows[i] = schema.Tag{CompanyId: c.companyId, Name: t.Name}
+	}
+	if err := c.create(model.DumpTags, &rows, len(rows)); err != nil {
+		return err
+	}
+
+	c.tags = make(map[uint]uint, len(rows))
+	for i, t := range dump.Tags {
+		c.tags[t.Id] = rows[i].ID
+	}
+	return nil
+}
+
+func (c *catalogRestore) restoreProducts(dump model.CatalogDump) error {
+	rows := make([]schema.Product, len(dump.Products))
+	for i, p := range dump.Products {
+		rows[i] = schema.Product{
+			CompanyId:      c.companyId,
+			DesignCode:     p.DesignCode,
+			Description:    p.Description,
+			Status:         p.Status,
+			PublishedAt:    p.PublishedAt,
+			ArchivedAt:     p.ArchivedAt,
+			DiscontinuedAt: p.DiscontinuedAt,
+		}
+	}
+	if err := c.create(model.DumpProducts, &rows, len(rows)); err != nil {
+		return err
+	}
+
+	c.products = make(map[uint]uint, len(rows))
+	for i, p := range dump.Products {
+		c.products[p.Id] = rows[i].ID
+	}
+	return nil
+}
+
+// restoreCarpets insert dimensions, themes and exclusions of their carpets
+func (c *catalogRestore) restoreCarpets(dump model.CatalogDump) error {
+	dimensions := make([]schema.Dimension, len(dump.Dimensions))
+	for i, d := range dump.Dimensions {
+		productId, err := remap(c.products, model.DumpProducts, d.ProductId)
+		if err != nil {
+			return err
+		}
+		dimensions[i] = schema.Dimension{ProductId: productId, Size: d.Size}
+		if d.StandardSizeId != nil {
+			standardSizeId, err := remap(c.standardSizes, model.DumpStandardSizes, *d.StandardSizeId)
+			if err != nil {
+				return err
+			}
+			dimensions[i].StandardSizeId = &standardSizeId
+		}
+	}
+	if err := c.create(model.DumpDimensions, &dimensions, len(dimensions)); err != nil {
+		return err
+	}
+	c.dimensions = make(map[uint]uint, len(dimensions))
+	for i, d := range dump.Dimensions {
+		c.dimensions[d.Id] = dimensions[i].ID
+	}
+
+	themes := make([]schema.Theme, len(dump.Themes))
+	for i, t := range dump.Themes {
+		productId, err := remap(c.products, model.DumpProducts, t.ProductId)
+		if err != nil {
+			return err
+		}
+		themes[i] = schema.Theme{ProductId: productId, Color: t.Color}
+	}
+	if err := c.create(model.DumpThemes, &themes, len(themes)); err != nil {
+		return err
+	}
+	c.themes = make(map[uint]uint, len(themes))
+	for i, t := range dump.Themes {
+		c.themes[t.Id] = themes[i].ID
+	}
+
+	exclusions := make([]schema.CarpetExclusion, len(dump.CarpetExclusions))
+	for i, e := range dump.CarpetExclusions {
+		var err error
+		exclusions[i].Reason = e.Reason
+		if exclusions[i].ProductId, err = remap(c.products, model.DumpProducts, e.ProductId); err != nil {
+			return err
+		}
+		if exclusions[i].DimensionId, err = remap(c.dimensions, model.DumpDimensions, e.DimensionId); err != nil {
+			return err
+		}
+		if exclusions[i].ThemeId, err = remap(c.themes, model.DumpThemes, e.ThemeId); err != nil {
+			return err
+		}
+	}
+	return c.create(model.DumpCarpetExclusions, &exclusions, len(exclusions))
+}
+
+// restoreProductRelations insert attribute values, category memberships and tags of products
+func (c *catalogRestore) restoreProductRelations(dump model.CatalogDump) error {
+	attributes := make([]schema.ProductAttribute, len(dump.ProductAttributes))
+	for i, a := range dump.ProductAttributes {
+		var err error
+		attributes[i].Value = a.Value
+		if attributes[i].ProductId, err = remap(c.products, model.DumpProducts, a.ProductId); err != nil {
+			return err
+		}
+		if attributes[i].AttributeId, err = remap(c.attributes, model.DumpAttributeDefinitions, a.AttributeId); err != nil {
+			return err
+		}
+	}
+	if err := c.create(model.DumpProductAttributes, &attributes, len(attributes)); err != nil {
+		return err
+	}
+
+	categories := make([]map[string]interface{}, len(dump.ProductCategories))
+	for i, pc := range dump.ProductCategories {
+		productId, err := remap(c.products, model.DumpProducts, pc.ProductId)
+		if err != nil {
+			return err
+		}
+		categoryId, err := remap(c.categories, model.DumpCategories, pc.CategoryId)
+		if err != nil {
+			return err
+		}
+		categories[i] = map[string]interface{}{"product_id": productId, "category_id": categoryId}
+	}
+	c.counts[model.DumpProductCategories] = len(categories)
+	if len(categories) != 0 {
+		if err := c.tx.Table("tbl_product_category").CreateInBatches(categories, restoreBatchSize).Error; err != nil {
+			return err
+		}
+	}
+
+	tags := make([]map[string]interface{}, len(dump.ProductTags))
+	for i, pt := range dump.ProductTags {
+		productId, err := remap(c.products, model.DumpProducts, pt.ProductId)
+		if err != nil {
+			return err
+		}
+		tagId, err := remap(c.tags, model.DumpTags, pt.TagId)
+		if err != nil {
+			return err
+		}
+		tags[i] = map[string]interface{}{"product_id": productId, "tag_id": tagId}
+	}
+	c.counts[model.DumpProductTags] = len(tags)
+	if len(tags) != 0 {
+		if err := c.tx.Table("tbl_product_tag").CreateInBatches(tags, restoreBatchSize).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restorePrices insert price lists with their prices and adjustments
+func (c *catalogRestore) restorePrices(dump model.CatalogDump) error {
+	lists := make([]schema.PriceList, len(dump.PriceLists))
+	for i, l := range dump.PriceLists {
+		lists[i] = schema.PriceList{CompanyId: c.companyId, Name: l.Name, Kind: l.Kind, Currency: l.Currency}
+	}
+	if err := c.create(model.DumpPriceLists, &lists, len(lists)); err != nil {
+		return err
+	}
+	c.priceLists = make(map[uint]uint, len(lists))
+	for i, l := range dump.PriceLists {
+		c.priceLists[l.Id] = lists[i].ID
+	}
+
+	prices := make([]schema.Price, len(dump.Prices))
+	for i, p := range dump.Prices {
+		var err error
+		prices[i] = schema.Price{Amount: p.Amount, ValidFrom: p.ValidFrom, ValidTo: p.ValidTo}
+		if prices[i].PriceListId, err = remap(c.priceLists, model.DumpPriceLists, p.PriceListId); err != nil {
+			return err
+		}
+		if prices[i].ProductId, err = remap(c.products, model.DumpProducts, p.ProductId); err != nil {
+			return err
+		}
+		// Price per square meter has no dimension
+		if p.DimensionId != 0 {
+			if prices[i].DimensionId, err = remap(c.dimensions, model.DumpDimensions, p.DimensionId); err != nil {
+				return err
+			}
+		}
+		if p.ThemeId != nil {
+			themeId, err := remap(c.themes, model.DumpThemes, *p.ThemeId)
+			if err != nil {
+				return err
+			}
+			prices[i].ThemeId = &themeId
+		}
+	}
+	if err := c.create(model.DumpPrices, &prices, len(prices)); err != nil {
+		return err
+	}
+
+	adjustments := make([]schema.PriceAdjustment, len(dump.PriceAdjustments))
+	for i, a := range dump.PriceAdjustments {
+		var err error
+		adjustments[i] = schema.PriceAdjustment{Kind: a.Kind, Value: a.Value, Percent: a.Percent}
+		if adjustments[i].PriceListId, err = remap(c.priceLists, model.DumpPriceLists, a.PriceListId); err != nil {
+			return err
+		}
+		// Adjustment of every product has no product
+		if a.ProductId != 0 {
+			if adjustments[i].ProductId, err = remap(c.products, model.DumpProducts, a.ProductId); err != nil {
+				return err
+			}
+		}
+	}
+	return c.create(model.DumpPriceAdjustments, &adjustments, len(adjustments))
+}
+
+// restoreAliases insert old design codes of products, an alias of a product that isn't in dump is invalid
+func (c *catalogRestore) restoreAliases(dump model.CatalogDump) error {
+	aliases := make([]schema.ProductAlias, len(dump.ProductAliases))
+	for i, a := range dump.ProductAliases {
+		var err error
+		aliases[i] = schema.ProductAlias{CompanyId: c.companyId, DesignCode: a.DesignCode}
+		if aliases[i].ProductId, err = remap(c.products, model.DumpProducts, a.ProductId); err != nil {
+			return err
+		}
+	}
+	return c.create(model.DumpProductAliases, &aliases, len(aliases))
+}
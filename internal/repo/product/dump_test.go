@@ -0,0 +1,68 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestProductRepo_RestoreCatalog(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := CreateProduct1(pRepo, t)
+	p2 := CreateProduct2(pRepo, t)
+	parent := CreateCategory(pRepo, t, "کلاسیک", nil)
+	CreateCategory(pRepo, t, "افشان", parent)
+	require.Nil(t, pRepo.AddTags(1, []uint{p1.ID, p2.ID}, []string{"new"}))
+
+	list, err := pRepo.CreatePriceList(model.PriceList{CompanyId: 1, Name: "عمده", Kind: model.PriceListWholesale, Currency: "IRR"})
+	require.Nil(t, err)
+	_, err = pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p1.ID, DimensionId: p1.Dimensions[0].ID,
+		ThemeId: p1.Themes[0].ID, Amount: 1500, ValidFrom: time.Now().Add(-time.Hour)})
+	require.Nil(t, err)
+
+	dump, err := pRepo.DumpCatalog(1)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(dump.Products))
+	require.Equal(t, 2, len(dump.Categories))
+	require.Equal(t, 2, len(dump.ProductTags))
+	require.Equal(t, 1, len(dump.Prices))
+
+	result, err := pRepo.RestoreCatalog(2, *dump)
+	require.Nil(t, err)
+	require.Equal(t, 2, result.Counts[model.DumpProducts])
+	require.Equal(t, len(dump.Dimensions), result.Counts[model.DumpDimensions])
+	require.Equal(t, 1, result.Counts[model.DumpPrices])
+	require.Equal(t, 2, len(result.ProductIds))
+	require.NotEqual(t, p1.ID, result.ProductIds[p1.ID])
+
+	restored, err := pRepo.GetProductWithId(result.ProductIds[p1.ID])
+	require.Nil(t, err)
+	require.Equal(t, uint(2), restored.CompanyId)
+	require.Equal(t, p1.DesignCode, restored.DesignCode)
+	require.Equal(t, len(p1.Themes), len(restored.Themes))
+
+	restoredDump, err := pRepo.DumpCatalog(2)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(restoredDump.Prices))
+	require.Equal(t, result.ProductIds[p1.ID], restoredDump.Prices[0].ProductId)
+
+	// Company 2 has a catalog now
+	_, err = pRepo.RestoreCatalog(2, *dump)
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.CatalogNotEmpty), derror.StatusText(err))
+
+	// A dangling reference rolls back whole restore
+	dump.Themes[0].ProductId = 1000
+	_, err = pRepo.RestoreCatalog(3, *dump)
+	require.NotNil(t, err)
+	products, err := pRepo.GetAllProducts(3)
+	require.Nil(t, err)
+	require.Equal(t, 0, len(products))
+}
@@ -0,0 +1,197 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExcludeCarpet remove a size and color of a product of `companyId` from its carpets,
+// if `exclusion.DimensionId` or `exclusion.ThemeId` is zero it is found with Size or Color.
+// a carpet with pieces in a warehouse can't be excluded, excluding an excluded carpet does nothing
+func (r *productRepo) ExcludeCarpet(companyId uint, exclusion model.CarpetExclusion) (result *model.CarpetExclusion, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("exclusion", fmt.Sprintf("%+v", exclusion)),
+			keyval.String("result", fmt.Sprintf("%+v", result)),
+		}
+		logger.LogReqRes(r.logger, "exclusion.ExcludeCarpet", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").Where("company_id = ?", companyId).First(&schema.Product{}, exclusion.ProductId).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return derror.ProductNotFound
+			}
+			return err
+		}
+
+		dimension := &schema.Dimension{}
+		query := tx.Where("product_id = ?", exclusion.ProductId)
+		if exclusion.DimensionId != 0 {
+			query = query.Where("id = ?", exclusion.DimensionId)
+		} else {
+			query = query.Where("size = ?", exclusion.Size)
+		}
+		if err := query.First(dimension).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return derror.DimensionNotFound
+			}
+			return err
+		}
+
+		theme := &schema.Theme{}
+		query = tx.Where("product_id = ?", exclusion.ProductId)
+		if exclusion.ThemeId != 0 {
+			query = query.Where("id = ?", exclusion.ThemeId)
+		} else {
+			query = query.Where("color = ?", exclusion.Color)
+		}
+		if err := query.First(theme).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return derror.ThemeNotFound
+			}
+			return err
+		}
+
+		var onHand int64
+		stock := tx.Model(&schema.Stock{}).Select("COALESCE(SUM(on_hand), 0)").
+			Where("product_id = ? AND dimension_id = ? AND theme_id = ?", exclusion.ProductId, dimension.ID, theme.ID).
+			Scan(&onHand)
+		if err := stock.Error; err != nil {
+			return err
+		}
+		if onHand != 0 {
+			return derror.New(derror.CarpetInStock, fmt.Sprintf("%v pieces on hand", onHand))
+		}
+
+		exclusion.DimensionId, exclusion.Size = dimension.ID, dimension.Size
+		exclusion.ThemeId, exclusion.Color = theme.ID, theme.Color
+
+		schemaExclusion := schema.CarpetExclusionModelToSchema(exclusion)
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(schemaExclusion).Error; err != nil {
+			return err
+		}
+
+		// Exclusion may already exist
+		existing := tx.Where("product_id = ? AND dimension_id = ? AND theme_id = ?", exclusion.ProductId, dimension.ID, theme.ID).
+			Take(schemaExclusion)
+		if err := existing.Error; err != nil {
+			return err
+		}
+		exclusion.Id, exclusion.Reason = schemaExclusion.ID, schemaExclusion.Reason
+
+		result = &exclusion
+		return nil
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return result, nil
+}
+
+// IncludeCarpet delete exclusion of a size and color of a product of `companyId`, carpet is listed again
+func (r *productRepo) IncludeCarpet(companyId, productId, dimensionId, themeId uint) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("dimension_id", fmt.Sprintf("%v", dimensionId)),
+			keyval.String("theme_id", fmt.Sprintf("%v", themeId)),
+		}
+		logger.LogReqRes(r.logger, "exclusion.IncludeCarpet", err, commonKeyVal...)
+	}()
+
+	products := r.db.Model(&schema.Product{}).Select("id").Where("company_id = ?", companyId)
+	tx := r.db.Where("product_id IN (?)", products).
+		Where("product_id = ? AND dimension_id = ? AND theme_id = ?", productId, dimensionId, themeId).
+		Delete(&schema.CarpetExclusion{})
+	if err := tx.Error; err != nil {
+		return derror.New(derror.InternalServer, err.Error())
+	} else if tx.RowsAffected < 1 {
+		return derror.ExclusionNotFound
+	}
+
+	return nil
+}
+
+// GetCarpetExclusions return excluded sizes and colors of a product of `companyId`
+func (r *productRepo) GetCarpetExclusions(companyId, productId uint) (exclusions []model.CarpetExclusion, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("exclusions", fmt.Sprintf("%+v", exclusions)),
+		}
+		logger.LogReqRes(r.logger, "exclusion.GetCarpetExclusions", err, commonKeyVal...)
+	}()
+
+	tx := r.db.Table("tbl_carpet_exclusion e").
+		Select("e.id, e.product_id, e.dimension_id, e.theme_id, d.size, t.color, e.reason").
+		Joins("JOIN tbl_product p ON p.id = e.product_id AND p.deleted_at IS NULL").
+		Joins("JOIN tbl_dimension d ON d.id = e.dimension_id AND d.deleted_at IS NULL").
+		Joins("JOIN tbl_theme t ON t.id = e.theme_id AND t.deleted_at IS NULL").
+		Where("p.company_id = ? AND e.product_id = ?", companyId, productId).
+		Order("e.dimension_id ASC").Order("e.theme_id ASC").
+		Scan(&exclusions)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	return exclusions, nil
+}
+
+// checkNotExcluded return derror.CarpetExcluded if size and color of product are excluded
+func checkNotExcluded(db *gorm.DB, productId, dimensionId, themeId uint) error {
+	var count int64
+	result := db.Model(&schema.CarpetExclusion{}).
+		Where("product_id = ? AND dimension_id = ? AND theme_id = ?", productId, dimensionId, themeId).
+		Count(&count)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if count != 0 {
+		return derror.New(derror.CarpetExcluded, fmt.Sprintf("dimension id %v, theme id %v", dimensionId, themeId))
+	}
+	return nil
+}
+
+// checkLabelsNotExcluded return derror.CarpetExcluded if `size` and `color` are a size and a color of product
+// and their carpet is excluded
+func checkLabelsNotExcluded(db *gorm.DB, productId uint, size, color string) error {
+	var count int64
+	result := db.Table("tbl_carpet_exclusion e").
+		Joins("JOIN tbl_dimension d ON d.id = e.dimension_id AND d.deleted_at IS NULL").
+		Joins("JOIN tbl_theme t ON t.id = e.theme_id AND t.deleted_at IS NULL").
+		Where("e.product_id = ? AND d.size = ? AND t.color = ?", productId, size, color).
+		Count(&count)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if count != 0 {
+		return derror.New(derror.CarpetExcluded, fmt.Sprintf("size %v, color %v", size, color))
+	}
+	return nil
+}
+
+// deleteDetachedExclusions delete exclusions of `productId` whose dimension or theme is deleted,
+// exclusions of kept sizes and colors are not changed
+func deleteDetachedExclusions(tx *gorm.DB, productId uint) error {
+	dimensions := tx.Model(&schema.Dimension{}).Select("id").Where("product_id = ?", productId)
+	themes := tx.Model(&schema.Theme{}).Select("id").Where("product_id = ?", productId)
+	return tx.Where("product_id = ?", productId).
+		Where("(dimension_id NOT IN (?) OR theme_id NOT IN (?))", dimensions, themes).
+		Delete(&schema.CarpetExclusion{}).Error
+}
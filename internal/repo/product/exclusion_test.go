@@ -0,0 +1,110 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestProductRepo_ExcludeCarpet(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	carpets, err := pRepo.GetAllCarpetWithProductId(1, p.ID)
+	require.Nil(t, err)
+	require.Equal(t, 4, len(carpets))
+
+	// Size 9 in red
+	exclusion, err := pRepo.ExcludeCarpet(1, model.CarpetExclusion{ProductId: p.ID, Size: "9", Color: "قرمز", Reason: "بافته نمی‌شود"})
+	require.Nil(t, err)
+	require.Equal(t, p.Dimensions[1].ID, exclusion.DimensionId)
+	require.Equal(t, p.Themes[0].ID, exclusion.ThemeId)
+
+	// Excluding again does nothing
+	again, err := pRepo.ExcludeCarpet(1, model.CarpetExclusion{ProductId: p.ID, DimensionId: exclusion.DimensionId, ThemeId: exclusion.ThemeId})
+	require.Nil(t, err)
+	require.Equal(t, exclusion.Id, again.Id)
+
+	carpets, err = pRepo.GetAllCarpetWithProductId(1, p.ID)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(carpets))
+
+	// Stock and pricing reject excluded carpet
+	warehouse, err := pRepo.CreateWarehouse(model.Warehouse{CompanyId: 1, Name: "مرکزی"})
+	require.Nil(t, err)
+	movement := model.StockMovement{
+		CompanyId:   1,
+		WarehouseId: warehouse.ID,
+		ProductId:   p.ID,
+		DimensionId: exclusion.DimensionId,
+		ThemeId:     exclusion.ThemeId,
+		Kind:        model.MovementReceipt,
+		Quantity:    1,
+	}
+	_, err = pRepo.RecordStockMovement(movement)
+	require.Equal(t, derror.StatusText(derror.CarpetExcluded), derror.StatusText(err))
+
+	list, err := pRepo.CreatePriceList(model.PriceList{CompanyId: 1, Name: "عمده", Kind: model.PriceListWholesale, Currency: "IRR"})
+	require.Nil(t, err)
+	_, err = pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p.ID, DimensionId: exclusion.DimensionId, ThemeId: exclusion.ThemeId, Amount: 1000, ValidFrom: time.Now()})
+	require.Equal(t, derror.StatusText(derror.CarpetExcluded), derror.StatusText(err))
+
+	// A carpet in stock can't be excluded
+	movement.ThemeId = p.Themes[1].ID
+	_, err = pRepo.RecordStockMovement(movement)
+	require.Nil(t, err)
+	_, err = pRepo.ExcludeCarpet(1, model.CarpetExclusion{ProductId: p.ID, DimensionId: movement.DimensionId, ThemeId: movement.ThemeId})
+	require.Equal(t, derror.StatusText(derror.CarpetInStock), derror.StatusText(err))
+
+	// Exclusion survives edit of other sizes and colors
+	_, err = pRepo.EditProduct(model.Product{Id: p.ID, DesignCode: p.DesignCode, Colors: []string{"قرمز", "آبی", "سبز"}, Sizes: []string{"9", "12"}})
+	require.Nil(t, err)
+	exclusions, err := pRepo.GetCarpetExclusions(1, p.ID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(exclusions))
+	require.Equal(t, "9", exclusions[0].Size)
+	require.Equal(t, "قرمز", exclusions[0].Color)
+
+	carpets, err = pRepo.GetAllCarpetWithProductId(1, p.ID)
+	require.Nil(t, err)
+	require.Equal(t, 5, len(carpets))
+
+	// Exclusion of a removed size is deleted
+	_, err = pRepo.EditProduct(model.Product{Id: p.ID, DesignCode: p.DesignCode, Colors: []string{"قرمز", "آبی", "سبز"}, Sizes: []string{"12"}})
+	require.Nil(t, err)
+	exclusions, err = pRepo.GetCarpetExclusions(1, p.ID)
+	require.Nil(t, err)
+	require.Equal(t, 0, len(exclusions))
+
+	err = pRepo.IncludeCarpet(1, p.ID, exclusion.DimensionId, exclusion.ThemeId)
+	require.Equal(t, derror.StatusText(derror.ExclusionNotFound), derror.StatusText(err))
+}
+
+func TestProductRepo_IncludeCarpet(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	exclusion, err := pRepo.ExcludeCarpet(1, model.CarpetExclusion{ProductId: p.ID, DimensionId: p.Dimensions[0].ID, ThemeId: p.Themes[1].ID})
+	require.Nil(t, err)
+
+	// Another company
+	err = pRepo.IncludeCarpet(2, p.ID, exclusion.DimensionId, exclusion.ThemeId)
+	require.Equal(t, derror.StatusText(derror.ExclusionNotFound), derror.StatusText(err))
+
+	err = pRepo.IncludeCarpet(1, p.ID, exclusion.DimensionId, exclusion.ThemeId)
+	require.Nil(t, err)
+
+	carpets, err := pRepo.GetAllCarpetWithProductId(1, p.ID)
+	require.Nil(t, err)
+	require.Equal(t, 4, len(carpets))
+}
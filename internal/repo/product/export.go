@@ -0,0 +1,285 @@
+package product
+
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// exportBatchSize is number of products read from database at once by an export
+const exportBatchSize = 100
+
+// ExportProducts call `fn` with every product that match `filter` in order of id,
+// products are read in batches so memory of an export doesn't grow with catalog.
+// If export stops at filter.MaxBatches, id of the last product is returned to continue after it, otherwise zero
+func (r *productRepo) ExportProducts(filter model.ExportFilter, fn func(model.ExportProduct) error) (nextId uint, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("filter", fmt.Sprintf("%+v", filter)),
+			keyval.String("next_id", fmt.Sprintf("%v", nextId)),
+		}
+		logger.LogReqRes(r.logger, "export.ExportProducts", err, commonKeyVal...)
+	}()
+
+	list, err := exportPriceList(r.db, filter)
+	if err != nil {
+		return 0, derror.Wrap(err)
+	}
+
+	lastId := filter.AfterId
+	for batch := 1; ; batch++ {
+		query, err := filterProducts(r.db, filter.ProductFilter)
+		if err != nil {
+			return 0, err
+		}
+
+		var products []schema.Product
+		tx := query.Preload(clause.Associations).Preload("Attributes.Attribute").
+			Where("tbl_product.id > ?", lastId).Order("tbl_product.id ASC").Limit(exportBatchSize).
+			Find(&products)
+		if err := tx.Error; err != nil {
+			return 0, derror.New(derror.InternalServer, err.Error())
+		}
+		if len(products) == 0 {
+			return 0, nil
+		}
+
+		productIds := make([]uint, len(products))
+		for i := range products {
+			productIds[i] = products[i].ID
+		}
+
+		onHand, available, err := productsStock(r.db, productIds...)
+		if err != nil {
+			return 0, derror.New(derror.InternalServer, err.Error())
+		}
+
+		prices, err := loadExportPrices(r.db, list, productIds, filter.At)
+		if err != nil {
+			return 0, derror.New(derror.InternalServer, err.Error())
+		}
+
+		for _, p := range products {
+			row := model.ExportProduct{
+				Id:          p.ID,
+				DesignCode:  p.DesignCode,
+				Description: p.Description,
+				Status:      p.Status,
+				Sizes:       schema.GetSizes(p.Dimensions),
+				Colors:      schema.GetColors(p.Themes),
+				Attributes:  schema.GetAttributes(p.Attributes),
+				Categories:  schema.GetCategoryIds(p.Categories),
+				Tags:        schema.GetTagNames(p.Tags),
+				OnHand:      onHand[p.ID],
+				Available:   available[p.ID],
+				Currency:    prices.currency(),
+			}
+			if price, found := prices.perSquareMeter(p.ID); found {
+				row.PricePerSquareMeter = &price.Amount
+			}
+
+			if err := fn(row); err != nil {
+				return 0, derror.Wrap(err)
+			}
+		}
+
+		if len(products) < exportBatchSize {
+			return 0, nil
+		}
+		lastId = products[len(products)-1].ID
+		if batch == filter.MaxBatches {
+			return lastId, nil
+		}
+	}
+}
+
+// ExportCarpets call `fn` with every carpet of products that match `filter` in order of product, size and color,
+// carpets are read in batches of products so memory of an export doesn't grow with catalog.
+// If export stops at filter.MaxBatches, id of the last product is returned to continue after it, otherwise zero
+func (r *productRepo) ExportCarpets(filter model.ExportFilter, fn func(model.ExportCarpet) error) (nextId uint, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("filter", fmt.Sprintf("%+v", filter)),
+			keyval.String("next_id", fmt.Sprintf("%v", nextId)),
+		}
+		logger.LogReqRes(r.logger, "export.ExportCarpets", err, commonKeyVal...)
+	}()
+
+	list, err := exportPriceList(r.db, filter)
+	if err != nil {
+		return 0, derror.Wrap(err)
+	}
+
+	lastId := filter.AfterId
+	for batch := 1; ; batch++ {
+		query, err := filterProducts(r.db, filter.ProductFilter)
+		if err != nil {
+			return 0, err
+		}
+
+		var productIds []uint
+		tx := query.Where("tbl_product.id > ?", lastId).Order("tbl_product.id ASC").Limit(exportBatchSize).
+			Pluck("tbl_product.id", &productIds)
+		if err := tx.Error; err != nil {
+			return 0, derror.New(derror.InternalServer, err.Error())
+		}
+		if len(productIds) == 0 {
+			return 0, nil
+		}
+
+		var schemaCarpets []schema.Carpet
+		err = r.withCarpetView(filter.CompanyId, func(view string) error {
+			return r.db.Table(view).Where("product_id IN ?", productIds).
+				Order("product_id ASC").Order("dimension_id ASC").Order("theme_id ASC").
+				Find(&schemaCarpets).Error
+		})
+		if err != nil {
+			return 0, derror.New(derror.InternalServer, err.Error())
+		}
+
+		carpets := make([]model.Carpet, len(schemaCarpets))
+		for i := range schemaCarpets {
+			carpets[i] = schema.CarpetToModel(&schemaCarpets[i], filter.CompanyId)
+		}
+
+		if err := setCarpetsStock(r.db, carpets); err != nil {
+			return 0, derror.New(derror.InternalServer, err.Error())
+		}
+
+		prices, err := loadExportPrices(r.db, list, productIds, filter.At)
+		if err != nil {
+			return 0, derror.New(derror.InternalServer, err.Error())
+		}
+
+		for _, c := range carpets {
+			row := model.ExportCarpet{Carpet: c, Currency: prices.currency()}
+			if price, found := prices.carpet(c); found {
+				row.Price, row.PriceSource = &price.Amount, price.Source()
+			}
+
+			if err := fn(row); err != nil {
+				return 0, derror.Wrap(err)
+			}
+		}
+
+		if len(productIds) < exportBatchSize {
+			return 0, nil
+		}
+		lastId = productIds[len(productIds)-1]
+		if batch == filter.MaxBatches {
+			return lastId, nil
+		}
+	}
+}
+
+// exportPriceList return price list of an export, nil if export has no prices
+func exportPriceList(db *gorm.DB, filter model.ExportFilter) (*schema.PriceList, error) {
+	if filter.PriceListId == 0 {
+		return nil, nil
+	}
+	return getPriceList(db, filter.CompanyId, filter.PriceListId)
+}
+
+// exportPrices is prices of a batch of products in a price list of an export,
+// without a price list every carpet is without price
+type exportPrices struct {
+	list        *schema.PriceList
+	at          time.Time
+	prices      map[uint][]model.Price
+	adjustments map[uint][]model.PriceAdjustment // Adjustments of every product have zero key
+	sizes       map[uint]model.CarpetSize        // Measure of dimensions with a valid size
+}
+
+func loadExportPrices(db *gorm.DB, list *schema.PriceList, productIds []uint, at time.Time) (*exportPrices, error) {
+	p := &exportPrices{list: list, at: at}
+	if list == nil {
+		return p, nil
+	}
+
+	var schemaPrices []schema.Price
+	if err := db.Where("price_list_id = ? AND product_id IN ?", list.ID, productIds).Find(&schemaPrices).Error; err != nil {
+		return nil, err
+	}
+	p.prices = make(map[uint][]model.Price)
+	for i := range schemaPrices {
+		p.prices[schemaPrices[i].ProductId] = append(p.prices[schemaPrices[i].ProductId], schema.PriceToModel(&schemaPrices[i]))
+	}
+
+	var schemaAdjustments []schema.PriceAdjustment
+	tx := db.Where("price_list_id = ? AND product_id IN ?", list.ID, append([]uint{0}, productIds...)).Find(&schemaAdjustments)
+	if err := tx.Error; err != nil {
+		return nil, err
+	}
+	p.adjustments = make(map[uint][]model.PriceAdjustment)
+	for i := range schemaAdjustments {
+		p.adjustments[schemaAdjustments[i].ProductId] = append(p.adjustments[schemaAdjustments[i].ProductId], schema.PriceAdjustmentToModel(&schemaAdjustments[i]))
+	}
+
+	var dimensions []struct {
+		Id     uint
+		Size   string
+		Width  uint
+		Length uint
+	}
+	tx = db.Table("tbl_dimension d").
+		Select("d.id, d.size, s.width, s.length").
+		Joins("LEFT JOIN tbl_standard_size s ON s.id = d.standard_size_id AND s.deleted_at IS NULL").
+		Where("d.product_id IN ? AND d.deleted_at IS NULL", productIds).
+		Scan(&dimensions)
+	if err := tx.Error; err != nil {
+		return nil, err
+	}
+	p.sizes = make(map[uint]model.CarpetSize, len(dimensions))
+	for _, d := range dimensions {
+		// A size without measure has no area price
+		if size, err := model.SizeOf(d.Size, d.Width, d.Length, ""); err == nil {
+			p.sizes[d.Id] = size
+		}
+	}
+
+	return p, nil
+}
+
+func (p *exportPrices) currency() string {
+	if p.list == nil {
+		return ""
+	}
+	return p.list.Currency
+}
+
+// perSquareMeter return price per square meter of `productId`
+func (p *exportPrices) perSquareMeter(productId uint) (model.Price, bool) {
+	if p.list == nil {
+		return model.Price{}, false
+	}
+	return model.ResolvePrice(p.prices[productId], 0, 0, p.at)
+}
+
+// carpet return effective price of `carpet` like ResolvePrice, an area price has total of its breakdown as amount
+func (p *exportPrices) carpet(carpet model.Carpet) (model.Price, bool) {
+	if p.list == nil {
+		return model.Price{}, false
+	}
+
+	if price, found := model.ResolvePrice(p.prices[carpet.ProductId], carpet.DimensionId, carpet.ThemeId, p.at); found {
+		return price, true
+	}
+
+	price, found := p.perSquareMeter(carpet.ProductId)
+	size, measured := p.sizes[carpet.DimensionId]
+	if !found || !measured {
+		return model.Price{}, false
+	}
+
+	adjustments := append(append([]model.PriceAdjustment{}, p.adjustments[0]...), p.adjustments[carpet.ProductId]...)
+	price.Amount = model.CalculateAreaPrice(price.Amount, size, carpet.Color, adjustments).Total
+	return price, true
+}
@@ -0,0 +1,78 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestProductRepo_ExportCarpets(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	CreateProduct2(pRepo, t)
+
+	warehouse, err := pRepo.CreateWarehouse(model.Warehouse{CompanyId: 1, Name: "مرکزی"})
+	require.Nil(t, err)
+	_, err = pRepo.RecordStockMovement(model.StockMovement{CompanyId: 1, WarehouseId: warehouse.ID, ProductId: p.ID,
+		DimensionId: p.Dimensions[0].ID, ThemeId: p.Themes[0].ID, Kind: model.MovementReceipt, Quantity: 4})
+	require.Nil(t, err)
+
+	list, err := pRepo.CreatePriceList(model.PriceList{CompanyId: 1, Name: "عمده", Kind: model.PriceListWholesale, Currency: "IRR"})
+	require.Nil(t, err)
+	_, err = pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p.ID, DimensionId: p.Dimensions[0].ID,
+		ThemeId: p.Themes[0].ID, Amount: 1500, ValidFrom: time.Now().Add(-time.Hour)})
+	require.Nil(t, err)
+
+	filter := model.ExportFilter{ProductFilter: model.ProductFilter{CompanyId: 1}, PriceListId: list.ID, At: time.Now()}
+
+	var carpets []model.ExportCarpet
+	nextId, err := pRepo.ExportCarpets(filter, func(c model.ExportCarpet) error {
+		carpets = append(carpets, c)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, uint(0), nextId)
+	require.Equal(t, 8, len(carpets))
+	require.Equal(t, p.ID, carpets[0].ProductId)
+
+	for _, c := range carpets {
+		require.Equal(t, "IRR", c.Currency)
+		if c.DimensionId == p.Dimensions[0].ID && c.ThemeId == p.Themes[0].ID {
+			require.Equal(t, int64(4), c.OnHand)
+			require.NotNil(t, c.Price)
+			require.Equal(t, int64(1500), *c.Price)
+			require.Equal(t, model.PriceSourceCarpet, c.PriceSource)
+		} else {
+			require.Equal(t, int64(0), c.OnHand)
+		}
+	}
+
+	var products []model.ExportProduct
+	filter.ProductIds = []uint{p.ID}
+	_, err = pRepo.ExportProducts(filter, func(p model.ExportProduct) error {
+		products = append(products, p)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(products))
+	require.Equal(t, int64(4), products[0].OnHand)
+	require.ElementsMatch(t, []string{"6", "9"}, products[0].Sizes)
+
+	// Continue after first product
+	products = nil
+	filter.ProductIds, filter.AfterId, filter.MaxBatches = nil, p.ID, 1
+	nextId, err = pRepo.ExportProducts(filter, func(p model.ExportProduct) error {
+		products = append(products, p)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, uint(0), nextId)
+	require.Equal(t, 1, len(products))
+	require.NotEqual(t, p.ID, products[0].Id)
+}
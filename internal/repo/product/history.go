@@ -0,0 +1,36 @@
+package product
+
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+// GetProductHistory return history records of product `productId` of `companyId`, oldest first
+func (r *productRepo) GetProductHistory(companyId, productId uint) (history []schema.ProductHistory, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("history", fmt.Sprintf("%+v", history)),
+		}
+		logger.LogReqRes(r.logger, "history.GetProductHistory", err, commonKeyVal...)
+	}()
+
+	var count int64
+	if err := r.db.Model(&schema.Product{}).Where("company_id = ? AND id = ?", companyId, productId).Count(&count).Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	if count == 0 {
+		return nil, derror.New(derror.ProductNotFound, fmt.Sprintf("product id %v", productId))
+	}
+
+	history = []schema.ProductHistory{}
+	if err := r.db.Where("company_id = ? AND product_id = ?", companyId, productId).Order("id ASC").Find(&history).Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	return history, nil
+}
@@ -0,0 +1,132 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errDryRun roll back a chunk of a dry run import
+var errDryRun = errors.New("dry run")
+
+// ImportProducts create products of `companyId` with a new design code and edit products with an existing one.
+// rows are saved in a transaction per `chunkSize` rows and a failed row is rolled back alone.
+// in a dry run every chunk is rolled back and results are what a commit would do
+func (r *productRepo) ImportProducts(companyId uint, rows []model.ImportRow, chunkSize int, dryRun bool) (results []model.ImportResult, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("rows", fmt.Sprintf("%v", len(rows))),
+			keyval.String("chunk_size", fmt.Sprintf("%v", chunkSize)),
+			keyval.String("dry_run", fmt.Sprintf("%v", dryRun)),
+			keyval.String("results", fmt.Sprintf("%+v", results)),
+		}
+		logger.LogReqRes(r.logger, "import.ImportProducts", err, commonKeyVal...)
+	}()
+
+	if chunkSize <= 0 {
+		chunkSize = len(rows)
+	}
+
+	// First product of a company creates its carpet view
+	if !dryRun && len(rows) != 0 {
+		if _, err := r.carpetView(companyId); err != nil {
+			return nil, derror.New(derror.InternalServer, err.Error())
+		}
+	}
+
+	results = make([]model.ImportResult, 0, len(rows))
+	for start := 0; start < len(rows); start += chunkSize {
+		end := start + chunkSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		chunk := rows[start:end]
+
+		var chunkResults []model.ImportResult
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			chunkResults = make([]model.ImportResult, len(chunk))
+			for i, row := range chunk {
+				chunkResults[i] = r.importRow(tx, companyId, row)
+			}
+			if dryRun {
+				return errDryRun
+			}
+			return nil
+		})
+
+		// Chunk is rolled back, none of its rows is saved
+		if err != nil && !errors.Is(err, errDryRun) {
+			chunkResults = make([]model.ImportResult, len(chunk))
+			for i, row := range chunk {
+				chunkResults[i] = model.ImportResult{
+					Line:       row.Line,
+					DesignCode: row.Product.DesignCode,
+					Action:     model.ImportFail,
+					Error:      derror.Wrap(err).Error(),
+				}
+			}
+		}
+
+		results = append(results, chunkResults...)
+	}
+
+	// Products of a dry run are not created
+	if dryRun {
+		for i := range results {
+			if results[i].Action == model.ImportCreate {
+				results[i].ProductId = 0
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// importRow create or edit product of `row` in a nested transaction of `tx`
+func (r *productRepo) importRow(tx *gorm.DB, companyId uint, row model.ImportRow) model.ImportResult {
+	result := model.ImportResult{Line: row.Line, DesignCode: row.Product.DesignCode}
+
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		product := row.Product
+		product.CompanyId = companyId
+
+		originalProduct := &schema.Product{}
+		err := tx.Preload(clause.Associations).Preload("Attributes.Attribute").
+			Where("company_id = ? AND design_code = ?", companyId, product.DesignCode).
+			Take(originalProduct).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			schemaProduct, err := createProduct(tx, product)
+			if err != nil {
+				return err
+			}
+			result.Action, result.ProductId = model.ImportCreate, schemaProduct.ID
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// Catalog sizes and categories are not columns of an import, they are kept
+		product.Id = originalProduct.ID
+		product.StandardSizes = schema.GetStandardSizeIds(originalProduct.Dimensions)
+		product.Categories = schema.GetCategoryIds(originalProduct.Categories)
+		if _, err := r.editProduct(tx, originalProduct, product); err != nil {
+			return err
+		}
+		result.Action, result.ProductId = model.ImportUpdate, originalProduct.ID
+		return nil
+	})
+
+	if err != nil {
+		result.Action, result.ProductId, result.Error = model.ImportFail, 0, derror.Wrap(err).Error()
+	}
+
+	return result
+}
@@ -0,0 +1,58 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestProductRepo_ImportProducts(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+
+	rows := []model.ImportRow{
+		{Line: 2, Product: model.Product{CompanyId: 1, DesignCode: "105", Description: "ویرایش", Sizes: []string{"6", "12"}, Colors: []string{"قرمز"}}},
+		{Line: 3, Product: model.Product{CompanyId: 1, DesignCode: "201", Sizes: []string{"6"}, Colors: []string{"قرمز"}}},
+		{Line: 4, Product: model.Product{CompanyId: 1, DesignCode: "202", Sizes: []string{"6"}, Colors: []string{"قرمز"}, Attributes: map[string]string{"unknown": "x"}}},
+	}
+
+	// Dry run saves nothing
+	results, err := pRepo.ImportProducts(1, rows, 2, true)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(results))
+	require.Equal(t, model.ImportUpdate, results[0].Action)
+	require.Equal(t, p.ID, results[0].ProductId)
+	require.Equal(t, model.ImportCreate, results[1].Action)
+	require.Equal(t, uint(0), results[1].ProductId)
+	require.Equal(t, model.ImportFail, results[2].Action)
+	require.NotEmpty(t, results[2].Error)
+
+	products, err := pRepo.GetAllProducts(1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(products))
+	product, err := pRepo.GetProductWithId(p.ID)
+	require.Nil(t, err)
+	require.Equal(t, p.Description, product.Description)
+
+	// Commit saves rows that don't fail
+	results, err = pRepo.ImportProducts(1, rows, 2, false)
+	require.Nil(t, err)
+	require.Equal(t, model.ImportUpdate, results[0].Action)
+	require.Equal(t, model.ImportCreate, results[1].Action)
+	require.NotEqual(t, uint(0), results[1].ProductId)
+	require.Equal(t, model.ImportFail, results[2].Action)
+
+	products, err = pRepo.GetAllProducts(1)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(products))
+	product, err = pRepo.GetProductWithId(p.ID)
+	require.Nil(t, err)
+	require.Equal(t, "ویرایش", product.Description)
+	require.Equal(t, 2, len(product.Dimensions))
+	require.Equal(t, 1, len(product.Themes))
+}
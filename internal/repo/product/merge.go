@@ -0,0 +1,485 @@
+package product
+
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"github.com/seed95/product-service/pkg/normalize"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mergeReference is reference of stock movements that move pieces of a merged product
+const mergeReference = "merge"
+
+// MergeProducts fold product `sourceId` into product `targetId` of `companyId` in one transaction and return target.
+// colors and sizes of source that target lacks are added to target and pieces of source are moved to carpets of
+// target with the same size and color. prices, tags, categories and attributes of source are moved where target
+// has none, design codes of source are kept as aliases of target and source is deleted. the merge is recorded
+// in history of target
+func (r *productRepo) MergeProducts(companyId, sourceId, targetId uint) (product *schema.Product, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("source_id", fmt.Sprintf("%v", sourceId)),
+			keyval.String("target_id", fmt.Sprintf("%v", targetId)),
+			keyval.String("product", fmt.Sprintf("%+v", product)),
+		}
+		logger.LogReqRes(r.logger, "merge.MergeProducts", err, commonKeyVal...)
+	}()
+
+	if sourceId == targetId {
+		return nil, derror.New(derror.InvalidProduct, "product is merged into itself")
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock in id order, so two merges of the same products don't deadlock
+		first, second := sourceId, targetId
+		if first > second {
+			first, second = second, first
+		}
+		locked := make(map[uint]*schema.Product, 2)
+		for _, id := range []uint{first, second} {
+			p, err := lockProduct(tx, companyId, id)
+			if err != nil {
+				return err
+			}
+			locked[id] = p
+		}
+
+		merge := &productMerge{
+			repo:   r,
+			tx:     tx,
+			source: locked[sourceId],
+			target: locked[targetId],
+		}
+		return merge.run()
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return r.GetProductWithId(targetId)
+}
+
+// productMerge move relations of source to target and keep id of target dimension and theme of every source one
+type productMerge struct {
+	repo   *productRepo
+	tx     *gorm.DB
+	source *schema.Product
+	target *schema.Product
+
+	dimensions    map[uint]uint
+	themes        map[uint]uint
+	newDimensions map[uint]bool // Target dimensions added by merge
+	newThemes     map[uint]bool // Target themes added by merge
+	sizes         []string
+	colors        []string
+	pieces        int64
+}
+
+func (m *productMerge) run() error {
+	steps := []func() error{
+		m.mergeDimensions,
+		m.mergeThemes,
+		m.moveReservations,
+		m.moveStock,
+		m.movePrices,
+		m.movePriceAdjustments,
+		m.moveExclusions,
+		m.mergeAttributes,
+		m.mergeTagsAndCategories,
+		m.moveAliases,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+
+	// Dimensions, themes, attributes, categories and tags of source
+	if err := m.tx.Select(clause.Associations).Delete(m.source).Error; err != nil {
+		return err
+	}
+
+	history := &schema.ProductHistory{
+		CompanyId:       m.target.CompanyId,
+		ProductId:       m.target.ID,
+		Kind:            model.HistoryMerge,
+		SourceProductId: m.source.ID,
+		Note: fmt.Sprintf("design code %v merged, sizes %v and colors %v added, %v pieces moved",
+			m.source.DesignCode, m.sizes, m.colors, m.pieces),
+	}
+	return m.tx.Create(history).Error
+}
+
+// mergeDimensions add sizes of source that target lacks to target, a catalog size of a dimension is kept
+func (m *productMerge) mergeDimensions() error {
+	sourceDimensions, err := m.repo.dimension.GetDimensionsWithProductId(m.tx, m.source.ID)
+	if err != nil {
+		return err
+	}
+	targetDimensions, err := m.repo.dimension.GetDimensionsWithProductId(m.tx, m.target.ID)
+	if err != nil {
+		return err
+	}
+
+	m.dimensions = make(map[uint]uint, len(sourceDimensions))
+	m.newDimensions = make(map[uint]bool)
+	var added []schema.Dimension
+	for _, d := range sourceDimensions {
+		if id, ok := dimensionWithSize(targetDimensions, d.Size); ok {
+			m.dimensions[d.ID] = id
+			continue
+		}
+		added = append(added, d)
+		m.sizes = append(m.sizes, d.Size)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	dimensions, err := m.repo.dimension.InsertDimensions(m.tx, m.target.ID, m.sizes)
+	if err != nil {
+		return err
+	}
+	for i := range dimensions {
+		m.dimensions[added[i].ID] = dimensions[i].ID
+		m.newDimensions[dimensions[i].ID] = true
+		if added[i].StandardSizeId == nil {
+			continue
+		}
+		if err := m.tx.Model(&dimensions[i]).Update("standard_size_id", added[i].StandardSizeId).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeThemes add colors of source that target lacks to target
+func (m *productMerge) mergeThemes() error {
+	sourceThemes, err := m.repo.theme.GetThemesWithProductId(m.tx, m.source.ID)
+	if err != nil {
+		return err
+	}
+	targetThemes, err := m.repo.theme.GetThemesWithProductId(m.tx, m.target.ID)
+	if err != nil {
+		return err
+	}
+
+	m.themes = make(map[uint]uint, len(sourceThemes))
+	m.newThemes = make(map[uint]bool)
+	var added []schema.Theme
+	for _, t := range sourceThemes {
+		if id, ok := themeWithColor(targetThemes, t.Color); ok {
+			m.themes[t.ID] = id
+			continue
+		}
+		added = append(added, t)
+		m.colors = append(m.colors, t.Color)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	themes, err := m.repo.theme.InsertThemesWithColor(m.tx, m.target.ID, m.colors)
+	if err != nil {
+		return err
+	}
+	for i := range themes {
+		m.themes[added[i].ID] = themes[i].ID
+		m.newThemes[themes[i].ID] = true
+	}
+	return nil
+}
+
+// carpet return target dimension and theme of a carpet of source, ok is false for a removed size or color of source
+func (m *productMerge) carpet(dimensionId, themeId uint) (uint, uint, bool) {
+	d, dimensionOk := m.dimensions[dimensionId]
+	t, themeOk := m.themes[themeId]
+	return d, t, dimensionOk && themeOk
+}
+
+// moveReservations move reservations of source to carpets of target before their pieces are moved,
+// an active reservation of a removed carpet of source is released
+func (m *productMerge) moveReservations() error {
+	var reservations []schema.Reservation
+	if err := m.tx.Where("product_id = ?", m.source.ID).Order("id").Find(&reservations).Error; err != nil {
+		return err
+	}
+
+	for _, reservation := range reservations {
+		dimensionId, themeId, ok := m.carpet(reservation.DimensionId, reservation.ThemeId)
+		if !ok {
+			if reservation.ReleasedAt != nil {
+				continue
+			}
+			if err := releaseReservation(m.tx, &reservation, model.ReleaseMerged); err != nil {
+				return err
+			}
+			continue
+		}
+		result := m.tx.Model(&reservation).Updates(map[string]interface{}{
+			"product_id":   m.target.ID,
+			"dimension_id": dimensionId,
+			"theme_id":     themeId,
+		})
+		if err := result.Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveStock move pieces of every carpet of source to carpet of target with the same size and color,
+// each move is an adjustment out of source and an adjustment into target in stock ledger
+func (m *productMerge) moveStock() error {
+	var stocks []schema.Stock
+	if err := m.tx.Where("product_id = ? AND on_hand <> 0", m.source.ID).Order("id").Find(&stocks).Error; err != nil {
+		return err
+	}
+
+	note := fmt.Sprintf("design code %v merged into %v", m.source.DesignCode, m.target.DesignCode)
+	for _, s := range stocks {
+		dimensionId, themeId, ok := m.carpet(s.DimensionId, s.ThemeId)
+		if !ok {
+			return derror.New(derror.CarpetInStock, fmt.Sprintf("%v pieces of removed carpet in warehouse id %v", s.OnHand, s.WarehouseId))
+		}
+
+		movement := model.StockMovement{
+			CompanyId:   m.source.CompanyId,
+			ProductId:   m.source.ID,
+			DimensionId: s.DimensionId,
+			ThemeId:     s.ThemeId,
+			Kind:        model.MovementAdjustment,
+			Reference:   mergeReference,
+			Note:        note,
+		}
+		if _, err := moveStock(m.tx, movement, s.WarehouseId, 0, -s.OnHand); err != nil {
+			return err
+		}
+
+		movement.ProductId, movement.DimensionId, movement.ThemeId = m.target.ID, dimensionId, themeId
+		if _, err := moveStock(m.tx, movement, s.WarehouseId, 0, s.OnHand); err != nil {
+			return err
+		}
+		m.pieces += s.OnHand
+	}
+	return nil
+}
+
+// priceKey is a carpet of a price list, zero ThemeId is price of every color
+type priceKey struct {
+	PriceListId uint
+	DimensionId uint
+	ThemeId     uint
+}
+
+// movePrices move prices of source to target, a price of a carpet that target already has
+// a price for in an overlapping validity period is deleted
+func (m *productMerge) movePrices() error {
+	var targetPrices []schema.Price
+	if err := m.tx.Where("product_id = ?", m.target.ID).Find(&targetPrices).Error; err != nil {
+		return err
+	}
+	priced := make(map[priceKey][]schema.Price, len(targetPrices))
+	for _, p := range targetPrices {
+		key := priceKey{PriceListId: p.PriceListId, DimensionId: p.DimensionId}
+		if p.ThemeId != nil {
+			key.ThemeId = *p.ThemeId
+		}
+		priced[key] = append(priced[key], p)
+	}
+
+	var prices []schema.Price
+	if err := m.tx.Where("product_id = ?", m.source.ID).Order("id").Find(&prices).Error; err != nil {
+		return err
+	}
+
+	for _, p := range prices {
+		key, ok := priceKey{PriceListId: p.PriceListId}, true
+		// Price per square meter has no dimension
+		if p.DimensionId != 0 {
+			key.DimensionId, ok = m.dimensions[p.DimensionId]
+		}
+		var themeId *uint
+		if ok && p.ThemeId != nil {
+			key.ThemeId, ok = m.themes[*p.ThemeId]
+			themeId = &key.ThemeId
+		}
+
+		if !ok || pricesOverlap(priced[key], p) {
+			if err := m.tx.Delete(&p).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		result := m.tx.Model(&p).Updates(map[string]interface{}{
+			"product_id":   m.target.ID,
+			"dimension_id": key.DimensionId,
+			"theme_id":     themeId,
+		})
+		if err := result.Error; err != nil {
+			return err
+		}
+		priced[key] = append(priced[key], p)
+	}
+	return nil
+}
+
+// pricesOverlap report whether validity period of `price` overlaps one of `prices`, a nil ValidTo is open ended
+func pricesOverlap(prices []schema.Price, price schema.Price) bool {
+	for _, p := range prices {
+		startsBeforeEnd := price.ValidTo == nil || p.ValidFrom.Before(*price.ValidTo)
+		endsAfterStart := p.ValidTo == nil || price.ValidFrom.Before(*p.ValidTo)
+		if startsBeforeEnd && endsAfterStart {
+			return true
+		}
+	}
+	return false
+}
+
+// movePriceAdjustments move adjustments of source to target, an adjustment that target already has is deleted
+func (m *productMerge) movePriceAdjustments() error {
+	type adjustmentKey struct {
+		PriceListId uint
+		Kind        string
+		Value       string
+	}
+
+	var targetAdjustments []schema.PriceAdjustment
+	if err := m.tx.Where("product_id = ?", m.target.ID).Find(&targetAdjustments).Error; err != nil {
+		return err
+	}
+	adjusted := make(map[adjustmentKey]bool, len(targetAdjustments))
+	for _, a := range targetAdjustments {
+		adjusted[adjustmentKey{a.PriceListId, a.Kind, a.Value}] = true
+	}
+
+	var adjustments []schema.PriceAdjustment
+	if err := m.tx.Where("product_id = ?", m.source.ID).Order("id").Find(&adjustments).Error; err != nil {
+		return err
+	}
+
+	for _, a := range adjustments {
+		if adjusted[adjustmentKey{a.PriceListId, a.Kind, a.Value}] {
+			if err := m.tx.Delete(&a).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err := m.tx.Model(&a).Update("product_id", m.target.ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveExclusions move an exclusion of source to target only if its carpet is new to target,
+// a carpet target already had keeps its exclusion state
+func (m *productMerge) moveExclusions() error {
+	var exclusions []schema.CarpetExclusion
+	if err := m.tx.Where("product_id = ?", m.source.ID).Find(&exclusions).Error; err != nil {
+		return err
+	}
+
+	for _, e := range exclusions {
+		dimensionId, themeId, ok := m.carpet(e.DimensionId, e.ThemeId)
+		if !ok || (!m.newDimensions[dimensionId] && !m.newThemes[themeId]) {
+			if err := m.tx.Delete(&e).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		result := m.tx.Model(&e).Updates(map[string]interface{}{
+			"product_id":   m.target.ID,
+			"dimension_id": dimensionId,
+			"theme_id":     themeId,
+		})
+		if err := result.Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeAttributes copy attributes of source that target has no value for
+func (m *productMerge) mergeAttributes() error {
+	var attributes []schema.ProductAttribute
+	if err := m.tx.Where("product_id = ?", m.target.ID).Order("id").Find(&attributes).Error; err != nil {
+		return err
+	}
+	var sourceAttributes []schema.ProductAttribute
+	if err := m.tx.Where("product_id = ?", m.source.ID).Order("id").Find(&sourceAttributes).Error; err != nil {
+		return err
+	}
+
+	valued := make(map[uint]bool, len(attributes))
+	for _, a := range attributes {
+		valued[a.AttributeId] = true
+	}
+	added := false
+	for _, a := range sourceAttributes {
+		if !valued[a.AttributeId] {
+			attributes = append(attributes, a)
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+
+	for i := range attributes {
+		attributes[i].Model = gorm.Model{}
+		attributes[i].ProductId = m.target.ID
+	}
+	return replaceAttributes(m.tx, m.target.ID, attributes)
+}
+
+// mergeTagsAndCategories add tags and categories of source to target
+func (m *productMerge) mergeTagsAndCategories() error {
+	if err := m.tx.Exec("INSERT INTO tbl_product_tag (product_id, tag_id) "+
+		"SELECT ?, tag_id FROM tbl_product_tag WHERE product_id = ? ON CONFLICT DO NOTHING", m.target.ID, m.source.ID).Error; err != nil {
+		return err
+	}
+	return m.tx.Exec("INSERT INTO tbl_product_category (product_id, category_id) "+
+		"SELECT ?, category_id FROM tbl_product_category WHERE product_id = ? ON CONFLICT DO NOTHING", m.target.ID, m.source.ID).Error
+}
+
+// moveAliases keep design code and aliases of source as aliases of target
+func (m *productMerge) moveAliases() error {
+	result := m.tx.Model(&schema.ProductAlias{}).
+		Where("company_id = ? AND product_id = ?", m.source.CompanyId, m.source.ID).
+		Update("product_id", m.target.ID)
+	if err := result.Error; err != nil {
+		return err
+	}
+	return addAlias(m.tx, m.source.CompanyId, m.target.ID, m.source.DesignCode)
+}
+
+// dimensionWithSize return id of dimension of `dimensions` with `size`, sizes are compared by normalize.Key
+func dimensionWithSize(dimensions []schema.Dimension, size string) (uint, bool) {
+	for _, d := range dimensions {
+		if normalize.Key(d.Size) == normalize.Key(size) {
+			return d.ID, true
+		}
+	}
+	return 0, false
+}
+
+// themeWithColor return id of theme of `themes` with `color`, colors are compared by normalize.Key
+func themeWithColor(themes []schema.Theme, color string) (uint, bool) {
+	for _, t := range themes {
+		if normalize.Key(t.Color) == normalize.Key(color) {
+			return t.ID, true
+		}
+	}
+	return 0, false
+}
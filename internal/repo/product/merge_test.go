@@ -0,0 +1,149 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestProductRepo_MergeProducts(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := CreateProduct1(pRepo, t)
+	source, err := pRepo.CreateProduct(model.Product{
+		CompanyId:  1,
+		DesignCode: "۱۰۵",
+		Colors:     []string{"آبی", "سبز"},
+		Sizes:      []string{"9", "12"},
+	})
+	require.Nil(t, err)
+
+	warehouse, err := pRepo.CreateWarehouse(model.Warehouse{CompanyId: 1, Name: "مرکزی"})
+	require.Nil(t, err)
+	receipt := func(p *schema.Product, size, color string, quantity int64) {
+		movement := model.StockMovement{CompanyId: 1, WarehouseId: warehouse.ID, ProductId: p.ID, Kind: model.MovementReceipt, Quantity: quantity}
+		movement.DimensionId, _ = dimensionWithSize(p.Dimensions, size)
+		movement.ThemeId, _ = themeWithColor(p.Themes, color)
+		_, err := pRepo.RecordStockMovement(movement)
+		require.Nil(t, err)
+	}
+	receipt(target, "9", "آبی", 2)
+	receipt(source, "9", "آبی", 5)
+	receipt(source, "12", "سبز", 3)
+
+	require.Nil(t, pRepo.AddTags(1, []uint{source.ID}, []string{"دستباف"}))
+
+	product, err := pRepo.MergeProducts(1, source.ID, target.ID)
+	require.Nil(t, err)
+	require.Equal(t, target.ID, product.ID)
+	require.Equal(t, 3, len(product.Dimensions))
+	require.Equal(t, 3, len(product.Themes))
+	require.Equal(t, int64(10), product.OnHand)
+	require.Equal(t, 1, len(product.Tags))
+
+	// Same size and color of both products are one carpet
+	levels, _, err := pRepo.GetStock(1, warehouse.ID, target.ID, model.Page{})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(levels))
+
+	// Source is deleted and its code finds target
+	_, err = pRepo.GetProductWithId(source.ID)
+	require.Equal(t, derror.StatusText(derror.ProductNotFound), derror.StatusText(err))
+	product, err = pRepo.GetProductWithDesignCode(1, "۱۰۵")
+	require.Nil(t, err)
+	require.Equal(t, target.ID, product.ID)
+
+	history, err := pRepo.GetProductHistory(1, target.ID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(history))
+	require.Equal(t, model.HistoryMerge, history[0].Kind)
+	require.Equal(t, source.ID, history[0].SourceProductId)
+
+	// Merged source and merge into itself
+	_, err = pRepo.MergeProducts(1, source.ID, target.ID)
+	require.Equal(t, derror.StatusText(derror.ProductNotFound), derror.StatusText(err))
+	_, err = pRepo.MergeProducts(1, target.ID, target.ID)
+	require.Equal(t, derror.StatusText(derror.InvalidProduct), derror.StatusText(err))
+}
+
+func TestPricesOverlap(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2022, 3, d, 0, 0, 0, 0, time.UTC) }
+	until := func(d int) *time.Time { to := day(d); return &to }
+
+	target := []schema.Price{{ValidFrom: day(1), ValidTo: until(10)}, {ValidFrom: day(20)}}
+
+	tests := []struct {
+		Name    string
+		Price   schema.Price
+		Overlap bool
+	}{
+		{Name: "Before", Price: schema.Price{ValidFrom: day(1).Add(-time.Hour), ValidTo: until(1)}, Overlap: false},
+		{Name: "Between", Price: schema.Price{ValidFrom: day(10), ValidTo: until(20)}, Overlap: false},
+		{Name: "Inside", Price: schema.Price{ValidFrom: day(3), ValidTo: until(5)}, Overlap: true},
+		{Name: "OpenEnded", Price: schema.Price{ValidFrom: day(15)}, Overlap: true},
+		{Name: "CrossStart", Price: schema.Price{ValidFrom: day(15), ValidTo: until(21)}, Overlap: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			require.Equal(t, test.Overlap, pricesOverlap(target, test.Price))
+		})
+	}
+	require.False(t, pricesOverlap(nil, schema.Price{ValidFrom: day(1)}))
+}
+
+func TestDimensionWithSize(t *testing.T) {
+	dimensions := []schema.Dimension{{Model: gorm.Model{ID: 3}, Size: "6"}, {Model: gorm.Model{ID: 4}, Size: "9X12"}}
+
+	id, found := dimensionWithSize(dimensions, "۶")
+	require.True(t, found)
+	require.Equal(t, uint(3), id)
+
+	id, found = dimensionWithSize(dimensions, "9x12")
+	require.True(t, found)
+	require.Equal(t, uint(4), id)
+
+	_, found = dimensionWithSize(dimensions, "12")
+	require.False(t, found)
+
+	themes := []schema.Theme{{Model: gorm.Model{ID: 5}, Color: "کرم"}}
+	id, found = themeWithColor(themes, "كرم")
+	require.True(t, found)
+	require.Equal(t, uint(5), id)
+}
+
+func TestProductRepo_MergeProducts_ReservationOfRemovedCarpet(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := CreateProduct1(pRepo, t)
+	source := CreateProduct2(pRepo, t)
+	warehouse, err := pRepo.CreateWarehouse(model.Warehouse{CompanyId: 1, Name: "مرکزی"})
+	require.Nil(t, err)
+
+	// An active reservation of a color of source that is removed afterwards
+	reservation := schema.Reservation{CompanyId: 1, WarehouseId: warehouse.ID, ProductId: source.ID,
+		DimensionId: source.Dimensions[0].ID, ThemeId: source.Themes[0].ID, Quantity: 1, ExpiresAt: time.Now().Add(time.Hour)}
+	require.Nil(t, pRepo.db.Create(&reservation).Error)
+	require.Nil(t, pRepo.db.Delete(&schema.Theme{}, source.Themes[0].ID).Error)
+
+	_, err = pRepo.MergeProducts(1, source.ID, target.ID)
+	require.Nil(t, err)
+
+	released := schema.Reservation{}
+	require.Nil(t, pRepo.db.First(&released, reservation.ID).Error)
+	require.NotNil(t, released.ReleasedAt)
+	require.Equal(t, model.ReleaseMerged, released.ReleaseReason)
+	require.Equal(t, source.ID, released.ProductId)
+}
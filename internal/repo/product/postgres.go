@@ -8,10 +8,12 @@ import (
 	"github.com/seed95/product-service/internal/repo"
 	"github.com/seed95/product-service/internal/repo/product/schema"
 	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/normalize"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
 	gormSchema "gorm.io/gorm/schema"
+	"sync"
 )
 
 type (
@@ -21,6 +23,8 @@ type (
 		theme     ThemeService
 		dimension DimensionService
 		logger    logger.Logger
+		// Companies whose carpet view is created
+		carpetViews sync.Map
 	}
 
 	Setting struct {
@@ -68,9 +72,84 @@ func (r *productRepo) connect() error {
 }
 
 func (r *productRepo) migration() error {
-	if err := r.db.AutoMigrate(&schema.Product{}, &schema.Dimension{}, &schema.Theme{}); err != nil {
+	if err := r.db.AutoMigrate(
+		&schema.Product{},
+		&schema.ProductAlias{},
+		&schema.ProductHistory{},
+		&schema.Dimension{},
+		&schema.Theme{},
+		&schema.StandardSize{},
+		&schema.AttributeDefinition{},
+		&schema.ProductAttribute{},
+		&schema.Category{},
+		&schema.Tag{},
+		&schema.CarpetExclusion{},
+		&schema.PriceList{},
+		&schema.Price{},
+		&schema.PriceAdjustment{},
+		&schema.Warehouse{},
+		&schema.Stock{},
+		&schema.StockMovement{},
+		&schema.Reservation{},
+	); err != nil {
 		return errors.New(fmt.Sprintf(derror.CreateProductRepoErrorFormat, err))
 	}
 
+	if err := normalizeLabels(r.db); err != nil {
+		return errors.New(fmt.Sprintf(derror.CreateProductRepoErrorFormat, err))
+	}
+
+	if err := r.refreshCarpetViews(); err != nil {
+		return errors.New(fmt.Sprintf(derror.CreateProductRepoErrorFormat, err))
+	}
+
+	return nil
+}
+
+// normalizeLabels store design codes, sizes and colors saved before normalization in normalized form.
+// a value whose normalized form is used by another row of its product (or company for a design code) is kept,
+// such sizes and colors still match their normalized form because they are compared by normalize.Key
+func normalizeLabels(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := normalizeColumn(tx, &schema.Product{}, "company_id", "design_code"); err != nil {
+			return err
+		}
+		if err := normalizeColumn(tx, &schema.Dimension{}, "product_id", "size"); err != nil {
+			return err
+		}
+		return normalizeColumn(tx, &schema.Theme{}, "product_id", "color")
+	})
+}
+
+// normalizeColumn normalize `column` of rows of `table`, values are unique per `scope` column.
+// removed rows are included because they keep their value in unique index
+func normalizeColumn(tx *gorm.DB, table interface{}, scope, column string) error {
+	var rows []struct {
+		Id    uint
+		Scope uint
+		Value string
+	}
+	if err := tx.Unscoped().Model(table).Select("id, " + scope + " AS scope, " + column + " AS value").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	type scopedValue struct {
+		scope uint
+		value string
+	}
+	count := make(map[scopedValue]int, len(rows))
+	for _, row := range rows {
+		count[scopedValue{row.Scope, normalize.String(row.Value)}]++
+	}
+
+	for _, row := range rows {
+		normalized := normalize.String(row.Value)
+		if normalized == row.Value || count[scopedValue{row.Scope, normalized}] != 1 {
+			continue
+		}
+		if err := tx.Unscoped().Model(table).Where("id = ?", row.Id).UpdateColumn(column, normalized).Error; err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -26,7 +26,7 @@ func NewProductRepoMock() (*productRepo, error) {
 		return nil, err
 	}
 
-	if err := mock.db.Exec("TRUNCATE tbl_theme,tbl_dimension,tbl_product;").Error; err != nil {
+	if err := mock.db.Exec("TRUNCATE tbl_theme,tbl_dimension,tbl_product,tbl_product_alias,tbl_product_history,tbl_standard_size,tbl_attribute_definition,tbl_product_attribute,tbl_category,tbl_product_category,tbl_tag,tbl_product_tag,tbl_carpet_exclusion,tbl_price_list,tbl_price,tbl_price_adjustment,tbl_warehouse,tbl_stock,tbl_stock_movement,tbl_reservation;").Error; err != nil {
 		return nil, err
 	}
 
@@ -1 +1,52 @@
 package product
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestProductRepo_NormalizeLabels(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	blue := p.Themes[1]
+	require.Equal(t, "آبی", blue.Color)
+
+	// A color stored before normalization
+	require.Nil(t, pRepo.db.Model(&blue).UpdateColumn("color", "آبي").Error)
+
+	// Edit with normalized color keeps the theme
+	edited, err := pRepo.EditProduct(model.Product{Id: p.ID, CompanyId: 1, DesignCode: p.DesignCode,
+		Colors: []string{"قرمز", "آبی"}, Sizes: []string{"6", "9"}})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(edited.Themes))
+	for _, theme := range edited.Themes {
+		require.Contains(t, []uint{p.Themes[0].ID, blue.ID}, theme.ID)
+	}
+
+	require.Nil(t, pRepo.db.Model(&p.Dimensions[0]).UpdateColumn("size", "۶").Error)
+	require.Nil(t, pRepo.db.Model(&schema.Product{}).Where("id = ?", p.ID).UpdateColumn("design_code", "١٠٥").Error)
+	// "١٠٦" collides with design code of another product
+	p2 := CreateProduct2(pRepo, t)
+	p3 := CreateProduct3(pRepo, t)
+	require.Nil(t, pRepo.db.Model(&schema.Product{}).Where("id = ?", p3.ID).UpdateColumn("design_code", "١٠٦").Error)
+
+	require.Nil(t, normalizeLabels(pRepo.db))
+
+	product, err := pRepo.GetProductWithId(p.ID)
+	require.Nil(t, err)
+	require.Equal(t, "105", product.DesignCode)
+	require.ElementsMatch(t, []string{"6", "9"}, schema.GetSizes(product.Dimensions))
+	require.ElementsMatch(t, []string{"قرمز", "آبی"}, schema.GetColors(product.Themes))
+
+	product, err = pRepo.GetProductWithId(p3.ID)
+	require.Nil(t, err)
+	require.Equal(t, "١٠٦", product.DesignCode)
+	require.NotEqual(t, p2.ID, p3.ID)
+}
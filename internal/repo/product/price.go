@@ -0,0 +1,556 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+	"time"
+)
+
+// CreatePriceList add a price list for `list.CompanyId`, name of price lists of a company is unique
+func (r *productRepo) CreatePriceList(list model.PriceList) (schemaList *schema.PriceList, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("model_price_list", fmt.Sprintf("%+v", list)),
+			keyval.String("schema_price_list", fmt.Sprintf("%+v", schemaList)),
+		}
+		logger.LogReqRes(r.logger, "price.CreatePriceList", err, commonKeyVal...)
+	}()
+
+	schemaList = schema.PriceListModelToSchema(list)
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		result := tx.Model(&schema.PriceList{}).Where("company_id = ? AND name = ?", list.CompanyId, list.Name).Count(&count)
+		if err := result.Error; err != nil {
+			return err
+		}
+		if count != 0 {
+			return derror.New(derror.InvalidPriceList, "duplicate name "+list.Name)
+		}
+
+		return tx.Create(schemaList).Error
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return schemaList, nil
+}
+
+// GetPriceLists return all price lists of `companyId`
+func (r *productRepo) GetPriceLists(companyId uint) (lists []schema.PriceList, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("lists", fmt.Sprintf("%+v", lists)),
+		}
+		logger.LogReqRes(r.logger, "price.GetPriceLists", err, commonKeyVal...)
+	}()
+
+	tx := r.db.Order("id ASC").Where("company_id = ?", companyId).Find(&lists)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	return lists, nil
+}
+
+// DeletePriceList soft delete a price list with its prices and adjustments
+func (r *productRepo) DeletePriceList(companyId, priceListId uint) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("price_list_id", fmt.Sprintf("%v", priceListId)),
+		}
+		logger.LogReqRes(r.logger, "price.DeletePriceList", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := getPriceList(tx, companyId, priceListId); err != nil {
+			return err
+		}
+
+		if err := tx.Where("price_list_id = ?", priceListId).Delete(&schema.Price{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("price_list_id = ?", priceListId).Delete(&schema.PriceAdjustment{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&schema.PriceList{Model: gorm.Model{ID: priceListId}}).Error
+	})
+
+	return derror.Wrap(err)
+}
+
+// AddPrice add a price of a carpet, a product size or a product per square meter to a price list of `companyId`.
+// if `price.DimensionId` is zero dimension is found with `price.Size`.
+// an open ended price closes earlier open prices of same carpet or size in the list.
+// a discontinued product and an excluded carpet don't accept new prices
+func (r *productRepo) AddPrice(companyId uint, price model.Price) (schemaPrice *schema.Price, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("model_price", fmt.Sprintf("%+v", price)),
+			keyval.String("schema_price", fmt.Sprintf("%+v", schemaPrice)),
+		}
+		logger.LogReqRes(r.logger, "price.AddPrice", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := getPriceList(tx, companyId, price.PriceListId); err != nil {
+			return err
+		}
+
+		if err := checkProductAcceptsEntries(tx, companyId, price.ProductId); err != nil {
+			return err
+		}
+
+		if price.PerSquareMeter {
+			price.DimensionId, price.ThemeId = 0, 0
+		} else {
+			dimension := &schema.Dimension{}
+			query := tx.Where("product_id = ?", price.ProductId)
+			if price.DimensionId != 0 {
+				query = query.Where("id = ?", price.DimensionId)
+			} else {
+				query = query.Where("size = ?", price.Size)
+			}
+			if err := query.First(dimension).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return derror.DimensionNotFound
+				}
+				return err
+			}
+			price.DimensionId = dimension.ID
+		}
+
+		if price.ThemeId != 0 {
+			if err := tx.Where("product_id = ?", price.ProductId).First(&schema.Theme{}, price.ThemeId).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return derror.ThemeNotFound
+				}
+				return err
+			}
+
+			if err := checkNotExcluded(tx, price.ProductId, price.DimensionId, price.ThemeId); err != nil {
+				return err
+			}
+		}
+
+		schemaPrice = schema.PriceModelToSchema(price)
+
+		if schemaPrice.ValidTo == nil {
+			open := tx.Model(&schema.Price{}).
+				Where("price_list_id = ? AND product_id = ? AND dimension_id = ?", price.PriceListId, price.ProductId, price.DimensionId).
+				Where("valid_from < ? AND (valid_to IS NULL OR valid_to > ?)", price.ValidFrom, price.ValidFrom)
+			if schemaPrice.ThemeId == nil {
+				open = open.Where("theme_id IS NULL")
+			} else {
+				open = open.Where("theme_id = ?", price.ThemeId)
+			}
+			if err := open.Update("valid_to", price.ValidFrom).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(schemaPrice).Error
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return schemaPrice, nil
+}
+
+// GetPrices return prices of a price list of `companyId`, if `productId` isn't zero only prices of the product
+func (r *productRepo) GetPrices(companyId, priceListId, productId uint) (prices []schema.Price, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("price_list_id", fmt.Sprintf("%v", priceListId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("prices", fmt.Sprintf("%+v", prices)),
+		}
+		logger.LogReqRes(r.logger, "price.GetPrices", err, commonKeyVal...)
+	}()
+
+	if _, err := getPriceList(r.db, companyId, priceListId); err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	query := r.db.Where("price_list_id = ?", priceListId)
+	if productId != 0 {
+		query = query.Where("product_id = ?", productId)
+	}
+
+	tx := query.Order("product_id ASC").Order("dimension_id ASC").Order("theme_id ASC NULLS FIRST").Order("valid_from ASC").Find(&prices)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	return prices, nil
+}
+
+// DeletePrice soft delete a price of a price list of `companyId`
+func (r *productRepo) DeletePrice(companyId, priceId uint) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("price_id", fmt.Sprintf("%v", priceId)),
+		}
+		logger.LogReqRes(r.logger, "price.DeletePrice", err, commonKeyVal...)
+	}()
+
+	lists := r.db.Model(&schema.PriceList{}).Select("id").Where("company_id = ?", companyId)
+	tx := r.db.Where("price_list_id IN (?)", lists).Delete(&schema.Price{Model: gorm.Model{ID: priceId}})
+	if err := tx.Error; err != nil {
+		return derror.New(derror.InternalServer, err.Error())
+	} else if tx.RowsAffected < 1 {
+		return derror.PriceNotFound
+	}
+
+	return nil
+}
+
+// ResolvePrice return price of `carpet` in a price list of `companyId` that is effective at `at`,
+// without a price of the carpet or its size, price is calculated with area of the size
+func (r *productRepo) ResolvePrice(companyId, priceListId uint, carpet model.Carpet, at time.Time) (effective *model.EffectivePrice, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("price_list_id", fmt.Sprintf("%v", priceListId)),
+			keyval.String("carpet", fmt.Sprintf("%+v", carpet)),
+			keyval.String("at", at.String()),
+			keyval.String("effective", fmt.Sprintf("%+v", effective)),
+		}
+		logger.LogReqRes(r.logger, "price.ResolvePrice", err, commonKeyVal...)
+	}()
+
+	list, err := getPriceList(r.db, companyId, priceListId)
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	var schemaPrices []schema.Price
+	tx := r.db.Where("price_list_id = ? AND product_id = ? AND dimension_id = ?", priceListId, carpet.ProductId, carpet.DimensionId).
+		Where("(theme_id IS NULL OR theme_id = ?)", carpet.ThemeId).
+		Find(&schemaPrices)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	prices := make([]model.Price, len(schemaPrices))
+	for i := range schemaPrices {
+		prices[i] = schema.PriceToModel(&schemaPrices[i])
+	}
+
+	price, found := model.ResolvePrice(prices, carpet.DimensionId, carpet.ThemeId, at)
+	if !found {
+		// Calculate with area of dimension
+		price, err := perSquareMeterPrice(r.db, priceListId, carpet.ProductId, at)
+		if err != nil {
+			return nil, derror.Wrap(err)
+		}
+
+		size, err := dimensionSize(r.db, carpet.DimensionId)
+		if err != nil {
+			return nil, derror.Wrap(err)
+		}
+
+		effective, err = areaPrice(r.db, list, carpet, size, price)
+		if err != nil {
+			return nil, derror.Wrap(err)
+		}
+		return effective, nil
+	}
+
+	return &model.EffectivePrice{
+		Carpet:    carpet,
+		PriceList: schema.PriceListToModel(list),
+		Price:     price,
+		Source:    price.Source(),
+	}, nil
+}
+
+// AddPriceAdjustment add an adjustment of area prices to a price list of `companyId`,
+// a product of adjustment should be in same company
+func (r *productRepo) AddPriceAdjustment(companyId uint, adjustment model.PriceAdjustment) (schemaAdjustment *schema.PriceAdjustment, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("model_adjustment", fmt.Sprintf("%+v", adjustment)),
+			keyval.String("schema_adjustment", fmt.Sprintf("%+v", schemaAdjustment)),
+		}
+		logger.LogReqRes(r.logger, "price.AddPriceAdjustment", err, commonKeyVal...)
+	}()
+
+	schemaAdjustment = schema.PriceAdjustmentModelToSchema(adjustment)
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := getPriceList(tx, companyId, adjustment.PriceListId); err != nil {
+			return err
+		}
+
+		if adjustment.ProductId != 0 {
+			if err := checkCompanyProducts(tx, companyId, []uint{adjustment.ProductId}); err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(schemaAdjustment).Error
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return schemaAdjustment, nil
+}
+
+// GetPriceAdjustments return adjustments of a price list of `companyId`
+func (r *productRepo) GetPriceAdjustments(companyId, priceListId uint) (adjustments []schema.PriceAdjustment, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("price_list_id", fmt.Sprintf("%v", priceListId)),
+			keyval.String("adjustments", fmt.Sprintf("%+v", adjustments)),
+		}
+		logger.LogReqRes(r.logger, "price.GetPriceAdjustments", err, commonKeyVal...)
+	}()
+
+	if _, err := getPriceList(r.db, companyId, priceListId); err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	tx := r.db.Order("id ASC").Where("price_list_id = ?", priceListId).Find(&adjustments)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	return adjustments, nil
+}
+
+// DeletePriceAdjustment soft delete an adjustment of a price list of `companyId`
+func (r *productRepo) DeletePriceAdjustment(companyId, adjustmentId uint) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("adjustment_id", fmt.Sprintf("%v", adjustmentId)),
+		}
+		logger.LogReqRes(r.logger, "price.DeletePriceAdjustment", err, commonKeyVal...)
+	}()
+
+	lists := r.db.Model(&schema.PriceList{}).Select("id").Where("company_id = ?", companyId)
+	tx := r.db.Where("price_list_id IN (?)", lists).Delete(&schema.PriceAdjustment{Model: gorm.Model{ID: adjustmentId}})
+	if err := tx.Error; err != nil {
+		return derror.New(derror.InternalServer, err.Error())
+	} else if tx.RowsAffected < 1 {
+		return derror.PriceAdjustmentNotFound
+	}
+
+	return nil
+}
+
+// QuotePrice return area price of a product of `companyId` in a price list at `at`.
+// a size label of the product is measured with its catalog size, other labels are parsed
+func (r *productRepo) QuotePrice(companyId uint, quote model.PriceQuote, at time.Time) (effective *model.EffectivePrice, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("quote", fmt.Sprintf("%+v", quote)),
+			keyval.String("at", at.String()),
+			keyval.String("effective", fmt.Sprintf("%+v", effective)),
+		}
+		logger.LogReqRes(r.logger, "price.QuotePrice", err, commonKeyVal...)
+	}()
+
+	list, err := getPriceList(r.db, companyId, quote.PriceListId)
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	product := &schema.Product{}
+	if err := r.db.Where("company_id = ?", companyId).First(product, quote.ProductId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, derror.ProductNotFound
+		}
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	if quote.Size != "" && quote.Color != "" {
+		if err := checkLabelsNotExcluded(r.db, product.ID, quote.Size, quote.Color); err != nil {
+			return nil, derror.Wrap(err)
+		}
+	}
+
+	width, length := quote.Width, quote.Length
+	if width == 0 || length == 0 {
+		var row struct {
+			Width  uint
+			Length uint
+		}
+		tx := r.db.Table("tbl_dimension d").
+			Select("s.width, s.length").
+			Joins("JOIN tbl_standard_size s ON s.id = d.standard_size_id AND s.deleted_at IS NULL").
+			Where("d.product_id = ? AND d.size = ? AND d.deleted_at IS NULL", quote.ProductId, quote.Size).
+			Scan(&row)
+		if err := tx.Error; err != nil {
+			return nil, derror.New(derror.InternalServer, err.Error())
+		}
+		width, length = row.Width, row.Length
+	}
+
+	size, err := model.SizeOf(quote.Size, width, length, quote.Shape)
+	if err != nil {
+		return nil, derror.New(derror.InvalidDimension, quote.Size)
+	}
+
+	label := quote.Size
+	if label == "" {
+		label = fmt.Sprintf("%vx%v", size.Width, size.Length)
+	}
+
+	carpet := model.Carpet{
+		CompanyId:  companyId,
+		ProductId:  product.ID,
+		DesignCode: product.DesignCode,
+		Dimension:  label,
+		Color:      quote.Color,
+	}
+
+	price, err := perSquareMeterPrice(r.db, quote.PriceListId, carpet.ProductId, at)
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	effective, err = areaPrice(r.db, list, carpet, size, price)
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+	return effective, nil
+}
+
+// perSquareMeterPrice return price per square meter of `productId` in a price list that is effective at `at`
+func perSquareMeterPrice(db *gorm.DB, priceListId, productId uint, at time.Time) (model.Price, error) {
+	var schemaPrices []schema.Price
+	tx := db.Where("price_list_id = ? AND product_id = ? AND dimension_id = 0", priceListId, productId).Find(&schemaPrices)
+	if err := tx.Error; err != nil {
+		return model.Price{}, err
+	}
+
+	prices := make([]model.Price, len(schemaPrices))
+	for i := range schemaPrices {
+		prices[i] = schema.PriceToModel(&schemaPrices[i])
+	}
+
+	price, found := model.ResolvePrice(prices, 0, 0, at)
+	if !found {
+		return model.Price{}, derror.PriceNotFound
+	}
+	return price, nil
+}
+
+// areaPrice return price of `carpet` of `size` calculated with `price` per square meter and adjustments of price list
+func areaPrice(db *gorm.DB, list *schema.PriceList, carpet model.Carpet, size model.CarpetSize, price model.Price) (*model.EffectivePrice, error) {
+	var schemaAdjustments []schema.PriceAdjustment
+	tx := db.Where("price_list_id = ? AND product_id IN ?", list.ID, []uint{0, carpet.ProductId}).Find(&schemaAdjustments)
+	if err := tx.Error; err != nil {
+		return nil, err
+	}
+
+	adjustments := make([]model.PriceAdjustment, len(schemaAdjustments))
+	for i := range schemaAdjustments {
+		adjustments[i] = schema.PriceAdjustmentToModel(&schemaAdjustments[i])
+	}
+
+	breakdown := model.CalculateAreaPrice(price.Amount, size, carpet.Color, adjustments)
+	price.Amount = breakdown.Total
+
+	return &model.EffectivePrice{
+		Carpet:    carpet,
+		PriceList: schema.PriceListToModel(list),
+		Price:     price,
+		Source:    price.Source(),
+		Breakdown: &breakdown,
+	}, nil
+}
+
+// dimensionSize return measure of a dimension with its catalog size or its label
+func dimensionSize(db *gorm.DB, dimensionId uint) (model.CarpetSize, error) {
+	var row struct {
+		Size   string
+		Width  uint
+		Length uint
+	}
+	tx := db.Table("tbl_dimension d").
+		Select("d.size, s.width, s.length").
+		Joins("LEFT JOIN tbl_standard_size s ON s.id = d.standard_size_id AND s.deleted_at IS NULL").
+		Where("d.id = ?", dimensionId).
+		Scan(&row)
+	if err := tx.Error; err != nil {
+		return model.CarpetSize{}, err
+	}
+
+	size, err := model.SizeOf(row.Size, row.Width, row.Length, "")
+	if err != nil {
+		return model.CarpetSize{}, derror.New(derror.InvalidDimension, row.Size)
+	}
+	return size, nil
+}
+
+func getPriceList(db *gorm.DB, companyId, priceListId uint) (*schema.PriceList, error) {
+	list := &schema.PriceList{}
+	if err := db.Where("company_id = ?", companyId).First(list, priceListId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, derror.New(derror.PriceListNotFound, fmt.Sprintf("price list id %v", priceListId))
+		}
+		return nil, err
+	}
+	return list, nil
+}
+
+// checkProductAcceptsEntries return derror.ProductNotFound if `productId` not found for `companyId`
+// and derror.ProductDiscontinued if product doesn't accept new entries
+func checkProductAcceptsEntries(db *gorm.DB, companyId, productId uint) error {
+	product := &schema.Product{}
+	if err := db.Select("id", "status").Where("company_id = ?", companyId).First(product, productId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return derror.New(derror.ProductNotFound, fmt.Sprintf("product id %v", productId))
+		}
+		return err
+	}
+
+	if !model.StatusAcceptsEntries(product.Status) {
+		return derror.New(derror.ProductDiscontinued, fmt.Sprintf("product id %v", productId))
+	}
+
+	return nil
+}
+
+// deleteDetachedPrices soft delete prices of `productId` whose dimension or theme is deleted,
+// prices per square meter are kept
+func deleteDetachedPrices(tx *gorm.DB, productId uint) error {
+	dimensions := tx.Model(&schema.Dimension{}).Select("id").Where("product_id = ?", productId)
+	themes := tx.Model(&schema.Theme{}).Select("id").Where("product_id = ?", productId)
+	return tx.Where("product_id = ?", productId).
+		Where("((dimension_id <> 0 AND dimension_id NOT IN (?)) OR theme_id NOT IN (?))", dimensions, themes).
+		Delete(&schema.Price{}).Error
+}
@@ -0,0 +1,190 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestProductRepo_CreatePriceList(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := model.PriceList{CompanyId: 1, Name: "خرده فروشی", Kind: model.PriceListRetail, Currency: "IRR"}
+	_, err = pRepo.CreatePriceList(list)
+	require.Nil(t, err)
+
+	_, err = pRepo.CreatePriceList(list)
+	require.Equal(t, derror.StatusText(derror.InvalidPriceList), derror.StatusText(err))
+
+	// Same name for another company
+	list.CompanyId = 2
+	_, err = pRepo.CreatePriceList(list)
+	require.Nil(t, err)
+
+	lists, err := pRepo.GetPriceLists(1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(lists))
+}
+
+func TestProductRepo_ResolvePrice(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	list, err := pRepo.CreatePriceList(model.PriceList{CompanyId: 1, Name: "عمده", Kind: model.PriceListWholesale, Currency: "IRR"})
+	require.Nil(t, err)
+
+	jan := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+
+	carpet := model.Carpet{ProductId: p.ID, DimensionId: p.Dimensions[0].ID, ThemeId: p.Themes[0].ID}
+
+	_, err = pRepo.ResolvePrice(1, list.ID, carpet, feb)
+	require.Equal(t, derror.StatusText(derror.PriceNotFound), derror.StatusText(err))
+
+	// Price of size with its label
+	_, err = pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p.ID, Size: p.Dimensions[0].Size, Amount: 1000, ValidFrom: jan})
+	require.Nil(t, err)
+
+	effective, err := pRepo.ResolvePrice(1, list.ID, carpet, feb)
+	require.Nil(t, err)
+	require.Equal(t, int64(1000), effective.Price.Amount)
+	require.Equal(t, model.PriceSourceSize, effective.Source)
+	require.Equal(t, "IRR", effective.PriceList.Currency)
+
+	// Newer price of size closes the older one
+	_, err = pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p.ID, DimensionId: p.Dimensions[0].ID, Amount: 1200, ValidFrom: feb})
+	require.Nil(t, err)
+
+	prices, err := pRepo.GetPrices(1, list.ID, p.ID)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(prices))
+	require.NotNil(t, prices[0].ValidTo)
+	require.True(t, feb.Equal(*prices[0].ValidTo))
+
+	effective, err = pRepo.ResolvePrice(1, list.ID, carpet, jan)
+	require.Nil(t, err)
+	require.Equal(t, int64(1000), effective.Price.Amount)
+
+	// Price of carpet wins over price of size
+	_, err = pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p.ID, DimensionId: p.Dimensions[0].ID, ThemeId: p.Themes[0].ID, Amount: 1500, ValidFrom: jan})
+	require.Nil(t, err)
+
+	effective, err = pRepo.ResolvePrice(1, list.ID, carpet, feb)
+	require.Nil(t, err)
+	require.Equal(t, int64(1500), effective.Price.Amount)
+	require.Equal(t, model.PriceSourceCarpet, effective.Source)
+
+	t.Run("price list of another company", func(t *testing.T) {
+		_, err := pRepo.ResolvePrice(2, list.ID, carpet, feb)
+		require.Equal(t, derror.StatusText(derror.PriceListNotFound), derror.StatusText(err))
+	})
+
+	t.Run("size of another product", func(t *testing.T) {
+		_, err := pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p.ID, Size: "100", Amount: 1000, ValidFrom: jan})
+		require.Equal(t, derror.StatusText(derror.DimensionNotFound), derror.StatusText(err))
+	})
+}
+
+func TestProductRepo_AddPrice_Discontinued(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	list, err := pRepo.CreatePriceList(model.PriceList{CompanyId: 1, Name: "نماینده", Kind: model.PriceListDealer, Currency: "IRR"})
+	require.Nil(t, err)
+
+	_, err = pRepo.ChangeProductStatus(1, p.ID, model.StatusDiscontinued)
+	require.Nil(t, err)
+
+	_, err = pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p.ID, DimensionId: p.Dimensions[0].ID, Amount: 1000, ValidFrom: time.Now()})
+	require.Equal(t, derror.StatusText(derror.ProductDiscontinued), derror.StatusText(err))
+}
+
+func TestProductRepo_EditProduct_Prices(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	list, err := pRepo.CreatePriceList(model.PriceList{CompanyId: 1, Name: "خرده فروشی", Kind: model.PriceListRetail, Currency: "IRR"})
+	require.Nil(t, err)
+
+	for _, d := range p.Dimensions {
+		_, err = pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p.ID, DimensionId: d.ID, Amount: 1000, ValidFrom: time.Now()})
+		require.Nil(t, err)
+	}
+	_, err = pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p.ID, DimensionId: p.Dimensions[0].ID, ThemeId: p.Themes[1].ID, Amount: 1500, ValidFrom: time.Now()})
+	require.Nil(t, err)
+
+	// Remove first size and second color
+	_, err = pRepo.EditProduct(model.Product{
+		Id:          p.ID,
+		CompanyId:   p.CompanyId,
+		DesignCode:  p.DesignCode,
+		Colors:      []string{p.Themes[0].Color},
+		Sizes:       []string{p.Dimensions[1].Size},
+		Description: p.Description,
+	})
+	require.Nil(t, err)
+
+	prices, err := pRepo.GetPrices(1, list.ID, p.ID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(prices))
+	require.Equal(t, p.Dimensions[1].ID, prices[0].DimensionId)
+}
+
+func TestProductRepo_ResolvePrice_Area(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	list, err := pRepo.CreatePriceList(model.PriceList{CompanyId: 1, Name: "خرده فروشی", Kind: model.PriceListRetail, Currency: "IRR"})
+	require.Nil(t, err)
+
+	carpet := model.Carpet{ProductId: p.ID, DimensionId: p.Dimensions[0].ID, ThemeId: p.Themes[0].ID, Color: p.Themes[0].Color}
+
+	_, err = pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p.ID, PerSquareMeter: true, Amount: 1000000, ValidFrom: time.Now().Add(-time.Hour)})
+	require.Nil(t, err)
+	_, err = pRepo.AddPriceAdjustment(1, model.PriceAdjustment{PriceListId: list.ID, Kind: model.AdjustmentColor, Value: p.Themes[0].Color, Percent: 10})
+	require.Nil(t, err)
+
+	// Size "6" is six square meter
+	effective, err := pRepo.ResolvePrice(1, list.ID, carpet, time.Now())
+	require.Nil(t, err)
+	require.Equal(t, model.PriceSourceArea, effective.Source)
+	require.NotNil(t, effective.Breakdown)
+	require.Equal(t, int64(6000000), effective.Breakdown.Base)
+	require.Equal(t, int64(6600000), effective.Price.Amount)
+
+	quote, err := pRepo.QuotePrice(1, model.PriceQuote{PriceListId: list.ID, ProductId: p.ID, Width: 80, Length: 300, Color: "کرم"}, time.Now())
+	require.Nil(t, err)
+	require.Equal(t, model.ShapeRunner, quote.Breakdown.Size.Shape)
+	require.Equal(t, int64(2400000), quote.Price.Amount)
+
+	// Price of size wins over area price
+	_, err = pRepo.AddPrice(1, model.Price{PriceListId: list.ID, ProductId: p.ID, DimensionId: p.Dimensions[0].ID, Amount: 5000000, ValidFrom: time.Now().Add(-time.Hour)})
+	require.Nil(t, err)
+
+	effective, err = pRepo.ResolvePrice(1, list.ID, carpet, time.Now())
+	require.Nil(t, err)
+	require.Equal(t, model.PriceSourceSize, effective.Source)
+	require.Nil(t, effective.Breakdown)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/seed95/product-service/pkg/logger/keyval"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"time"
 )
 
 // CreateProduct create a product with relations(dimension, theme)
@@ -23,8 +24,13 @@ func (r *productRepo) CreateProduct(product model.Product) (schemaProduct *schem
 		logger.LogReqRes(r.logger, "product.CreateProduct", err, commonKeyVal...)
 	}()
 
-	schemaProduct = schema.ProductModelToSchema(product)
-	if err := r.db.Create(schemaProduct).Error; err != nil {
+	schemaProduct, err = createProduct(r.db, product)
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	// First product of a company creates its carpet view
+	if _, err := r.carpetView(schemaProduct.CompanyId); err != nil {
 		return nil, derror.New(derror.InternalServer, err.Error())
 	}
 
@@ -44,12 +50,19 @@ func (r *productRepo) GetProductWithId(productId uint) (product *schema.Product,
 	product = &schema.Product{
 		Model: gorm.Model{ID: productId},
 	}
-	if err := r.db.Preload(clause.Associations).First(product).Error; err != nil {
+	if err := r.db.Preload(clause.Associations).Preload("Attributes.Attribute").First(product).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, derror.ProductNotFound
 		}
 		return nil, derror.New(derror.InternalServer, err.Error())
 	}
+
+	onHand, available, err := productsStock(r.db, product.ID)
+	if err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	product.OnHand, product.Available = onHand[product.ID], available[product.ID]
+
 	return product, nil
 }
 
@@ -83,40 +96,48 @@ func (r *productRepo) EditProduct(product model.Product) (schemaProduct *schema.
 		logger.LogReqRes(r.logger, "product.EditProduct", err, commonKeyVal...)
 	}()
 
-	schemaProduct = schema.ProductModelToSchema(product)
-
 	// Check product exist
-	if _, err = r.GetProductWithId(schemaProduct.ID); err != nil {
+	originalProduct, err := r.GetProductWithId(product.Id)
+	if err != nil {
 		return nil, err
 	}
 
-	err = r.db.Transaction(func(tx *gorm.DB) error {
-		result := tx.Model(schema.Product{Model: gorm.Model{ID: schemaProduct.ID}}).
-			Updates(schema.Product{DesignCode: schemaProduct.DesignCode, Description: schemaProduct.Description})
-		if err := result.Error; err != nil {
-			return err
+	return r.editProduct(r.db, originalProduct, product)
+}
+
+// ChangeProductStatus move product `productId` of `companyId` to `status` if transition from its current status is allowed
+func (r *productRepo) ChangeProductStatus(companyId, productId uint, status string) (product *schema.Product, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("status", status),
+			keyval.String("product", fmt.Sprintf("%+v", product)),
 		}
+		logger.LogReqRes(r.logger, "product.ChangeProductStatus", err, commonKeyVal...)
+	}()
 
-		themes, err := r.theme.EditThemes(tx, schemaProduct.ID, schemaProduct.Themes)
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		locked, err := lockProduct(tx, companyId, productId)
 		if err != nil {
 			return err
 		}
-		schemaProduct.Themes = themes
+		product = locked
 
-		dimensions, err := r.dimension.EditDimensions(tx, schemaProduct.ID, schemaProduct.Dimensions)
-		if err != nil {
-			return err
+		if !model.StatusTransitionIsValid(product.Status, status) {
+			return derror.New(derror.StatusTransitionNotAllowed, fmt.Sprintf("%v to %v", product.Status, status))
 		}
-		schemaProduct.Dimensions = dimensions
 
-		return nil
+		product.SetStatus(status, time.Now())
+		return tx.Model(product).Select("status", "published_at", "archived_at", "discontinued_at").Updates(product).Error
 	})
 
 	if err != nil {
-		return nil, derror.New(derror.InternalServer, err.Error())
+		return nil, derror.Wrap(err)
 	}
 
-	return schemaProduct, nil
+	return r.GetProductWithId(productId)
 }
 
 func (r *productRepo) GetAllProducts(companyId uint) (products []schema.Product, err error) {
@@ -129,12 +150,286 @@ func (r *productRepo) GetAllProducts(companyId uint) (products []schema.Product,
 		logger.LogReqRes(r.logger, "product.GetAllProducts", err, commonKeyVal...)
 	}()
 
-	tx := r.db.Model(&schema.Product{}).Preload(clause.Associations).Where("company_id = ?", companyId).Find(&products)
+	tx := r.db.Model(&schema.Product{}).Preload(clause.Associations).Preload("Attributes.Attribute").Where("company_id = ?", companyId).Find(&products)
 	if tx.RowsAffected < 1 {
 		return nil, derror.ProductNotFound
 	} else if err := tx.Error; err != nil {
 		return nil, derror.New(derror.InternalServer, err.Error())
 	}
 
+	if err := setProductsStock(r.db, products); err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
 	return products, nil
 }
+
+// SearchProducts return products of `filter.CompanyId` that match all filters
+func (r *productRepo) SearchProducts(filter model.ProductFilter) (products []schema.Product, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("filter", fmt.Sprintf("%+v", filter)),
+			keyval.String("products", fmt.Sprintf("%+v", products)),
+		}
+		logger.LogReqRes(r.logger, "product.SearchProducts", err, commonKeyVal...)
+	}()
+
+	db, err := filterProducts(r.db, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := db.Preload(clause.Associations).Preload("Attributes.Attribute").Order("tbl_product.id ASC").Find(&products)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	if err := setProductsStock(r.db, products); err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	return products, nil
+}
+
+// lockProduct lock row of `productId` of `companyId` in `tx` until end of transaction,
+// edits of relations of a product are serialized with it
+func lockProduct(tx *gorm.DB, companyId, productId uint) (*schema.Product, error) {
+	product := &schema.Product{}
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("company_id = ?", companyId).First(product, productId)
+	if err := result.Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, derror.New(derror.ProductNotFound, fmt.Sprintf("product id %v", productId))
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+// filterProducts return a query on products of `filter.CompanyId` that match all filters
+func filterProducts(db *gorm.DB, filter model.ProductFilter) (*gorm.DB, error) {
+	query := db.Model(&schema.Product{}).Where("tbl_product.company_id = ?", filter.CompanyId)
+
+	if filter.CategoryId != 0 {
+		query = query.Where("tbl_product.id IN (SELECT pc.product_id FROM tbl_product_category pc "+
+			"WHERE pc.category_id IN ("+categoryTreeQuery+"))", filter.CategoryId, filter.CompanyId)
+	}
+
+	if len(filter.Statuses) != 0 {
+		query = query.Where("tbl_product.status IN ?", filter.Statuses)
+	}
+
+	if filter.HideDrafts {
+		query = query.Where("tbl_product.status <> ?", model.StatusDraft)
+	}
+
+	if len(filter.ProductIds) != 0 {
+		query = query.Where("tbl_product.id IN ?", filter.ProductIds)
+	}
+
+	if tags := model.NormalizeTags(filter.Tags); len(tags) != 0 {
+		tagged := "SELECT pt.product_id FROM tbl_product_tag pt JOIN tbl_tag t ON t.id = pt.tag_id " +
+			"WHERE t.company_id = ? AND t.name IN ?"
+		if filter.TagMatch == model.TagMatchAll {
+			query = query.Where("tbl_product.id IN ("+tagged+" GROUP BY pt.product_id HAVING COUNT(DISTINCT t.id) = ?)",
+				filter.CompanyId, tags, len(tags))
+		} else {
+			query = query.Where("tbl_product.id IN ("+tagged+")", filter.CompanyId, tags)
+		}
+	}
+
+	if len(filter.Attributes) == 0 {
+		return query, nil
+	}
+
+	definitions, err := getAttributeDefinitions(db, filter.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]schema.AttributeDefinition, len(definitions))
+	for _, d := range definitions {
+		byName[d.Name] = d
+	}
+
+	for _, f := range filter.Attributes {
+		d, ok := byName[f.Name]
+		if !ok {
+			return nil, derror.New(derror.AttributeNotFound, f.Name)
+		}
+
+		modelDefinition := schema.AttributeDefinitionToModel(&d)
+		if !f.OperatorIsValid(modelDefinition.Type) {
+			return nil, derror.New(derror.InvalidAttribute, fmt.Sprintf("operator %v on %v", f.Operator, f.Name))
+		}
+
+		value, err := modelDefinition.Normalize(f.Value)
+		if err != nil {
+			return nil, derror.New(derror.InvalidAttribute, err.Error())
+		}
+
+		column := "pa.value"
+		if modelDefinition.Type == model.AttributeNumber {
+			column = "pa.value::numeric"
+		}
+
+		// A product without a value of the attribute matches no operator, ne included
+		query = query.Where(fmt.Sprintf("EXISTS (SELECT 1 FROM tbl_product_attribute pa "+
+			"WHERE pa.product_id = tbl_product.id AND pa.deleted_at IS NULL AND pa.attribute_id = ? AND %s %s ?)",
+			column, filterOperators[f.Operator]), d.ID, value)
+	}
+
+	return query, nil
+}
+
+// createProduct create a product with relations in `db`
+func createProduct(db *gorm.DB, product model.Product) (*schema.Product, error) {
+	// Code of a deleted product or an alias of another product isn't reused
+	if err := checkDesignCodeFree(db, product.CompanyId, product.DesignCode); err != nil {
+		return nil, err
+	}
+
+	schemaProduct := schema.ProductModelToSchema(product)
+	if schemaProduct.Status == "" {
+		schemaProduct.Status = model.StatusPublished
+	}
+	schemaProduct.SetStatus(schemaProduct.Status, time.Now())
+
+	// Link catalog sizes
+	dimensions, err := withStandardSizes(db, product.CompanyId, product.Id, schemaProduct.Dimensions, product.StandardSizes)
+	if err != nil {
+		return nil, err
+	}
+	schemaProduct.Dimensions = dimensions
+
+	// Validate attributes
+	attributes, err := withAttributes(db, product.CompanyId, product.Id, product.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	schemaProduct.Attributes = attributes
+
+	// Check categories
+	categories, err := withCategories(db, product.CompanyId, product.Categories)
+	if err != nil {
+		return nil, err
+	}
+	schemaProduct.Categories = categories
+
+	if err := db.Omit("Attributes.Attribute").Create(schemaProduct).Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	return schemaProduct, nil
+}
+
+// editProduct replace fields and relations of `originalProduct` with `product` in `db`,
+// tags and status of product are not changed
+func (r *productRepo) editProduct(db *gorm.DB, originalProduct *schema.Product, product model.Product) (*schema.Product, error) {
+	schemaProduct := schema.ProductModelToSchema(product)
+
+	// Link catalog sizes
+	dimensions, err := withStandardSizes(db, originalProduct.CompanyId, schemaProduct.ID, schemaProduct.Dimensions, product.StandardSizes)
+	if err != nil {
+		return nil, err
+	}
+	schemaProduct.Dimensions = dimensions
+
+	// Validate attributes
+	attributes, err := withAttributes(db, originalProduct.CompanyId, schemaProduct.ID, product.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check categories
+	categories, err := withCategories(db, originalProduct.CompanyId, product.Categories)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// A new design code is checked like RenameDesignCode and old code is kept as an alias
+		if schemaProduct.DesignCode != originalProduct.DesignCode {
+			if err := takeAlias(tx, originalProduct.CompanyId, schemaProduct.ID, schemaProduct.DesignCode); err != nil {
+				return err
+			}
+			if err := checkDesignCodeFree(tx, originalProduct.CompanyId, schemaProduct.DesignCode); err != nil {
+				return err
+			}
+			if err := addAlias(tx, originalProduct.CompanyId, schemaProduct.ID, originalProduct.DesignCode); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(schema.Product{Model: gorm.Model{ID: schemaProduct.ID}}).
+			Updates(schema.Product{DesignCode: schemaProduct.DesignCode, Description: schemaProduct.Description})
+		if err := result.Error; err != nil {
+			return err
+		}
+
+		// A size or color with pieces on hand can't be removed
+		removedDimensions, removedThemes := removedCarpets(originalProduct, schemaProduct)
+		if err := checkRemovedCarpetsNotInStock(tx, schemaProduct.ID, removedDimensions, removedThemes); err != nil {
+			return err
+		}
+
+		themes, err := r.theme.EditThemes(tx, schemaProduct.ID, schemaProduct.Themes)
+		if err != nil {
+			return err
+		}
+		schemaProduct.Themes = themes
+
+		dimensions, err := r.dimension.EditDimensions(tx, schemaProduct.ID, schemaProduct.Dimensions)
+		if err != nil {
+			return err
+		}
+		schemaProduct.Dimensions = dimensions
+
+		// Carpets of deleted sizes and colors lose their prices and exclusions
+		if err := deleteDetachedPrices(tx, schemaProduct.ID); err != nil {
+			return err
+		}
+		if err := deleteDetachedExclusions(tx, schemaProduct.ID); err != nil {
+			return err
+		}
+
+		if err := replaceAttributes(tx, schemaProduct.ID, attributes); err != nil {
+			return err
+		}
+		schemaProduct.Attributes = attributes
+
+		if err := replaceCategories(tx, schemaProduct.ID, categories); err != nil {
+			return err
+		}
+		schemaProduct.Categories = categories
+
+		// Tags and status are edited with their own operations
+		schemaProduct.Tags = originalProduct.Tags
+		schemaProduct.Status = originalProduct.Status
+		schemaProduct.PublishedAt = originalProduct.PublishedAt
+		schemaProduct.ArchivedAt = originalProduct.ArchivedAt
+		schemaProduct.DiscontinuedAt = originalProduct.DiscontinuedAt
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return schemaProduct, nil
+}
+
+// removedCarpets return dimensions and themes of `original` whose size or color isn't in `edited`
+func removedCarpets(original, edited *schema.Product) (dimensions []schema.Dimension, themes []schema.Theme) {
+	for _, d := range original.Dimensions {
+		if !dimensionsHaveSize(edited.Dimensions, d.Size) {
+			dimensions = append(dimensions, d)
+		}
+	}
+	for _, t := range original.Themes {
+		if !themesHaveColor(edited.Themes, t.Color) {
+			themes = append(themes, t)
+		}
+	}
+	return dimensions, themes
+}
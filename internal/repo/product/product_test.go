@@ -615,7 +615,7 @@ func TestProductRepo_EditProduct_DuplicateDesignCode(t *testing.T) {
 
 	p1.DesignCode = gotP2.DesignCode
 	editedProduct, err := pRepo.EditProduct(p1)
-	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.ProductExists), derror.StatusText(err))
 	require.Nil(t, editedProduct)
 }
 
@@ -684,3 +684,42 @@ func checkEqualProduct(t *testing.T, expectedProduct, gotProduct *schema.Product
 	require.Equal(t, len(expectedProduct.Dimensions), len(gotProduct.Dimensions), "dimension")
 	require.Equal(t, len(expectedProduct.Themes), len(gotProduct.Themes), "theme")
 }
+
+func TestProductRepo_ChangeProductStatus(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := pRepo.CreateProduct(model.Product{CompanyId: 1, DesignCode: "105", Colors: []string{"قرمز"}, Sizes: []string{"6"}, Status: model.StatusDraft})
+	require.Nil(t, err)
+	require.Equal(t, model.StatusDraft, p.Status)
+	require.Nil(t, p.PublishedAt)
+
+	t.Run("not allowed", func(t *testing.T) {
+		product, err := pRepo.ChangeProductStatus(1, p.ID, model.StatusDiscontinued)
+		require.Equal(t, derror.StatusText(derror.StatusTransitionNotAllowed), derror.StatusText(err))
+		require.Nil(t, product)
+	})
+
+	product, err := pRepo.ChangeProductStatus(1, p.ID, model.StatusPublished)
+	require.Nil(t, err)
+	require.Equal(t, model.StatusPublished, product.Status)
+	require.NotNil(t, product.PublishedAt)
+
+	product, err = pRepo.ChangeProductStatus(1, p.ID, model.StatusDiscontinued)
+	require.Nil(t, err)
+	require.NotNil(t, product.DiscontinuedAt)
+
+	products, err := pRepo.SearchProducts(model.ProductFilter{CompanyId: 1, Statuses: []string{model.StatusDiscontinued}})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(products))
+
+	_, err = pRepo.ChangeProductStatus(1, p.ID+1000, model.StatusPublished)
+	require.Equal(t, derror.StatusText(derror.ProductNotFound), derror.StatusText(err))
+
+	// Product of another company
+	_, err = pRepo.ChangeProductStatus(2, p.ID, model.StatusArchived)
+	require.Equal(t, derror.StatusText(derror.ProductNotFound), derror.StatusText(err))
+}
@@ -0,0 +1,225 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// ReserveStock hold pieces of a carpet in a warehouse until `reservation.ExpiresAt`,
+// reserved quantity can't be more than available (on hand and not reserved) quantity
+func (r *productRepo) ReserveStock(reservation model.Reservation) (schemaReservation *schema.Reservation, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("model_reservation", fmt.Sprintf("%+v", reservation)),
+			keyval.String("schema_reservation", fmt.Sprintf("%+v", schemaReservation)),
+		}
+		logger.LogReqRes(r.logger, "reservation.ReserveStock", err, commonKeyVal...)
+	}()
+
+	if err := reservation.Validate(); err != nil {
+		return nil, derror.New(derror.InvalidReservation, err.Error())
+	}
+
+	schemaReservation = schema.ReservationModelToSchema(reservation)
+
+	// A transaction that failed on a dropped view is rolled back and run again
+	err = r.withCarpetView(reservation.CompanyId, func(view string) error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			if _, err := getWarehouse(tx, reservation.CompanyId, reservation.WarehouseId); err != nil {
+				return err
+			}
+
+			if err := checkCarpet(tx, view, reservation.ProductId, reservation.DimensionId, reservation.ThemeId); err != nil {
+				return err
+			}
+
+			stock, err := lockStock(tx, reservation.WarehouseId, reservation.ProductId, reservation.DimensionId, reservation.ThemeId)
+			if err != nil {
+				return err
+			}
+
+			reserved, err := reservedQuantity(tx, reservation.WarehouseId, reservation.ProductId, reservation.DimensionId, reservation.ThemeId, time.Now())
+			if err != nil {
+				return err
+			}
+
+			if available := model.Available(stock.OnHand, reserved); reservation.Quantity > available {
+				return derror.New(derror.InsufficientStock,
+					fmt.Sprintf("%v available in warehouse id %v, %v requested", available, reservation.WarehouseId, reservation.Quantity))
+			}
+
+			return tx.Create(schemaReservation).Error
+		})
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return schemaReservation, nil
+}
+
+// ReleaseReservation cancel an active reservation of `companyId`, its pieces become available
+func (r *productRepo) ReleaseReservation(companyId, reservationId uint) (reservation *schema.Reservation, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("reservation_id", fmt.Sprintf("%v", reservationId)),
+			keyval.String("reservation", fmt.Sprintf("%+v", reservation)),
+		}
+		logger.LogReqRes(r.logger, "reservation.ReleaseReservation", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		reservation, err = lockActiveReservation(tx, companyId, reservationId)
+		if err != nil {
+			return err
+		}
+
+		return releaseReservation(tx, reservation, model.ReleaseCancelled)
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return reservation, nil
+}
+
+// GetReservations return a page of reservations that match `filter`, newest first, and total number of matched reservations
+func (r *productRepo) GetReservations(filter model.ReservationFilter, page model.Page) (reservations []schema.Reservation, total int64, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("filter", fmt.Sprintf("%+v", filter)),
+			keyval.String("page", fmt.Sprintf("%+v", page)),
+			keyval.String("total", fmt.Sprintf("%v", total)),
+		}
+		logger.LogReqRes(r.logger, "reservation.GetReservations", err, commonKeyVal...)
+	}()
+
+	page = page.Normalize()
+	now := time.Now()
+
+	query := func() *gorm.DB {
+		query := r.db.Model(&schema.Reservation{})
+		if filter.ActiveOnly {
+			query = activeReservations(r.db, now)
+		}
+		query = query.Where("company_id = ?", filter.CompanyId)
+		if filter.WarehouseId != 0 {
+			query = query.Where("warehouse_id = ?", filter.WarehouseId)
+		}
+		if filter.ProductId != 0 {
+			query = query.Where("product_id = ?", filter.ProductId)
+		}
+		if filter.Holder != "" {
+			query = query.Where("holder = ?", filter.Holder)
+		}
+		return query
+	}
+
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, derror.New(derror.InternalServer, err.Error())
+	}
+
+	tx := query().Order("id DESC").Offset(page.Offset()).Limit(page.Size).Find(&reservations)
+	if err := tx.Error; err != nil {
+		return nil, 0, derror.New(derror.InternalServer, err.Error())
+	}
+
+	return reservations, total, nil
+}
+
+// ReleaseExpiredReservations release reservations of every company that are expired at `at` and return their number
+func (r *productRepo) ReleaseExpiredReservations(at time.Time) (released int64, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("at", at.String()),
+			keyval.String("released", fmt.Sprintf("%v", released)),
+		}
+		logger.LogReqRes(r.logger, "reservation.ReleaseExpiredReservations", err, commonKeyVal...)
+	}()
+
+	tx := r.db.Model(&schema.Reservation{}).
+		Where("released_at IS NULL AND expires_at <= ?", at).
+		Updates(map[string]interface{}{"released_at": at, "release_reason": model.ReleaseExpired})
+	if err := tx.Error; err != nil {
+		return 0, derror.New(derror.InternalServer, err.Error())
+	}
+
+	return tx.RowsAffected, nil
+}
+
+// activeReservations return a query on reservations that are not released and not expired at `at`
+func activeReservations(db *gorm.DB, at time.Time) *gorm.DB {
+	return db.Model(&schema.Reservation{}).Where("released_at IS NULL AND expires_at > ?", at)
+}
+
+// reservedQuantity return pieces of a carpet in `warehouseId` that are held by active reservations at `at`
+func reservedQuantity(db *gorm.DB, warehouseId, productId, dimensionId, themeId uint, at time.Time) (int64, error) {
+	var reserved int64
+	result := activeReservations(db, at).Select("COALESCE(SUM(quantity), 0)").
+		Where("warehouse_id = ? AND product_id = ? AND dimension_id = ? AND theme_id = ?", warehouseId, productId, dimensionId, themeId).
+		Scan(&reserved)
+	if err := result.Error; err != nil {
+		return 0, err
+	}
+	return reserved, nil
+}
+
+// lockActiveReservation return reservation of `companyId` locked for update,
+// derror.ReservationNotActive if it is released or expired
+func lockActiveReservation(tx *gorm.DB, companyId, reservationId uint) (*schema.Reservation, error) {
+	reservation := &schema.Reservation{}
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("company_id = ?", companyId).First(reservation, reservationId)
+	if err := result.Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, derror.New(derror.ReservationNotFound, fmt.Sprintf("reservation id %v", reservationId))
+		}
+		return nil, err
+	}
+
+	if !schema.ReservationToModel(reservation).ActiveAt(time.Now()) {
+		return nil, derror.New(derror.ReservationNotActive, fmt.Sprintf("reservation id %v", reservationId))
+	}
+
+	return reservation, nil
+}
+
+func releaseReservation(tx *gorm.DB, reservation *schema.Reservation, reason string) error {
+	now := time.Now()
+	reservation.ReleasedAt = &now
+	reservation.ReleaseReason = reason
+	return tx.Model(reservation).Updates(map[string]interface{}{"released_at": now, "release_reason": reason}).Error
+}
+
+// fulfillReservation take pieces of a sale from its reservation, reservation is released when all its pieces are sold
+func fulfillReservation(tx *gorm.DB, movement model.StockMovement) error {
+	reservation, err := lockActiveReservation(tx, movement.CompanyId, movement.ReservationId)
+	if err != nil {
+		return err
+	}
+
+	if reservation.WarehouseId != movement.WarehouseId || reservation.ProductId != movement.ProductId ||
+		reservation.DimensionId != movement.DimensionId || reservation.ThemeId != movement.ThemeId {
+		return derror.New(derror.InvalidStockMovement, "reservation is for another carpet or warehouse")
+	}
+
+	if movement.Quantity < reservation.Quantity {
+		return tx.Model(reservation).Update("quantity", reservation.Quantity-movement.Quantity).Error
+	}
+
+	return releaseReservation(tx, reservation, model.ReleaseFulfilled)
+}
@@ -0,0 +1,128 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestProductRepo_ReserveStock(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	warehouse, err := pRepo.CreateWarehouse(model.Warehouse{CompanyId: 1, Name: "مرکزی"})
+	require.Nil(t, err)
+
+	movement := model.StockMovement{
+		CompanyId:   1,
+		WarehouseId: warehouse.ID,
+		ProductId:   p.ID,
+		DimensionId: p.Dimensions[0].ID,
+		ThemeId:     p.Themes[0].ID,
+		Kind:        model.MovementReceipt,
+		Quantity:    5,
+	}
+	_, err = pRepo.RecordStockMovement(movement)
+	require.Nil(t, err)
+
+	reservation := model.Reservation{
+		CompanyId:   1,
+		WarehouseId: warehouse.ID,
+		ProductId:   p.ID,
+		DimensionId: p.Dimensions[0].ID,
+		ThemeId:     p.Themes[0].ID,
+		Quantity:    3,
+		Holder:      "فروشگاه شیراز",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	held, err := pRepo.ReserveStock(reservation)
+	require.Nil(t, err)
+
+	// Only 2 pieces are available
+	_, err = pRepo.ReserveStock(reservation)
+	require.Equal(t, derror.StatusText(derror.InsufficientStock), derror.StatusText(err))
+
+	movement.Kind = model.MovementSale
+	_, err = pRepo.RecordStockMovement(movement)
+	require.Equal(t, derror.StatusText(derror.InsufficientStock), derror.StatusText(err))
+
+	levels, _, err := pRepo.GetStock(1, warehouse.ID, p.ID, model.Page{})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(levels))
+	require.Equal(t, int64(5), levels[0].OnHand)
+	require.Equal(t, int64(3), levels[0].Reserved)
+	require.Equal(t, int64(2), levels[0].Available)
+
+	product, err := pRepo.GetProductWithId(p.ID)
+	require.Nil(t, err)
+	require.Equal(t, int64(5), product.OnHand)
+	require.Equal(t, int64(2), product.Available)
+
+	// Sale of reserved pieces fulfills reservation
+	movement.Quantity, movement.ReservationId = 3, held.ID
+	_, err = pRepo.RecordStockMovement(movement)
+	require.Nil(t, err)
+
+	_, err = pRepo.ReleaseReservation(1, held.ID)
+	require.Equal(t, derror.StatusText(derror.ReservationNotActive), derror.StatusText(err))
+
+	reservations, total, err := pRepo.GetReservations(model.ReservationFilter{CompanyId: 1, ActiveOnly: true}, model.Page{})
+	require.Nil(t, err)
+	require.Equal(t, int64(0), total)
+	require.Equal(t, 0, len(reservations))
+}
+
+func TestProductRepo_ReleaseExpiredReservations(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	warehouse, err := pRepo.CreateWarehouse(model.Warehouse{CompanyId: 1, Name: "مرکزی"})
+	require.Nil(t, err)
+
+	_, err = pRepo.RecordStockMovement(model.StockMovement{
+		CompanyId:   1,
+		WarehouseId: warehouse.ID,
+		ProductId:   p.ID,
+		DimensionId: p.Dimensions[0].ID,
+		ThemeId:     p.Themes[0].ID,
+		Kind:        model.MovementReceipt,
+		Quantity:    2,
+	})
+	require.Nil(t, err)
+
+	expiresAt := time.Now().Add(time.Hour)
+	held, err := pRepo.ReserveStock(model.Reservation{
+		CompanyId:   1,
+		WarehouseId: warehouse.ID,
+		ProductId:   p.ID,
+		DimensionId: p.Dimensions[0].ID,
+		ThemeId:     p.Themes[0].ID,
+		Quantity:    2,
+		Holder:      "فروشگاه شیراز",
+		ExpiresAt:   expiresAt,
+	})
+	require.Nil(t, err)
+
+	released, err := pRepo.ReleaseExpiredReservations(time.Now())
+	require.Nil(t, err)
+	require.Equal(t, int64(0), released)
+
+	released, err = pRepo.ReleaseExpiredReservations(expiresAt)
+	require.Nil(t, err)
+	require.Equal(t, int64(1), released)
+
+	reservations, _, err := pRepo.GetReservations(model.ReservationFilter{CompanyId: 1}, model.Page{})
+	require.Nil(t, err)
+	require.Equal(t, held.ID, reservations[0].ID)
+	require.Equal(t, model.ReleaseExpired, reservations[0].ReleaseReason)
+}
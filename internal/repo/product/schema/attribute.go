@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/model"
+	"gorm.io/gorm"
+)
+
+type (
+	AttributeDefinition struct {
+		gorm.Model
+		CompanyId uint   `gorm:"uniqueIndex:attribute_definition_unique_id"`
+		Name      string `gorm:"uniqueIndex:attribute_definition_unique_id"`
+		Type      string
+		Unit      string
+		Required  bool
+		Options   Strings `gorm:"type:text"`
+	}
+
+	ProductAttribute struct {
+		gorm.Model
+		ProductId   uint `gorm:"uniqueIndex:product_attribute_unique_id"`
+		AttributeId uint `gorm:"uniqueIndex:product_attribute_unique_id"`
+		Attribute   AttributeDefinition
+		Value       string // Normalized value, see model.AttributeDefinition.Normalize
+	}
+)
+
+func AttributeDefinitionModelToSchema(d model.AttributeDefinition) *AttributeDefinition {
+	return &AttributeDefinition{
+		Model:     gorm.Model{ID: d.Id},
+		CompanyId: d.CompanyId,
+		Name:      d.Name,
+		Type:      d.Type,
+		Unit:      d.Unit,
+		Required:  d.Required,
+		Options:   d.Options,
+	}
+}
+
+func AttributeDefinitionToModel(d *AttributeDefinition) model.AttributeDefinition {
+	return model.AttributeDefinition{
+		Id:        d.ID,
+		CompanyId: d.CompanyId,
+		Name:      d.Name,
+		Type:      d.Type,
+		Unit:      d.Unit,
+		Required:  d.Required,
+		Options:   d.Options,
+	}
+}
+
+func (d AttributeDefinition) String() string {
+	return fmt.Sprintf("ID: %v, CompanyId: %v, Name: %v, Type: %v, Unit: %v, Required: %v, Options: %v",
+		d.ID, d.CompanyId, d.Name, d.Type, d.Unit, d.Required, []string(d.Options))
+}
+
+func (a ProductAttribute) String() string {
+	return fmt.Sprintf("ID: %v, ProductId: %v, AttributeId: %v, Value: %v", a.ID, a.ProductId, a.AttributeId, a.Value)
+}
+
+// GetAttributes return attribute values keyed by attribute name, `attributes` should be loaded with definitions
+func GetAttributes(attributes []ProductAttribute) map[string]string {
+	result := make(map[string]string, len(attributes))
+	for _, a := range attributes {
+		result[a.Attribute.Name] = a.Value
+	}
+	return result
+}
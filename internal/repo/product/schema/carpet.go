@@ -1,6 +1,10 @@
 package schema
 
-import "github.com/seed95/product-service/internal/model"
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/model"
+	"time"
+)
 
 type (
 	Carpet struct {
@@ -12,6 +16,16 @@ type (
 		Size        string
 		Color       string
 	}
+
+	// CarpetExclusion remove a dimension and theme of a product from carpets, it is deleted when carpet is included again
+	CarpetExclusion struct {
+		ID          uint `gorm:"primarykey"`
+		ProductId   uint `gorm:"uniqueIndex:carpet_exclusion_unique_id"`
+		DimensionId uint `gorm:"uniqueIndex:carpet_exclusion_unique_id"`
+		ThemeId     uint `gorm:"uniqueIndex:carpet_exclusion_unique_id"`
+		Reason      string
+		CreatedAt   time.Time
+	}
 )
 
 func CarpetToModel(c *Carpet, companyId uint) model.Carpet {
@@ -26,3 +40,17 @@ func CarpetToModel(c *Carpet, companyId uint) model.Carpet {
 		Color:       c.Color,
 	}
 }
+
+func CarpetExclusionModelToSchema(e model.CarpetExclusion) *CarpetExclusion {
+	return &CarpetExclusion{
+		ID:          e.Id,
+		ProductId:   e.ProductId,
+		DimensionId: e.DimensionId,
+		ThemeId:     e.ThemeId,
+		Reason:      e.Reason,
+	}
+}
+
+func (e CarpetExclusion) String() string {
+	return fmt.Sprintf("ID: %v, ProductId: %v, DimensionId: %v, ThemeId: %v, Reason: %v", e.ID, e.ProductId, e.DimensionId, e.ThemeId, e.Reason)
+}
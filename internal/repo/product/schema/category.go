@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/model"
+	"gorm.io/gorm"
+)
+
+type (
+	Category struct {
+		gorm.Model
+		CompanyId uint  `gorm:"index"`
+		ParentId  *uint `gorm:"index"`
+		Name      string
+		Kind      string
+	}
+)
+
+func CategoryModelToSchema(c model.Category) *Category {
+	return &Category{
+		Model:     gorm.Model{ID: c.Id},
+		CompanyId: c.CompanyId,
+		ParentId:  c.ParentId,
+		Name:      c.Name,
+		Kind:      c.Kind,
+	}
+}
+
+func (c Category) String() string {
+	parentId := "nil"
+	if c.ParentId != nil {
+		parentId = fmt.Sprintf("%v", *c.ParentId)
+	}
+	return fmt.Sprintf("ID: %v, CompanyId: %v, ParentId: %v, Name: %v, Kind: %v", c.ID, c.CompanyId, parentId, c.Name, c.Kind)
+}
+
+func GetCategoryIds(categories []Category) []uint {
+	result := make([]uint, len(categories))
+	for i, c := range categories {
+		result[i] = c.ID
+	}
+	return result
+}
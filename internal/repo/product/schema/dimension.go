@@ -10,6 +10,8 @@ type (
 		gorm.Model
 		ProductId uint   `gorm:"uniqueIndex:dimension_unique_id"`
 		Size      string `gorm:"uniqueIndex:dimension_unique_id"`
+		// StandardSizeId is the catalog entry this dimension references, nil for a free size
+		StandardSizeId *uint `gorm:"index"`
 	}
 )
 
@@ -24,3 +26,14 @@ func GetSizes(dimensions []Dimension) []string {
 	}
 	return result
 }
+
+// GetStandardSizeIds return catalog size ids that `dimensions` reference
+func GetStandardSizeIds(dimensions []Dimension) []uint {
+	result := make([]uint, 0, len(dimensions))
+	for _, d := range dimensions {
+		if d.StandardSizeId != nil {
+			result = append(result, *d.StandardSizeId)
+		}
+	}
+	return result
+}
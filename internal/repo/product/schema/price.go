@@ -0,0 +1,140 @@
+package schema
+
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type (
+	PriceList struct {
+		gorm.Model
+		CompanyId uint `gorm:"index"`
+		Name      string
+		Kind      string
+		Currency  string
+	}
+
+	// Price is amount of a carpet, nil ThemeId is price of every color of the dimension
+	// and zero DimensionId is price per square meter of the product
+	Price struct {
+		gorm.Model
+		PriceListId uint `gorm:"index"`
+		ProductId   uint `gorm:"index"`
+		DimensionId uint
+		ThemeId     *uint
+		Amount      int64
+		ValidFrom   time.Time
+		ValidTo     *time.Time
+	}
+
+	// PriceAdjustment is a percent of area prices of a price list, zero ProductId applies to every product
+	PriceAdjustment struct {
+		gorm.Model
+		PriceListId uint `gorm:"index"`
+		ProductId   uint
+		Kind        string
+		Value       string
+		Percent     float64
+	}
+)
+
+func PriceListModelToSchema(l model.PriceList) *PriceList {
+	return &PriceList{
+		Model:     gorm.Model{ID: l.Id},
+		CompanyId: l.CompanyId,
+		Name:      l.Name,
+		Kind:      l.Kind,
+		Currency:  l.Currency,
+	}
+}
+
+func PriceListToModel(l *PriceList) model.PriceList {
+	return model.PriceList{
+		Id:        l.ID,
+		CompanyId: l.CompanyId,
+		Name:      l.Name,
+		Kind:      l.Kind,
+		Currency:  l.Currency,
+	}
+}
+
+func (l PriceList) String() string {
+	return fmt.Sprintf("ID: %v, CompanyId: %v, Name: %v, Kind: %v, Currency: %v", l.ID, l.CompanyId, l.Name, l.Kind, l.Currency)
+}
+
+func PriceModelToSchema(p model.Price) *Price {
+	var themeId *uint
+	if p.ThemeId != 0 {
+		id := p.ThemeId
+		themeId = &id
+	}
+	return &Price{
+		Model:       gorm.Model{ID: p.Id},
+		PriceListId: p.PriceListId,
+		ProductId:   p.ProductId,
+		DimensionId: p.DimensionId,
+		ThemeId:     themeId,
+		Amount:      p.Amount,
+		ValidFrom:   p.ValidFrom,
+		ValidTo:     p.ValidTo,
+	}
+}
+
+func PriceToModel(p *Price) model.Price {
+	var themeId uint
+	if p.ThemeId != nil {
+		themeId = *p.ThemeId
+	}
+	return model.Price{
+		Id:             p.ID,
+		PriceListId:    p.PriceListId,
+		ProductId:      p.ProductId,
+		DimensionId:    p.DimensionId,
+		ThemeId:        themeId,
+		PerSquareMeter: p.DimensionId == 0,
+		Amount:         p.Amount,
+		ValidFrom:      p.ValidFrom,
+		ValidTo:        p.ValidTo,
+	}
+}
+
+func (p Price) String() string {
+	themeId, validTo := "nil", "nil"
+	if p.ThemeId != nil {
+		themeId = fmt.Sprintf("%v", *p.ThemeId)
+	}
+	if p.ValidTo != nil {
+		validTo = p.ValidTo.String()
+	}
+	return fmt.Sprintf("ID: %v, PriceListId: %v, ProductId: %v, DimensionId: %v, ThemeId: %v, Amount: %v, ValidFrom: %v, ValidTo: %v",
+		p.ID, p.PriceListId, p.ProductId, p.DimensionId, themeId, p.Amount, p.ValidFrom, validTo)
+}
+
+func PriceAdjustmentModelToSchema(a model.PriceAdjustment) *PriceAdjustment {
+	return &PriceAdjustment{
+		Model:       gorm.Model{ID: a.Id},
+		PriceListId: a.PriceListId,
+		ProductId:   a.ProductId,
+		Kind:        a.Kind,
+		Value:       a.Value,
+		Percent:     a.Percent,
+	}
+}
+
+func PriceAdjustmentToModel(a *PriceAdjustment) model.PriceAdjustment {
+	return model.PriceAdjustment{
+		Id:          a.ID,
+		PriceListId: a.PriceListId,
+		ProductId:   a.ProductId,
+		Kind:        a.Kind,
+		Value:       a.Value,
+		Percent:     a.Percent,
+	}
+}
+
+func (a PriceAdjustment) String() string {
+	return fmt.Sprintf("ID: %v, PriceListId: %v, ProductId: %v, Kind: %v, Value: %v, Percent: %v",
+		a.ID, a.PriceListId, a.ProductId, a.Kind, a.Value, a.Percent)
+}
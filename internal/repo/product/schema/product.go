@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/seed95/product-service/internal/model"
 	"gorm.io/gorm"
+	"time"
 )
 
 type (
@@ -12,8 +13,36 @@ type (
 		CompanyId   uint   `gorm:"uniqueIndex:product_unique_id"`
 		DesignCode  string `gorm:"uniqueIndex:product_unique_id"`
 		Description string
-		Dimensions  []Dimension
-		Themes      []Theme
+		Status      string `gorm:"index;default:published"`
+		// Last time product moved to each status
+		PublishedAt    *time.Time
+		ArchivedAt     *time.Time
+		DiscontinuedAt *time.Time
+		Dimensions     []Dimension
+		Themes         []Theme
+		Attributes     []ProductAttribute
+		Categories     []Category `gorm:"many2many:product_category;"`
+		Tags           []Tag      `gorm:"many2many:product_tag;"`
+		OnHand         int64      `gorm:"-"` // Pieces of all carpets in all warehouses, read only
+		Available      int64      `gorm:"-"` // Pieces of all carpets in all warehouses that are not reserved, read only
+	}
+
+	// ProductAlias is an old design code of a product, a lookup by it resolves to the product
+	ProductAlias struct {
+		gorm.Model
+		CompanyId  uint   `gorm:"uniqueIndex:product_alias_unique_id"`
+		DesignCode string `gorm:"uniqueIndex:product_alias_unique_id"`
+		ProductId  uint   `gorm:"index"`
+	}
+
+	// ProductHistory is an append only record of a change of a product, a merge records product it folded in
+	ProductHistory struct {
+		gorm.Model
+		CompanyId       uint `gorm:"index"`
+		ProductId       uint `gorm:"index"`
+		Kind            string
+		SourceProductId uint
+		Note            string
 	}
 )
 
@@ -23,6 +52,7 @@ func ProductModelToSchema(p model.Product) *Product {
 		CompanyId:   p.CompanyId,
 		DesignCode:  p.DesignCode,
 		Description: p.Description,
+		Status:      p.Status,
 	}
 
 	for _, d := range p.Sizes {
@@ -54,6 +84,28 @@ func (p Product) String() string {
 		theme = append(theme, t.Color)
 	}
 
-	return fmt.Sprintf("ID: %v,\t CompanyId: %v,\t DesignCode: %v,\t Description: %v,\t Sizes: %v,\t Theme: %v,\t",
-		p.ID, p.CompanyId, p.DesignCode, p.Description, dimension, theme)
+	return fmt.Sprintf("ID: %v,\t CompanyId: %v,\t DesignCode: %v,\t Description: %v,\t Status: %v,\t Sizes: %v,\t Theme: %v,\t",
+		p.ID, p.CompanyId, p.DesignCode, p.Description, p.Status, dimension, theme)
+}
+
+// SetStatus change status of product and set timestamp of new status to `at`
+func (p *Product) SetStatus(status string, at time.Time) {
+	p.Status = status
+	switch status {
+	case model.StatusPublished:
+		p.PublishedAt = &at
+	case model.StatusArchived:
+		p.ArchivedAt = &at
+	case model.StatusDiscontinued:
+		p.DiscontinuedAt = &at
+	}
+}
+
+func (a ProductAlias) String() string {
+	return fmt.Sprintf("ID: %v, CompanyId: %v, DesignCode: %v, ProductId: %v", a.ID, a.CompanyId, a.DesignCode, a.ProductId)
+}
+
+func (h ProductHistory) String() string {
+	return fmt.Sprintf("ID: %v, CompanyId: %v, ProductId: %v, Kind: %v, SourceProductId: %v, Note: %v",
+		h.ID, h.CompanyId, h.ProductId, h.Kind, h.SourceProductId, h.Note)
 }
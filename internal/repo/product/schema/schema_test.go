@@ -109,3 +109,49 @@ func TestGetColors(t *testing.T) {
 		})
 	}
 }
+
+func TestGetStandardSizeIds(t *testing.T) {
+	id1, id2 := uint(3), uint(7)
+	dimensions := []Dimension{{Size: "6", StandardSizeId: &id1}, {Size: "2x3"}, {Size: "9", StandardSizeId: &id2}}
+	require.Equal(t, []uint{3, 7}, GetStandardSizeIds(dimensions))
+	require.Equal(t, []uint{}, GetStandardSizeIds(nil))
+}
+
+func TestStrings(t *testing.T) {
+	value, err := Strings{"ماشینی", "دستباف"}.Value()
+	require.Nil(t, err)
+
+	var got Strings
+	require.Nil(t, got.Scan(value))
+	require.Equal(t, Strings{"ماشینی", "دستباف"}, got)
+
+	value, err = Strings(nil).Value()
+	require.Nil(t, err)
+	require.Equal(t, "[]", value)
+
+	require.Nil(t, got.Scan(nil))
+	require.Nil(t, got)
+	require.NotNil(t, got.Scan(12))
+}
+
+func TestGetAttributes(t *testing.T) {
+	attributes := []ProductAttribute{
+		{Attribute: AttributeDefinition{Name: "material"}, Value: "پشم"},
+		{Attribute: AttributeDefinition{Name: "shaneh"}, Value: "50"},
+	}
+	require.Equal(t, map[string]string{"material": "پشم", "shaneh": "50"}, GetAttributes(attributes))
+	require.Equal(t, map[string]string{}, GetAttributes(nil))
+}
+
+func TestPriceModelToSchema(t *testing.T) {
+	sizePrice := model.Price{Id: 1, PriceListId: 2, ProductId: 3, DimensionId: 4, Amount: 1000}
+	gotSizePrice := PriceModelToSchema(sizePrice)
+	require.Nil(t, gotSizePrice.ThemeId)
+	require.Equal(t, sizePrice, PriceToModel(gotSizePrice))
+
+	carpetPrice := model.Price{Id: 1, PriceListId: 2, ProductId: 3, DimensionId: 4, ThemeId: 5, Amount: 1000}
+	gotCarpetPrice := PriceModelToSchema(carpetPrice)
+	require.NotNil(t, gotCarpetPrice.ThemeId)
+	require.Equal(t, uint(5), *gotCarpetPrice.ThemeId)
+	require.Equal(t, carpetPrice, PriceToModel(gotCarpetPrice))
+}
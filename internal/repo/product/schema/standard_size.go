@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/model"
+	"gorm.io/gorm"
+)
+
+type (
+	// StandardSize is a company wide catalog entry that dimensions can reference
+	StandardSize struct {
+		gorm.Model
+		CompanyId uint   `gorm:"uniqueIndex:standard_size_unique_id"`
+		Label     string `gorm:"uniqueIndex:standard_size_unique_id"`
+		Width     uint   // Centimeter
+		Length    uint   // Centimeter
+		SortOrder int
+	}
+)
+
+func StandardSizeModelToSchema(s model.StandardSize) *StandardSize {
+	return &StandardSize{
+		Model:     gorm.Model{ID: s.Id},
+		CompanyId: s.CompanyId,
+		Label:     s.Label,
+		Width:     s.Width,
+		Length:    s.Length,
+		SortOrder: s.SortOrder,
+	}
+}
+
+func StandardSizeToModel(s *StandardSize) model.StandardSize {
+	return model.StandardSize{
+		Id:        s.ID,
+		CompanyId: s.CompanyId,
+		Label:     s.Label,
+		Width:     s.Width,
+		Length:    s.Length,
+		SortOrder: s.SortOrder,
+	}
+}
+
+func (s StandardSize) String() string {
+	return fmt.Sprintf("ID: %v, CompanyId: %v, Label: %v, Width: %v, Length: %v, SortOrder: %v",
+		s.ID, s.CompanyId, s.Label, s.Width, s.Length, s.SortOrder)
+}
@@ -0,0 +1,134 @@
+package schema
+
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type (
+	Warehouse struct {
+		gorm.Model
+		CompanyId uint `gorm:"index"`
+		Name      string
+	}
+
+	// Stock is on hand quantity of a carpet in a warehouse, it is changed only with a StockMovement
+	Stock struct {
+		gorm.Model
+		WarehouseId uint `gorm:"uniqueIndex:stock_unique_carpet"`
+		ProductId   uint `gorm:"uniqueIndex:stock_unique_carpet;index"`
+		DimensionId uint `gorm:"uniqueIndex:stock_unique_carpet"`
+		ThemeId     uint `gorm:"uniqueIndex:stock_unique_carpet"`
+		OnHand      int64
+	}
+
+	// StockMovement is an append only entry of stock ledger, Quantity is signed change of on hand quantity
+	// of the warehouse and OnHand is quantity after the movement. a transfer has an entry for each warehouse
+	StockMovement struct {
+		gorm.Model
+		CompanyId          uint `gorm:"index"`
+		WarehouseId        uint `gorm:"index"`
+		CounterWarehouseId uint
+		ProductId          uint `gorm:"index"`
+		DimensionId        uint
+		ThemeId            uint
+		Kind               string
+		Quantity           int64
+		OnHand             int64
+		ReservationId      uint
+		Reference          string
+		Note               string
+	}
+
+	// Reservation hold pieces of a carpet in a warehouse until ExpiresAt, a released reservation has ReleasedAt
+	Reservation struct {
+		gorm.Model
+		CompanyId     uint `gorm:"index"`
+		WarehouseId   uint `gorm:"index"`
+		ProductId     uint `gorm:"index"`
+		DimensionId   uint
+		ThemeId       uint
+		Quantity      int64
+		Holder        string
+		Reason        string
+		ExpiresAt     time.Time `gorm:"index"`
+		ReleasedAt    *time.Time
+		ReleaseReason string
+	}
+)
+
+func WarehouseModelToSchema(w model.Warehouse) *Warehouse {
+	return &Warehouse{
+		Model:     gorm.Model{ID: w.Id},
+		CompanyId: w.CompanyId,
+		Name:      w.Name,
+	}
+}
+
+func WarehouseToModel(w *Warehouse) model.Warehouse {
+	return model.Warehouse{
+		Id:        w.ID,
+		CompanyId: w.CompanyId,
+		Name:      w.Name,
+	}
+}
+
+func (w Warehouse) String() string {
+	return fmt.Sprintf("ID: %v, CompanyId: %v, Name: %v", w.ID, w.CompanyId, w.Name)
+}
+
+func (s Stock) String() string {
+	return fmt.Sprintf("ID: %v, WarehouseId: %v, ProductId: %v, DimensionId: %v, ThemeId: %v, OnHand: %v",
+		s.ID, s.WarehouseId, s.ProductId, s.DimensionId, s.ThemeId, s.OnHand)
+}
+
+func (m StockMovement) String() string {
+	return fmt.Sprintf("ID: %v, CompanyId: %v, WarehouseId: %v, CounterWarehouseId: %v, ProductId: %v, DimensionId: %v, ThemeId: %v, Kind: %v, Quantity: %v, OnHand: %v, ReservationId: %v, Reference: %v",
+		m.ID, m.CompanyId, m.WarehouseId, m.CounterWarehouseId, m.ProductId, m.DimensionId, m.ThemeId, m.Kind, m.Quantity, m.OnHand, m.ReservationId, m.Reference)
+}
+
+func ReservationModelToSchema(r model.Reservation) *Reservation {
+	return &Reservation{
+		Model:         gorm.Model{ID: r.Id},
+		CompanyId:     r.CompanyId,
+		WarehouseId:   r.WarehouseId,
+		ProductId:     r.ProductId,
+		DimensionId:   r.DimensionId,
+		ThemeId:       r.ThemeId,
+		Quantity:      r.Quantity,
+		Holder:        r.Holder,
+		Reason:        r.Reason,
+		ExpiresAt:     r.ExpiresAt,
+		ReleasedAt:    r.ReleasedAt,
+		ReleaseReason: r.ReleaseReason,
+	}
+}
+
+func ReservationToModel(r *Reservation) model.Reservation {
+	return model.Reservation{
+		Id:            r.ID,
+		CompanyId:     r.CompanyId,
+		WarehouseId:   r.WarehouseId,
+		ProductId:     r.ProductId,
+		DimensionId:   r.DimensionId,
+		ThemeId:       r.ThemeId,
+		Quantity:      r.Quantity,
+		Holder:        r.Holder,
+		Reason:        r.Reason,
+		ExpiresAt:     r.ExpiresAt,
+		ReleasedAt:    r.ReleasedAt,
+		ReleaseReason: r.ReleaseReason,
+		CreatedAt:     r.CreatedAt,
+	}
+}
+
+func (r Reservation) String() string {
+	releasedAt := "nil"
+	if r.ReleasedAt != nil {
+		releasedAt = r.ReleasedAt.String()
+	}
+	return fmt.Sprintf("ID: %v, CompanyId: %v, WarehouseId: %v, ProductId: %v, DimensionId: %v, ThemeId: %v, Quantity: %v, Holder: %v, ExpiresAt: %v, ReleasedAt: %v, ReleaseReason: %v",
+		r.ID, r.CompanyId, r.WarehouseId, r.ProductId, r.DimensionId, r.ThemeId, r.Quantity, r.Holder, r.ExpiresAt, releasedAt, r.ReleaseReason)
+}
@@ -0,0 +1,27 @@
+package schema
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+)
+
+type (
+	// Tag is a free-form label of a company, Name is normalized
+	Tag struct {
+		gorm.Model
+		CompanyId uint   `gorm:"uniqueIndex:tag_unique_id"`
+		Name      string `gorm:"uniqueIndex:tag_unique_id"`
+	}
+)
+
+func (t Tag) String() string {
+	return fmt.Sprintf("ID: %v, CompanyId: %v, Name: %v", t.ID, t.CompanyId, t.Name)
+}
+
+func GetTagNames(tags []Tag) []string {
+	var result []string
+	for _, t := range tags {
+		result = append(result, t.Name)
+	}
+	return result
+}
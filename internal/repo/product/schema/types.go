@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// Strings is a list of strings stored as a json text column
+type Strings []string
+
+func (s Strings) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(s)
+	return string(b), err
+}
+
+func (s *Strings) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	case []byte:
+		return json.Unmarshal(v, s)
+	}
+	return errors.New("invalid strings value")
+}
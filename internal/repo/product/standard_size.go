@@ -0,0 +1,241 @@
+package product
+
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+)
+
+// CreateStandardSize add a size to catalog of `size.CompanyId`
+func (r *productRepo) CreateStandardSize(size model.StandardSize) (standardSize *schema.StandardSize, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("model_standard_size", fmt.Sprintf("%+v", size)),
+			keyval.String("schema_standard_size", fmt.Sprintf("%+v", standardSize)),
+		}
+		logger.LogReqRes(r.logger, "standard_size.CreateStandardSize", err, commonKeyVal...)
+	}()
+
+	standardSize = schema.StandardSizeModelToSchema(size)
+	if err := r.db.Create(standardSize).Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	return standardSize, nil
+}
+
+// GetStandardSizes return catalog of `companyId` ordered by sort order
+func (r *productRepo) GetStandardSizes(companyId uint) (standardSizes []schema.StandardSize, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("standard_sizes", fmt.Sprintf("%+v", standardSizes)),
+		}
+		logger.LogReqRes(r.logger, "standard_size.GetStandardSizes", err, commonKeyVal...)
+	}()
+
+	tx := r.db.Order("sort_order ASC").Order("id ASC").Where("company_id = ?", companyId).Find(&standardSizes)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	return standardSizes, nil
+}
+
+// EditStandardSize update a catalog size, dimensions referencing it follow the new label.
+// a product that has another dimension with the new label, even a removed one, rejects the edit
+func (r *productRepo) EditStandardSize(size model.StandardSize) (standardSize *schema.StandardSize, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("model_standard_size", fmt.Sprintf("%+v", size)),
+			keyval.String("schema_standard_size", fmt.Sprintf("%+v", standardSize)),
+		}
+		logger.LogReqRes(r.logger, "standard_size.EditStandardSize", err, commonKeyVal...)
+	}()
+
+	standardSize = schema.StandardSizeModelToSchema(size)
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&schema.StandardSize{}).
+			Where("id = ? AND company_id = ?", standardSize.ID, standardSize.CompanyId).
+			Updates(map[string]interface{}{
+				"label":      standardSize.Label,
+				"width":      standardSize.Width,
+				"length":     standardSize.Length,
+				"sort_order": standardSize.SortOrder,
+			})
+		if err := result.Error; err != nil {
+			return err
+		} else if result.RowsAffected < 1 {
+			return derror.StandardSizeNotFound
+		}
+
+		if err := checkStandardSizeLabelFree(tx, standardSize.ID, standardSize.Label); err != nil {
+			return err
+		}
+
+		result = tx.Model(&schema.Dimension{}).
+			Where("standard_size_id = ?", standardSize.ID).
+			Update("size", standardSize.Label)
+		if err := result.Error; err != nil {
+			return err
+		}
+
+		return tx.First(standardSize).Error
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return standardSize, nil
+}
+
+// checkStandardSizeLabelFree return derror.InvalidStandardSize if a product with a dimension of `standardSizeId`
+// has another dimension with `label`, a removed dimension keeps its size in dimension_unique_id
+func checkStandardSizeLabelFree(tx *gorm.DB, standardSizeId uint, label string) error {
+	var productIds []uint
+	result := tx.Unscoped().Model(&schema.Dimension{}).
+		Where("size = ? AND product_id IN (?)", label,
+			tx.Model(&schema.Dimension{}).Select("product_id").Where("standard_size_id = ?", standardSizeId)).
+		Where("standard_size_id IS DISTINCT FROM ? OR deleted_at IS NOT NULL", standardSizeId).
+		Distinct().Order("product_id").Pluck("product_id", &productIds)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if len(productIds) != 0 {
+		return derror.New(derror.InvalidStandardSize, fmt.Sprintf("size %v exists in product ids %v", label, productIds))
+	}
+	return nil
+}
+
+// DeleteStandardSize soft delete a catalog size, a size referenced by a dimension can't delete
+func (r *productRepo) DeleteStandardSize(companyId, standardSizeId uint) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("standard_size_id", fmt.Sprintf("%v", standardSizeId)),
+		}
+		logger.LogReqRes(r.logger, "standard_size.DeleteStandardSize", err, commonKeyVal...)
+	}()
+
+	var used int64
+	if err := r.db.Model(&schema.Dimension{}).Where("standard_size_id = ?", standardSizeId).Count(&used).Error; err != nil {
+		return derror.New(derror.InternalServer, err.Error())
+	}
+	if used != 0 {
+		return derror.StandardSizeInUse
+	}
+
+	tx := r.db.Where("company_id = ?", companyId).Delete(&schema.StandardSize{Model: gorm.Model{ID: standardSizeId}})
+	if err := tx.Error; err != nil {
+		return derror.New(derror.InternalServer, err.Error())
+	} else if tx.RowsAffected < 1 {
+		return derror.StandardSizeNotFound
+	}
+
+	return nil
+}
+
+// GetStandardSizeProducts return every catalog size of `companyId` with products that offer it
+func (r *productRepo) GetStandardSizeProducts(companyId uint) (report []model.StandardSizeProducts, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("report", fmt.Sprintf("%+v", report)),
+		}
+		logger.LogReqRes(r.logger, "standard_size.GetStandardSizeProducts", err, commonKeyVal...)
+	}()
+
+	standardSizes, err := r.GetStandardSizes(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		StandardSizeId uint
+		ProductId      uint
+		DesignCode     string
+	}
+	tx := r.db.Table("tbl_dimension d").
+		Select("d.standard_size_id, p.id AS product_id, p.design_code").
+		Joins("JOIN tbl_product p ON p.id = d.product_id AND p.deleted_at IS NULL").
+		Where("d.deleted_at IS NULL AND d.standard_size_id IS NOT NULL AND p.company_id = ?", companyId).
+		Order("p.design_code ASC").
+		Scan(&rows)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	report = make([]model.StandardSizeProducts, len(standardSizes))
+	index := make(map[uint]int, len(standardSizes))
+	for i, s := range standardSizes {
+		report[i] = model.StandardSizeProducts{
+			StandardSize: schema.StandardSizeToModel(&s),
+			Products:     []model.ProductCode{},
+		}
+		index[s.ID] = i
+	}
+
+	for _, row := range rows {
+		if i, ok := index[row.StandardSizeId]; ok {
+			report[i].Products = append(report[i].Products, model.ProductCode{
+				ProductId:  row.ProductId,
+				DesignCode: row.DesignCode,
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// withStandardSizes link `dimensions` to catalog sizes of `companyId`.
+// a catalog size whose label isn't in `dimensions` is appended as a new dimension.
+// if a `standardSizeId` not found for `companyId` return derror.StandardSizeNotFound
+func withStandardSizes(db *gorm.DB, companyId, productId uint, dimensions []schema.Dimension, standardSizeIds []uint) ([]schema.Dimension, error) {
+	if len(standardSizeIds) == 0 {
+		return dimensions, nil
+	}
+
+	var standardSizes []schema.StandardSize
+	tx := db.Where("company_id = ? AND id IN ?", companyId, standardSizeIds).Find(&standardSizes)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	found := make(map[uint]bool, len(standardSizes))
+	for _, s := range standardSizes {
+		found[s.ID] = true
+	}
+	for _, id := range standardSizeIds {
+		if !found[id] {
+			return nil, derror.New(derror.StandardSizeNotFound, fmt.Sprintf("standard size id %v", id))
+		}
+	}
+
+StandardSizeLoop:
+	for _, s := range standardSizes {
+		id := s.ID
+		for i := range dimensions {
+			if dimensions[i].Size == s.Label {
+				dimensions[i].StandardSizeId = &id
+				continue StandardSizeLoop
+			}
+		}
+		dimensions = append(dimensions, schema.Dimension{
+			ProductId:      productId,
+			Size:           s.Label,
+			StandardSizeId: &id,
+		})
+	}
+
+	return dimensions, nil
+}
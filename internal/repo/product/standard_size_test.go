@@ -0,0 +1,230 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func CreateStandardSize(repo *productRepo, t *testing.T, label string, sortOrder int) *schema.StandardSize {
+	s, err := repo.CreateStandardSize(model.StandardSize{
+		CompanyId: 1,
+		Label:     label,
+		Width:     200,
+		Length:    300,
+		SortOrder: sortOrder,
+	})
+	require.Nil(t, err)
+	require.NotNil(t, s)
+	return s
+}
+
+func TestProductRepo_CreateStandardSize_Ok(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := CreateStandardSize(pRepo, t, "6", 1)
+	require.NotEqual(t, uint(0), s.ID)
+	require.Equal(t, "6", s.Label)
+}
+
+func TestProductRepo_CreateStandardSize_Duplicate(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = CreateStandardSize(pRepo, t, "6", 1)
+
+	s, err := pRepo.CreateStandardSize(model.StandardSize{CompanyId: 1, Label: "6"})
+	require.NotNil(t, err)
+	require.Nil(t, s)
+
+	// Same label for another company
+	s, err = pRepo.CreateStandardSize(model.StandardSize{CompanyId: 2, Label: "6"})
+	require.Nil(t, err)
+	require.NotNil(t, s)
+}
+
+func TestProductRepo_GetStandardSizes_Order(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = CreateStandardSize(pRepo, t, "12", 3)
+	_ = CreateStandardSize(pRepo, t, "6", 1)
+	_ = CreateStandardSize(pRepo, t, "9", 2)
+
+	sizes, err := pRepo.GetStandardSizes(1)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(sizes))
+	require.Equal(t, "6", sizes[0].Label)
+	require.Equal(t, "9", sizes[1].Label)
+	require.Equal(t, "12", sizes[2].Label)
+
+	sizes, err = pRepo.GetStandardSizes(2)
+	require.Nil(t, err)
+	require.Equal(t, 0, len(sizes))
+}
+
+func TestProductRepo_EditStandardSize_Ok(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := CreateStandardSize(pRepo, t, "6", 1)
+	p, err := pRepo.CreateProduct(model.Product{
+		CompanyId:     1,
+		DesignCode:    "105",
+		Colors:        []string{"قرمز"},
+		StandardSizes: []uint{s.ID},
+	})
+	require.Nil(t, err)
+
+	edited, err := pRepo.EditStandardSize(model.StandardSize{Id: s.ID, CompanyId: 1, Label: "6 متری", Width: 200, Length: 300})
+	require.Nil(t, err)
+	require.Equal(t, "6 متری", edited.Label)
+
+	// Dimension follows label of catalog
+	gotP, err := pRepo.GetProductWithId(p.ID)
+	require.Nil(t, err)
+	require.Equal(t, []string{"6 متری"}, schema.GetSizes(gotP.Dimensions))
+}
+
+func TestProductRepo_EditStandardSize_LabelExists(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := CreateStandardSize(pRepo, t, "6", 1)
+	_, err = pRepo.CreateProduct(model.Product{
+		CompanyId:     1,
+		DesignCode:    "105",
+		Colors:        []string{"قرمز"},
+		Sizes:         []string{"9"},
+		StandardSizes: []uint{s.ID},
+	})
+	require.Nil(t, err)
+
+	edited, err := pRepo.EditStandardSize(model.StandardSize{Id: s.ID, CompanyId: 1, Label: "9", Width: 200, Length: 300})
+	require.Equal(t, derror.StatusText(derror.InvalidStandardSize), derror.StatusText(err))
+	require.Nil(t, edited)
+}
+
+func TestProductRepo_EditStandardSize_NotExist(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := CreateStandardSize(pRepo, t, "6", 1)
+
+	edited, err := pRepo.EditStandardSize(model.StandardSize{Id: s.ID, CompanyId: 2, Label: "9"})
+	require.Equal(t, derror.StandardSizeNotFound, err)
+	require.Nil(t, edited)
+}
+
+func TestProductRepo_DeleteStandardSize(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s1 := CreateStandardSize(pRepo, t, "6", 1)
+	s2 := CreateStandardSize(pRepo, t, "9", 2)
+	_, err = pRepo.CreateProduct(model.Product{
+		CompanyId:     1,
+		DesignCode:    "105",
+		Colors:        []string{"قرمز"},
+		StandardSizes: []uint{s1.ID},
+	})
+	require.Nil(t, err)
+
+	t.Run("in use", func(t *testing.T) {
+		err := pRepo.DeleteStandardSize(1, s1.ID)
+		require.Equal(t, derror.StandardSizeInUse, err)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		err := pRepo.DeleteStandardSize(1, s2.ID)
+		require.Nil(t, err)
+	})
+
+	t.Run("not exist", func(t *testing.T) {
+		err := pRepo.DeleteStandardSize(1, s2.ID)
+		require.Equal(t, derror.StandardSizeNotFound, err)
+	})
+}
+
+func TestProductRepo_CreateProduct_StandardSizes(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s1 := CreateStandardSize(pRepo, t, "6", 1)
+	s2 := CreateStandardSize(pRepo, t, "9", 2)
+
+	t.Run("ok", func(t *testing.T) {
+		p, err := pRepo.CreateProduct(model.Product{
+			CompanyId:     1,
+			DesignCode:    "105",
+			Colors:        []string{"قرمز"},
+			Sizes:         []string{"9", "2x3"},
+			StandardSizes: []uint{s1.ID, s2.ID},
+		})
+		require.Nil(t, err)
+		require.Equal(t, []string{"9", "2x3", "6"}, schema.GetSizes(p.Dimensions))
+		require.ElementsMatch(t, []uint{s1.ID, s2.ID}, schema.GetStandardSizeIds(p.Dimensions))
+	})
+
+	t.Run("not exist", func(t *testing.T) {
+		p, err := pRepo.CreateProduct(model.Product{
+			CompanyId:     2,
+			DesignCode:    "105",
+			Colors:        []string{"قرمز"},
+			StandardSizes: []uint{s1.ID},
+		})
+		require.NotNil(t, err)
+		require.Nil(t, p)
+	})
+}
+
+func TestProductRepo_GetStandardSizeProducts(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s1 := CreateStandardSize(pRepo, t, "6", 1)
+	s2 := CreateStandardSize(pRepo, t, "9", 2)
+	_ = CreateStandardSize(pRepo, t, "12", 3)
+
+	p1, err := pRepo.CreateProduct(model.Product{CompanyId: 1, DesignCode: "105", Colors: []string{"قرمز"}, StandardSizes: []uint{s1.ID, s2.ID}})
+	require.Nil(t, err)
+	p2, err := pRepo.CreateProduct(model.Product{CompanyId: 1, DesignCode: "106", Colors: []string{"قرمز"}, StandardSizes: []uint{s2.ID}})
+	require.Nil(t, err)
+
+	report, err := pRepo.GetStandardSizeProducts(1)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(report))
+	require.Equal(t, []model.ProductCode{{ProductId: p1.ID, DesignCode: "105"}}, report[0].Products)
+	require.Equal(t, []model.ProductCode{{ProductId: p1.ID, DesignCode: "105"}, {ProductId: p2.ID, DesignCode: "106"}}, report[1].Products)
+	require.Equal(t, 0, len(report[2].Products))
+}
@@ -0,0 +1,498 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// CreateWarehouse add a warehouse for `warehouse.CompanyId`, name of warehouses of a company is unique
+func (r *productRepo) CreateWarehouse(warehouse model.Warehouse) (schemaWarehouse *schema.Warehouse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("model_warehouse", fmt.Sprintf("%+v", warehouse)),
+			keyval.String("schema_warehouse", fmt.Sprintf("%+v", schemaWarehouse)),
+		}
+		logger.LogReqRes(r.logger, "stock.CreateWarehouse", err, commonKeyVal...)
+	}()
+
+	schemaWarehouse = schema.WarehouseModelToSchema(warehouse)
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		result := tx.Model(&schema.Warehouse{}).Where("company_id = ? AND name = ?", warehouse.CompanyId, warehouse.Name).Count(&count)
+		if err := result.Error; err != nil {
+			return err
+		}
+		if count != 0 {
+			return derror.New(derror.InvalidWarehouse, "duplicate name "+warehouse.Name)
+		}
+
+		return tx.Create(schemaWarehouse).Error
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return schemaWarehouse, nil
+}
+
+// GetWarehouses return all warehouses of `companyId`
+func (r *productRepo) GetWarehouses(companyId uint) (warehouses []schema.Warehouse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("warehouses", fmt.Sprintf("%+v", warehouses)),
+		}
+		logger.LogReqRes(r.logger, "stock.GetWarehouses", err, commonKeyVal...)
+	}()
+
+	tx := r.db.Order("id ASC").Where("company_id = ?", companyId).Find(&warehouses)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	return warehouses, nil
+}
+
+// DeleteWarehouse soft delete an empty warehouse, its ledger is kept
+func (r *productRepo) DeleteWarehouse(companyId, warehouseId uint) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("warehouse_id", fmt.Sprintf("%v", warehouseId)),
+		}
+		logger.LogReqRes(r.logger, "stock.DeleteWarehouse", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := getWarehouse(tx, companyId, warehouseId); err != nil {
+			return err
+		}
+
+		var count int64
+		result := tx.Model(&schema.Stock{}).Where("warehouse_id = ? AND on_hand <> 0", warehouseId).Count(&count)
+		if err := result.Error; err != nil {
+			return err
+		}
+		if count != 0 {
+			return derror.New(derror.WarehouseNotEmpty, fmt.Sprintf("%v carpets in warehouse", count))
+		}
+
+		return tx.Delete(&schema.Warehouse{Model: gorm.Model{ID: warehouseId}}).Error
+	})
+
+	return derror.Wrap(err)
+}
+
+// RecordStockMovement append `movement` to stock ledger and change on hand quantity of its carpet,
+// a transfer is recorded as an outgoing entry of source and an incoming entry of destination warehouse.
+// on hand quantity can't be negative, a sale or transfer can't take reserved pieces, except a sale
+// that fulfills its reservation, and a discontinued product doesn't accept receipts
+func (r *productRepo) RecordStockMovement(movement model.StockMovement) (entries []schema.StockMovement, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("movement", fmt.Sprintf("%+v", movement)),
+			keyval.String("entries", fmt.Sprintf("%+v", entries)),
+		}
+		logger.LogReqRes(r.logger, "stock.RecordStockMovement", err, commonKeyVal...)
+	}()
+
+	if err := movement.Validate(); err != nil {
+		return nil, derror.New(derror.InvalidStockMovement, err.Error())
+	}
+
+	// A transaction that failed on a dropped view is rolled back and run again
+	err = r.withCarpetView(movement.CompanyId, func(view string) error {
+		var err error
+		entries, err = recordStockMovement(r.db, view, movement)
+		return err
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return entries, nil
+}
+
+// recordStockMovement apply `movement` to carpet in `view` in a transaction and return its ledger entries
+func recordStockMovement(db *gorm.DB, view string, movement model.StockMovement) (entries []schema.StockMovement, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if _, err := getWarehouse(tx, movement.CompanyId, movement.WarehouseId); err != nil {
+			return err
+		}
+		if movement.Kind == model.MovementTransfer {
+			if _, err := getWarehouse(tx, movement.CompanyId, movement.ToWarehouseId); err != nil {
+				return err
+			}
+		}
+
+		if err := checkCarpet(tx, view, movement.ProductId, movement.DimensionId, movement.ThemeId); err != nil {
+			return err
+		}
+
+		if movement.IncreasesStock() {
+			if err := checkProductAcceptsEntries(tx, movement.CompanyId, movement.ProductId); err != nil {
+				return err
+			}
+		}
+
+		if movement.ReservationId != 0 {
+			if err := fulfillReservation(tx, movement); err != nil {
+				return err
+			}
+		}
+
+		entry, err := moveStock(tx, movement, movement.WarehouseId, movement.ToWarehouseId, movement.Delta())
+		if err != nil {
+			return err
+		}
+		entries = append(entries, *entry)
+
+		if movement.Kind == model.MovementTransfer {
+			entry, err := moveStock(tx, movement, movement.ToWarehouseId, movement.WarehouseId, movement.Quantity)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, *entry)
+		}
+
+		return nil
+	})
+	return entries, err
+}
+
+// GetStockMovements return a page of stock ledger entries that match `filter`, newest first, and total number of matched entries
+func (r *productRepo) GetStockMovements(filter model.MovementFilter, page model.Page) (entries []schema.StockMovement, total int64, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("filter", fmt.Sprintf("%+v", filter)),
+			keyval.String("page", fmt.Sprintf("%+v", page)),
+			keyval.String("total", fmt.Sprintf("%v", total)),
+		}
+		logger.LogReqRes(r.logger, "stock.GetStockMovements", err, commonKeyVal...)
+	}()
+
+	page = page.Normalize()
+
+	query := func() *gorm.DB {
+		query := r.db.Model(&schema.StockMovement{}).Where("company_id = ?", filter.CompanyId)
+		if filter.WarehouseId != 0 {
+			query = query.Where("warehouse_id = ?", filter.WarehouseId)
+		}
+		if filter.ProductId != 0 {
+			query = query.Where("product_id = ?", filter.ProductId)
+		}
+		if filter.DimensionId != 0 {
+			query = query.Where("dimension_id = ?", filter.DimensionId)
+		}
+		if filter.ThemeId != 0 {
+			query = query.Where("theme_id = ?", filter.ThemeId)
+		}
+		if filter.Kind != "" {
+			query = query.Where("kind = ?", filter.Kind)
+		}
+		return query
+	}
+
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, derror.New(derror.InternalServer, err.Error())
+	}
+
+	tx := query().Order("id DESC").Offset(page.Offset()).Limit(page.Size).Find(&entries)
+	if err := tx.Error; err != nil {
+		return nil, 0, derror.New(derror.InternalServer, err.Error())
+	}
+
+	return entries, total, nil
+}
+
+// GetStock return a page of on hand, reserved and available quantities of carpets of `companyId` per warehouse
+// and total number of them, zero `warehouseId` and `productId` match every warehouse and product
+func (r *productRepo) GetStock(companyId, warehouseId, productId uint, page model.Page) (levels []model.StockLevel, total int64, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("warehouse_id", fmt.Sprintf("%v", warehouseId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("page", fmt.Sprintf("%+v", page)),
+			keyval.String("total", fmt.Sprintf("%v", total)),
+		}
+		logger.LogReqRes(r.logger, "stock.GetStock", err, commonKeyVal...)
+	}()
+
+	page = page.Normalize()
+
+	var rows []stockRow
+	err = r.withCarpetView(companyId, func(view string) error {
+		return stockLevels(r.db, view, warehouseId, productId, page, &total, &rows)
+	})
+	if err != nil {
+		return nil, 0, derror.New(derror.InternalServer, err.Error())
+	}
+
+	levels = make([]model.StockLevel, len(rows))
+	for i := range rows {
+		levels[i] = model.StockLevel{
+			WarehouseId: rows[i].WarehouseId,
+			Carpet:      schema.CarpetToModel(&rows[i].Carpet, companyId),
+			OnHand:      rows[i].OnHand,
+			Reserved:    rows[i].Reserved,
+			Available:   model.Available(rows[i].OnHand, rows[i].Reserved),
+		}
+	}
+
+	return levels, total, nil
+}
+
+// stockLevels count stock rows of carpets in `view` and scan a page of them with their reserved quantity
+func stockLevels(db *gorm.DB, view string, warehouseId, productId uint, page model.Page, total *int64, rows *[]stockRow) error {
+	query := func() *gorm.DB {
+		query := db.Table("tbl_stock s").
+			Joins("JOIN " + view + " v ON v.product_id = s.product_id AND v.dimension_id = s.dimension_id AND v.theme_id = s.theme_id").
+			Where("s.deleted_at IS NULL")
+		if warehouseId != 0 {
+			query = query.Where("s.warehouse_id = ?", warehouseId)
+		}
+		if productId != 0 {
+			query = query.Where("s.product_id = ?", productId)
+		}
+		return query
+	}
+
+	if err := query().Count(total).Error; err != nil {
+		return err
+	}
+
+	reserved := activeReservations(db, time.Now()).
+		Select("warehouse_id, product_id, dimension_id, theme_id, SUM(quantity) AS reserved").
+		Group("warehouse_id, product_id, dimension_id, theme_id")
+
+	return query().
+		Joins("LEFT JOIN (?) r ON r.warehouse_id = s.warehouse_id AND r.product_id = s.product_id AND r.dimension_id = s.dimension_id AND r.theme_id = s.theme_id", reserved).
+		Select("s.warehouse_id, s.on_hand, COALESCE(r.reserved, 0) AS reserved, v.*").
+		Order("s.warehouse_id ASC").Order("s.product_id ASC").Order("s.dimension_id ASC").Order("s.theme_id ASC").
+		Offset(page.Offset()).Limit(page.Size).Scan(rows).Error
+}
+
+// stockRow is a stock row joined with its reserved quantity and its carpet
+type stockRow struct {
+	WarehouseId uint
+	OnHand      int64
+	Reserved    int64
+	schema.Carpet
+}
+
+// moveStock change on hand quantity of carpet of `movement` in `warehouseId` by `delta` and append its ledger entry
+func moveStock(tx *gorm.DB, movement model.StockMovement, warehouseId, counterWarehouseId uint, delta int64) (*schema.StockMovement, error) {
+	stock, err := lockStock(tx, warehouseId, movement.ProductId, movement.DimensionId, movement.ThemeId)
+	if err != nil {
+		return nil, err
+	}
+
+	onHand := stock.OnHand + delta
+	if onHand < 0 {
+		return nil, derror.New(derror.InsufficientStock, fmt.Sprintf("%v on hand in warehouse id %v", stock.OnHand, warehouseId))
+	}
+
+	if delta < 0 && movement.ReducesAvailable() {
+		reserved, err := reservedQuantity(tx, warehouseId, movement.ProductId, movement.DimensionId, movement.ThemeId, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if onHand < reserved {
+			return nil, derror.New(derror.InsufficientStock,
+				fmt.Sprintf("%v available in warehouse id %v", model.Available(stock.OnHand, reserved), warehouseId))
+		}
+	}
+
+	if err := tx.Model(stock).Update("on_hand", onHand).Error; err != nil {
+		return nil, err
+	}
+
+	entry := &schema.StockMovement{
+		CompanyId:          movement.CompanyId,
+		WarehouseId:        warehouseId,
+		CounterWarehouseId: counterWarehouseId,
+		ProductId:          movement.ProductId,
+		DimensionId:        movement.DimensionId,
+		ThemeId:            movement.ThemeId,
+		Kind:               movement.Kind,
+		Quantity:           delta,
+		OnHand:             onHand,
+		ReservationId:      movement.ReservationId,
+		Reference:          movement.Reference,
+		Note:               movement.Note,
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// lockStock return stock row of a carpet in `warehouseId` locked for update, a missing row is created with zero quantity
+func lockStock(tx *gorm.DB, warehouseId, productId, dimensionId, themeId uint) (*schema.Stock, error) {
+	stock := &schema.Stock{
+		WarehouseId: warehouseId,
+		ProductId:   productId,
+		DimensionId: dimensionId,
+		ThemeId:     themeId,
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(stock).Error; err != nil {
+		return nil, err
+	}
+
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("warehouse_id = ? AND product_id = ? AND dimension_id = ? AND theme_id = ?", warehouseId, productId, dimensionId, themeId).
+		Take(stock)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return stock, nil
+}
+
+// checkCarpet return derror.CarpetExcluded if carpet is excluded and derror.CarpetNotFound
+// if carpet isn't in carpet `view` of a company
+func checkCarpet(db *gorm.DB, view string, productId, dimensionId, themeId uint) error {
+	var count int64
+	result := db.Table(view).
+		Where("product_id = ? AND dimension_id = ? AND theme_id = ?", productId, dimensionId, themeId).
+		Count(&count)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if count != 0 {
+		return nil
+	}
+
+	if err := checkNotExcluded(db, productId, dimensionId, themeId); err != nil {
+		return err
+	}
+	return derror.CarpetNotFound
+}
+
+func getWarehouse(db *gorm.DB, companyId, warehouseId uint) (*schema.Warehouse, error) {
+	warehouse := &schema.Warehouse{}
+	if err := db.Where("company_id = ?", companyId).First(warehouse, warehouseId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, derror.New(derror.WarehouseNotFound, fmt.Sprintf("warehouse id %v", warehouseId))
+		}
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+// stockQuantity is on hand and actively reserved pieces of a carpet in a warehouse
+type stockQuantity struct {
+	WarehouseId uint
+	ProductId   uint
+	DimensionId uint
+	ThemeId     uint
+	OnHand      int64
+	Reserved    int64
+}
+
+// stockQuantities return quantities of carpets of `productIds` in every warehouse
+func stockQuantities(db *gorm.DB, productIds []uint) ([]stockQuantity, error) {
+	reserved := activeReservations(db, time.Now()).
+		Select("warehouse_id, product_id, dimension_id, theme_id, SUM(quantity) AS reserved").
+		Where("product_id IN ?", productIds).
+		Group("warehouse_id, product_id, dimension_id, theme_id")
+
+	var quantities []stockQuantity
+	result := db.Table("tbl_stock s").
+		Joins("LEFT JOIN (?) r ON r.warehouse_id = s.warehouse_id AND r.product_id = s.product_id AND r.dimension_id = s.dimension_id AND r.theme_id = s.theme_id", reserved).
+		Select("s.warehouse_id, s.product_id, s.dimension_id, s.theme_id, s.on_hand, COALESCE(r.reserved, 0) AS reserved").
+		Where("s.deleted_at IS NULL AND s.product_id IN ?", productIds).
+		Scan(&quantities)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return quantities, nil
+}
+
+// productsStock return on hand and available pieces of carpets of `productIds` in all warehouses
+func productsStock(db *gorm.DB, productIds ...uint) (onHand, available map[uint]int64, err error) {
+	quantities, err := stockQuantities(db, productIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	onHand = make(map[uint]int64)
+	available = make(map[uint]int64)
+	for _, q := range quantities {
+		onHand[q.ProductId] += q.OnHand
+		available[q.ProductId] += model.Available(q.OnHand, q.Reserved)
+	}
+	return onHand, available, nil
+}
+
+// setProductsStock set on hand and available quantity of `products`
+func setProductsStock(db *gorm.DB, products []schema.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	productIds := make([]uint, len(products))
+	for i := range products {
+		productIds[i] = products[i].ID
+	}
+
+	onHand, available, err := productsStock(db, productIds...)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		products[i].OnHand = onHand[products[i].ID]
+		products[i].Available = available[products[i].ID]
+	}
+	return nil
+}
+
+// setCarpetsStock set on hand and available quantity of `carpets` in all warehouses
+func setCarpetsStock(db *gorm.DB, carpets []model.Carpet) error {
+	if len(carpets) == 0 {
+		return nil
+	}
+
+	productIds := make([]uint, len(carpets))
+	for i := range carpets {
+		productIds[i] = carpets[i].ProductId
+	}
+
+	quantities, err := stockQuantities(db, productIds)
+	if err != nil {
+		return err
+	}
+
+	onHand := make(map[[3]uint]int64)
+	available := make(map[[3]uint]int64)
+	for _, q := range quantities {
+		key := [3]uint{q.ProductId, q.DimensionId, q.ThemeId}
+		onHand[key] += q.OnHand
+		available[key] += model.Available(q.OnHand, q.Reserved)
+	}
+
+	for i := range carpets {
+		key := [3]uint{carpets[i].ProductId, carpets[i].DimensionId, carpets[i].ThemeId}
+		carpets[i].OnHand = onHand[key]
+		carpets[i].Available = available[key]
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestProductRepo_RecordStockMovement(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	central, err := pRepo.CreateWarehouse(model.Warehouse{CompanyId: 1, Name: "مرکزی"})
+	require.Nil(t, err)
+	shop, err := pRepo.CreateWarehouse(model.Warehouse{CompanyId: 1, Name: "فروشگاه"})
+	require.Nil(t, err)
+
+	_, err = pRepo.CreateWarehouse(model.Warehouse{CompanyId: 1, Name: "مرکزی"})
+	require.Equal(t, derror.StatusText(derror.InvalidWarehouse), derror.StatusText(err))
+
+	movement := model.StockMovement{
+		CompanyId:   1,
+		WarehouseId: central.ID,
+		ProductId:   p.ID,
+		DimensionId: p.Dimensions[0].ID,
+		ThemeId:     p.Themes[0].ID,
+		Kind:        model.MovementReceipt,
+		Quantity:    10,
+	}
+	entries, err := pRepo.RecordStockMovement(movement)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(entries))
+	require.Equal(t, int64(10), entries[0].OnHand)
+
+	// Sale of more than on hand
+	movement.Kind, movement.Quantity = model.MovementSale, 11
+	_, err = pRepo.RecordStockMovement(movement)
+	require.Equal(t, derror.StatusText(derror.InsufficientStock), derror.StatusText(err))
+
+	movement.Quantity = 3
+	_, err = pRepo.RecordStockMovement(movement)
+	require.Nil(t, err)
+
+	movement.Kind, movement.ToWarehouseId, movement.Quantity = model.MovementTransfer, shop.ID, 2
+	entries, err = pRepo.RecordStockMovement(movement)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(entries))
+	require.Equal(t, int64(-2), entries[0].Quantity)
+	require.Equal(t, int64(5), entries[0].OnHand)
+	require.Equal(t, int64(2), entries[1].Quantity)
+	require.Equal(t, shop.ID, entries[1].WarehouseId)
+
+	levels, total, err := pRepo.GetStock(1, 0, p.ID, model.Page{})
+	require.Nil(t, err)
+	require.Equal(t, int64(2), total)
+	require.Equal(t, int64(5), levels[0].OnHand)
+
+	_, total, err = pRepo.GetStockMovements(model.MovementFilter{CompanyId: 1, WarehouseId: central.ID}, model.Page{})
+	require.Nil(t, err)
+	require.Equal(t, int64(3), total)
+
+	// On hand of all warehouses in listings
+	product, err := pRepo.GetProductWithId(p.ID)
+	require.Nil(t, err)
+	require.Equal(t, int64(7), product.OnHand)
+
+	carpets, err := pRepo.GetAllCarpetWithProductId(1, p.ID)
+	require.Nil(t, err)
+	for _, c := range carpets {
+		if c.DimensionId == movement.DimensionId && c.ThemeId == movement.ThemeId {
+			require.Equal(t, int64(7), c.OnHand)
+		} else {
+			require.Equal(t, int64(0), c.OnHand)
+		}
+	}
+
+	err = pRepo.DeleteWarehouse(1, shop.ID)
+	require.Equal(t, derror.StatusText(derror.WarehouseNotEmpty), derror.StatusText(err))
+}
+
+func TestProductRepo_RecordStockMovement_Discontinued(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	warehouse, err := pRepo.CreateWarehouse(model.Warehouse{CompanyId: 1, Name: "مرکزی"})
+	require.Nil(t, err)
+
+	movement := model.StockMovement{
+		CompanyId:   1,
+		WarehouseId: warehouse.ID,
+		ProductId:   p.ID,
+		DimensionId: p.Dimensions[0].ID,
+		ThemeId:     p.Themes[0].ID,
+		Kind:        model.MovementReceipt,
+		Quantity:    4,
+	}
+	_, err = pRepo.RecordStockMovement(movement)
+	require.Nil(t, err)
+
+	_, err = pRepo.ChangeProductStatus(1, p.ID, model.StatusDiscontinued)
+	require.Nil(t, err)
+
+	_, err = pRepo.RecordStockMovement(movement)
+	require.Equal(t, derror.StatusText(derror.ProductDiscontinued), derror.StatusText(err))
+
+	// Remaining pieces can be sold
+	movement.Kind = model.MovementSale
+	_, err = pRepo.RecordStockMovement(movement)
+	require.Nil(t, err)
+
+	// Carpet of another company
+	movement.CompanyId = 2
+	_, err = pRepo.RecordStockMovement(movement)
+	require.NotNil(t, err)
+}
+
+func TestProductRepo_EditProduct_CarpetInStock(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := CreateProduct1(pRepo, t)
+	warehouse, err := pRepo.CreateWarehouse(model.Warehouse{CompanyId: 1, Name: "مرکزی"})
+	require.Nil(t, err)
+
+	_, err = pRepo.RecordStockMovement(model.StockMovement{
+		CompanyId:   1,
+		WarehouseId: warehouse.ID,
+		ProductId:   p.ID,
+		DimensionId: p.Dimensions[0].ID,
+		ThemeId:     p.Themes[0].ID,
+		Kind:        model.MovementReceipt,
+		Quantity:    2,
+	})
+	require.Nil(t, err)
+
+	edit := model.Product{
+		Id:          p.ID,
+		CompanyName: "Negin",
+		CompanyId:   1,
+		DesignCode:  p.DesignCode,
+		Colors:      []string{p.Themes[1].Color},
+		Sizes:       []string{p.Dimensions[0].Size, p.Dimensions[1].Size},
+	}
+	_, err = pRepo.EditProduct(edit)
+	require.Equal(t, derror.StatusText(derror.CarpetInStock), derror.StatusText(err))
+
+	edit.Colors = []string{p.Themes[0].Color, p.Themes[1].Color}
+	edit.Sizes = []string{p.Dimensions[1].Size}
+	_, err = pRepo.EditProduct(edit)
+	require.Equal(t, derror.StatusText(derror.CarpetInStock), derror.StatusText(err))
+
+	// Removing a size without pieces on hand
+	edit.Sizes = []string{p.Dimensions[0].Size}
+	_, err = pRepo.EditProduct(edit)
+	require.Nil(t, err)
+}
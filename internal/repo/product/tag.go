@@ -0,0 +1,132 @@
+package product
+
+import (
+	"fmt"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddTags add `tags` to every product of `productIds`, tags are normalized and created for `companyId` if not exist
+func (r *productRepo) AddTags(companyId uint, productIds []uint, tags []string) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_ids", fmt.Sprintf("%v", productIds)),
+			keyval.String("tags", fmt.Sprintf("%v", tags)),
+		}
+		logger.LogReqRes(r.logger, "tag.AddTags", err, commonKeyVal...)
+	}()
+
+	tags = model.NormalizeTags(tags)
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkCompanyProducts(tx, companyId, productIds); err != nil {
+			return err
+		}
+
+		schemaTags := make([]schema.Tag, len(tags))
+		for i, t := range tags {
+			schemaTags[i] = schema.Tag{CompanyId: companyId, Name: t}
+			if err := tx.Where(schemaTags[i]).FirstOrCreate(&schemaTags[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		var rows []map[string]interface{}
+		for _, productId := range productIds {
+			for _, t := range schemaTags {
+				rows = append(rows, map[string]interface{}{"product_id": productId, "tag_id": t.ID})
+			}
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		return tx.Table("tbl_product_tag").Clauses(clause.OnConflict{DoNothing: true}).Create(rows).Error
+	})
+
+	return derror.Wrap(err)
+}
+
+// RemoveTags remove `tags` from every product of `productIds`, tags are kept for `companyId`
+func (r *productRepo) RemoveTags(companyId uint, productIds []uint, tags []string) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_ids", fmt.Sprintf("%v", productIds)),
+			keyval.String("tags", fmt.Sprintf("%v", tags)),
+		}
+		logger.LogReqRes(r.logger, "tag.RemoveTags", err, commonKeyVal...)
+	}()
+
+	tags = model.NormalizeTags(tags)
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkCompanyProducts(tx, companyId, productIds); err != nil {
+			return err
+		}
+
+		if len(tags) == 0 {
+			return nil
+		}
+
+		return tx.Exec("DELETE FROM tbl_product_tag WHERE product_id IN ? AND tag_id IN "+
+			"(SELECT id FROM tbl_tag WHERE company_id = ? AND name IN ?)", productIds, companyId, tags).Error
+	})
+
+	return derror.Wrap(err)
+}
+
+// GetTags return tags of `companyId` with number of products that have them, most used first
+func (r *productRepo) GetTags(companyId uint) (tags []model.TagUsage, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("tags", fmt.Sprintf("%+v", tags)),
+		}
+		logger.LogReqRes(r.logger, "tag.GetTags", err, commonKeyVal...)
+	}()
+
+	tx := r.db.Table("tbl_tag t").
+		Select("t.name, COUNT(p.id) AS products").
+		Joins("LEFT JOIN tbl_product_tag pt ON pt.tag_id = t.id").
+		Joins("LEFT JOIN tbl_product p ON p.id = pt.product_id AND p.deleted_at IS NULL").
+		Where("t.company_id = ? AND t.deleted_at IS NULL", companyId).
+		Group("t.id, t.name").
+		Order("products DESC").Order("t.name ASC").
+		Scan(&tags)
+	if err := tx.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+
+	return tags, nil
+}
+
+// checkCompanyProducts return derror.ProductNotFound if a product of `productIds` not found for `companyId`
+func checkCompanyProducts(db *gorm.DB, companyId uint, productIds []uint) error {
+	var found []uint
+	tx := db.Model(&schema.Product{}).Where("company_id = ? AND id IN ?", companyId, productIds).Pluck("id", &found)
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	exist := make(map[uint]bool, len(found))
+	for _, id := range found {
+		exist[id] = true
+	}
+	for _, id := range productIds {
+		if !exist[id] {
+			return derror.New(derror.ProductNotFound, fmt.Sprintf("product id %v", id))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package product
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestProductRepo_AddTags(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1, err := pRepo.CreateProduct(model.Product{CompanyId: 1, DesignCode: "105", Colors: []string{"قرمز"}, Sizes: []string{"6"}})
+	require.Nil(t, err)
+	p2, err := pRepo.CreateProduct(model.Product{CompanyId: 1, DesignCode: "106", Colors: []string{"قرمز"}, Sizes: []string{"6"}})
+	require.Nil(t, err)
+
+	t.Run("product of another company", func(t *testing.T) {
+		err := pRepo.AddTags(2, []uint{p1.ID}, []string{"کلاسیک"})
+		require.Equal(t, derror.StatusText(derror.ProductNotFound), derror.StatusText(err))
+	})
+
+	require.Nil(t, pRepo.AddTags(1, []uint{p1.ID, p2.ID}, []string{"كلاسيك"}))
+	require.Nil(t, pRepo.AddTags(1, []uint{p1.ID}, []string{"کلاسیک ", "پرفروش"}))
+
+	gotP, err := pRepo.GetProductWithId(p1.ID)
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"کلاسیک", "پرفروش"}, schema.GetTagNames(gotP.Tags))
+
+	tags, err := pRepo.GetTags(1)
+	require.Nil(t, err)
+	require.Equal(t, []model.TagUsage{{Name: "کلاسیک", Products: 2}, {Name: "پرفروش", Products: 1}}, tags)
+
+	require.Nil(t, pRepo.RemoveTags(1, []uint{p1.ID, p2.ID}, []string{"كلاسيك"}))
+	tags, err = pRepo.GetTags(1)
+	require.Nil(t, err)
+	require.Equal(t, []model.TagUsage{{Name: "پرفروش", Products: 1}, {Name: "کلاسیک", Products: 0}}, tags)
+}
+
+func TestProductRepo_SearchProducts_Tags(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1, err := pRepo.CreateProduct(model.Product{CompanyId: 1, DesignCode: "105", Colors: []string{"قرمز"}, Sizes: []string{"6"}})
+	require.Nil(t, err)
+	p2, err := pRepo.CreateProduct(model.Product{CompanyId: 1, DesignCode: "106", Colors: []string{"قرمز"}, Sizes: []string{"6"}})
+	require.Nil(t, err)
+
+	require.Nil(t, pRepo.AddTags(1, []uint{p1.ID, p2.ID}, []string{"کلاسیک"}))
+	require.Nil(t, pRepo.AddTags(1, []uint{p2.ID}, []string{"پرفروش"}))
+
+	products, err := pRepo.SearchProducts(model.ProductFilter{CompanyId: 1, Tags: []string{"پرفروش", "كلاسيك"}})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(products))
+
+	products, err = pRepo.SearchProducts(model.ProductFilter{CompanyId: 1, Tags: []string{"پرفروش", "كلاسيك"}, TagMatch: model.TagMatchAll})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(products))
+	require.Equal(t, p2.ID, products[0].ID)
+}
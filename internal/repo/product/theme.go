@@ -1,11 +1,13 @@
 package product
 
 import (
+	"errors"
 	"fmt"
 	"github.com/seed95/product-service/internal/derror"
 	"github.com/seed95/product-service/internal/repo/product/schema"
 	"github.com/seed95/product-service/pkg/logger"
 	"github.com/seed95/product-service/pkg/logger/keyval"
+	"github.com/seed95/product-service/pkg/normalize"
 	"gorm.io/gorm"
 )
 
@@ -19,6 +21,7 @@ type (
 		InsertThemesWithColor(tx *gorm.DB, productId uint, colors []string) ([]schema.Theme, error)
 		DeleteThemesWithId(tx *gorm.DB, productId uint, themes []schema.Theme) error
 		EditThemes(tx *gorm.DB, productId uint, editedThemes []schema.Theme) ([]schema.Theme, error)
+		RenameTheme(tx *gorm.DB, productId, themeId uint, color string) (*schema.Theme, error)
 	}
 )
 
@@ -46,7 +49,8 @@ func (r *themeRepo) GetThemesWithProductId(db *gorm.DB, productId uint) (themes
 	return themes, nil
 }
 
-// InsertThemesWithColor if a color for `productId` is duplicate, no add any themes
+// InsertThemesWithColor if a color for `productId` is duplicate, no add any themes.
+// a removed color of product is restored with its id instead of a new theme, so its carpets keep their SKU
 // support roll back
 func (r *themeRepo) InsertThemesWithColor(tx *gorm.DB, productId uint, colors []string) (themes []schema.Theme, err error) {
 	// Log request response
@@ -63,17 +67,60 @@ func (r *themeRepo) InsertThemesWithColor(tx *gorm.DB, productId uint, colors []
 		return nil, derror.InvalidColor
 	}
 
+	seen := make(map[string]bool, len(colors))
+	for _, v := range colors {
+		if seen[v] {
+			return nil, derror.New(derror.InvalidColor, fmt.Sprintf("color %v is repeated", v))
+		}
+		seen[v] = true
+	}
+
+	// A removed color of product is restored with its id
+	var removed []schema.Theme
+	result := tx.Unscoped().Where("product_id = ? AND color IN ? AND deleted_at IS NOT NULL", productId, colors).Find(&removed)
+	if err := result.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	restored := make(map[string]schema.Theme, len(removed))
+	for _, t := range removed {
+		restored[t.Color] = t
+	}
+
 	themes = make([]schema.Theme, len(colors))
-	for i, c := range colors {
-		themes[i] = schema.Theme{
+	var newIndexes []int
+	var newThemes []schema.Theme
+	for i, v := range colors {
+		if t, ok := restored[v]; ok {
+			themes[i] = t
+			continue
+		}
+		newIndexes = append(newIndexes, i)
+		newThemes = append(newThemes, schema.Theme{
 			ProductId: productId,
-			Color:     c,
+			Color:     v,
+		})
+	}
+
+	for i := range themes {
+		if themes[i].ID == 0 {
+			continue
 		}
+		result = tx.Unscoped().Model(&themes[i]).Updates(map[string]interface{}{"deleted_at": nil})
+		if err := result.Error; err != nil {
+			return nil, derror.New(derror.InternalServer, err.Error())
+		}
+		themes[i].DeletedAt = gorm.DeletedAt{}
 	}
 
-	if err := tx.Create(&themes).Error; err != nil {
-		return nil, derror.New(derror.InternalServer, err.Error())
+	if len(newThemes) != 0 {
+		if err := tx.Create(&newThemes).Error; err != nil {
+			return nil, derror.New(derror.InternalServer, err.Error())
+		}
+		for j, i := range newIndexes {
+			themes[i] = newThemes[j]
+		}
 	}
+
 	return themes, nil
 }
 
@@ -131,7 +178,7 @@ func (r *themeRepo) EditThemes(tx *gorm.DB, productId uint, editedThemes []schem
 OriginalLoop:
 	for _, ot := range originalThemes {
 		for _, et := range editedThemes {
-			if ot.Color == et.Color {
+			if normalize.Key(ot.Color) == normalize.Key(et.Color) {
 				themes = append(themes, ot)
 				continue OriginalLoop
 			}
@@ -144,7 +191,7 @@ OriginalLoop:
 EditedLoop:
 	for _, et := range editedThemes {
 		for _, ot := range originalThemes {
-			if ot.Color == et.Color {
+			if normalize.Key(ot.Color) == normalize.Key(et.Color) {
 				continue EditedLoop
 			}
 		}
@@ -168,3 +215,181 @@ EditedLoop:
 
 	return themes, nil
 }
+
+// RenameTheme change color of `themeId` of `productId` to `color` and keep its id, so carpets of theme keep their
+// SKU, stock and prices. if `color` is another theme of product, even a removed one, return derror.InvalidColor
+func (r *themeRepo) RenameTheme(tx *gorm.DB, productId, themeId uint, color string) (theme *schema.Theme, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("theme_id", fmt.Sprintf("%v", themeId)),
+			keyval.String("color", color),
+			keyval.String("theme", fmt.Sprintf("%+v", theme)),
+		}
+		logger.LogReqRes(r.logger, "theme.RenameTheme", err, commonKeyVal...)
+	}()
+
+	if color == "" {
+		return nil, derror.InvalidColor
+	}
+
+	theme = &schema.Theme{}
+	if err := tx.Where("product_id = ?", productId).First(theme, themeId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, derror.ThemeNotFound
+		}
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	if theme.Color == color {
+		return theme, nil
+	}
+
+	// A removed theme keeps its color in theme_unique_id
+	other := &schema.Theme{}
+	result := tx.Unscoped().Where("product_id = ? AND color = ?", productId, color).Limit(1).Find(other)
+	if err := result.Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	if result.RowsAffected != 0 {
+		if other.DeletedAt.Valid {
+			return nil, derror.New(derror.InvalidColor, fmt.Sprintf("color %v is a removed color of product, add it to restore it", color))
+		}
+		return nil, derror.New(derror.InvalidColor, fmt.Sprintf("color %v exists", color))
+	}
+
+	if err := tx.Model(theme).Update("color", color).Error; err != nil {
+		return nil, derror.New(derror.InternalServer, err.Error())
+	}
+	return theme, nil
+}
+
+// AddThemes add `colors` to product `productId` of `companyId` and return updated product,
+// if a color already exists for product return derror.InvalidColor
+func (r *productRepo) AddThemes(companyId, productId uint, colors []string) (product *schema.Product, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("colors", fmt.Sprintf("%v", colors)),
+			keyval.String("product", fmt.Sprintf("%+v", product)),
+		}
+		logger.LogReqRes(r.logger, "theme.AddThemes", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, companyId, productId); err != nil {
+			return err
+		}
+
+		themes, err := r.theme.GetThemesWithProductId(tx, productId)
+		if err != nil {
+			return err
+		}
+		for _, c := range colors {
+			if themesHaveColor(themes, c) {
+				return derror.New(derror.InvalidColor, fmt.Sprintf("color %v exists", c))
+			}
+		}
+
+		_, err = r.theme.InsertThemesWithColor(tx, productId, colors)
+		return err
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return r.GetProductWithId(productId)
+}
+
+// RemoveThemes delete `themeIds` of product `productId` of `companyId` and return updated product.
+// a product keeps at least one color and a color with pieces on hand can't be removed,
+// prices and exclusions of carpets of removed colors are deleted
+func (r *productRepo) RemoveThemes(companyId, productId uint, themeIds []uint) (product *schema.Product, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("theme_ids", fmt.Sprintf("%v", themeIds)),
+			keyval.String("product", fmt.Sprintf("%+v", product)),
+		}
+		logger.LogReqRes(r.logger, "theme.RemoveThemes", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, companyId, productId); err != nil {
+			return err
+		}
+
+		themes, err := r.theme.GetThemesWithProductId(tx, productId)
+		if err != nil {
+			return err
+		}
+
+		removed := make([]schema.Theme, 0, len(themeIds))
+	IdLoop:
+		for _, id := range themeIds {
+			for _, t := range themes {
+				if t.ID == id {
+					removed = append(removed, t)
+					continue IdLoop
+				}
+			}
+			return derror.New(derror.ThemeNotFound, fmt.Sprintf("theme id %v", id))
+		}
+		if len(removed) == len(themes) {
+			return derror.New(derror.InvalidTheme, "no color is left")
+		}
+
+		if err := checkRemovedCarpetsNotInStock(tx, productId, nil, removed); err != nil {
+			return err
+		}
+		if err := r.theme.DeleteThemesWithId(tx, productId, removed); err != nil {
+			return err
+		}
+
+		// Carpets of deleted colors lose their prices and exclusions
+		if err := deleteDetachedPrices(tx, productId); err != nil {
+			return err
+		}
+		return deleteDetachedExclusions(tx, productId)
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return r.GetProductWithId(productId)
+}
+
+// RenameTheme change color of theme `themeId` of product `productId` of `companyId` in place and return updated product
+func (r *productRepo) RenameTheme(companyId, productId, themeId uint, color string) (product *schema.Product, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("product_id", fmt.Sprintf("%v", productId)),
+			keyval.String("theme_id", fmt.Sprintf("%v", themeId)),
+			keyval.String("color", color),
+			keyval.String("product", fmt.Sprintf("%+v", product)),
+		}
+		logger.LogReqRes(r.logger, "theme.RenameTheme", err, commonKeyVal...)
+	}()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, companyId, productId); err != nil {
+			return err
+		}
+		_, err := r.theme.RenameTheme(tx, productId, themeId, color)
+		return err
+	})
+
+	if err != nil {
+		return nil, derror.Wrap(err)
+	}
+
+	return r.GetProductWithId(productId)
+}
@@ -478,3 +478,147 @@ func TestDimensionRepo_EditThemes_NotChange(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, len(themes), len(gotThemes))
 }
+
+func TestProductRepo_AddRemoveThemes(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := CreateProduct1(pRepo, t)
+
+	product, err := pRepo.AddThemes(1, p1.ID, []string{"سبز"})
+	require.Nil(t, err)
+	require.Equal(t, 3, len(product.Themes))
+
+	// Existing color
+	_, err = pRepo.AddThemes(1, p1.ID, []string{"قرمز"})
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.InvalidColor), derror.StatusText(err))
+
+	// Product of another company
+	_, err = pRepo.AddThemes(2, p1.ID, []string{"زرد"})
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.ProductNotFound), derror.StatusText(err))
+
+	product, err = pRepo.RemoveThemes(1, p1.ID, []uint{p1.Themes[0].ID, p1.Themes[1].ID})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(product.Themes))
+	require.Equal(t, "سبز", product.Themes[0].Color)
+
+	// Last color
+	_, err = pRepo.RemoveThemes(1, p1.ID, []uint{product.Themes[0].ID})
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.InvalidTheme), derror.StatusText(err))
+
+	// Removed color
+	_, err = pRepo.RemoveThemes(1, p1.ID, []uint{p1.Themes[0].ID})
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.ThemeNotFound), derror.StatusText(err))
+}
+
+func TestProductRepo_RenameTheme(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := CreateProduct1(pRepo, t)
+	red := p1.Themes[0]
+
+	product, err := pRepo.RenameTheme(1, p1.ID, red.ID, "قرمز لاکی")
+	require.Nil(t, err)
+	require.Equal(t, 2, len(product.Themes))
+	for _, theme := range product.Themes {
+		if theme.ID == red.ID {
+			require.Equal(t, "قرمز لاکی", theme.Color)
+		}
+	}
+
+	// Color of another theme
+	_, err = pRepo.RenameTheme(1, p1.ID, red.ID, p1.Themes[1].Color)
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.InvalidColor), derror.StatusText(err))
+
+	// Color of a removed theme
+	_, err = pRepo.AddThemes(1, p1.ID, []string{"سبز"})
+	require.Nil(t, err)
+	_, err = pRepo.RemoveThemes(1, p1.ID, []uint{p1.Themes[1].ID})
+	require.Nil(t, err)
+	_, err = pRepo.RenameTheme(1, p1.ID, red.ID, p1.Themes[1].Color)
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.InvalidColor), derror.StatusText(err))
+
+	_, err = pRepo.RenameTheme(1, p1.ID, 1000, "زرد")
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.ThemeNotFound), derror.StatusText(err))
+}
+
+func TestThemeRepo_EditThemes_RestoreRemovedColor(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create product
+	gotP1 := CreateProduct1(pRepo, t)
+	removed := gotP1.Themes[1]
+
+	// Theme repo
+	tRepo := NewThemeRepoMock()
+
+	// Remove second color
+	err = pRepo.db.Transaction(func(tx *gorm.DB) error {
+		_, err = tRepo.EditThemes(tx, gotP1.ID, []schema.Theme{{Color: gotP1.Themes[0].Color}})
+		return err
+	})
+	require.Nil(t, err)
+
+	// Add it back with a new color
+	var gotThemes []schema.Theme
+	err = pRepo.db.Transaction(func(tx *gorm.DB) error {
+		gotThemes, err = tRepo.EditThemes(tx, gotP1.ID, []schema.Theme{{Color: gotP1.Themes[0].Color}, {Color: removed.Color}, {Color: "سبز"}})
+		return err
+	})
+	require.Nil(t, err)
+	require.Equal(t, 3, len(gotThemes))
+	require.Equal(t, removed.ID, gotThemes[1].ID)
+	require.Equal(t, removed.Color, gotThemes[1].Color)
+	require.False(t, gotThemes[1].DeletedAt.Valid)
+
+	gotThemes, err = tRepo.GetThemesWithProductId(pRepo.db, gotP1.ID)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(gotThemes))
+	require.Equal(t, removed.ID, gotThemes[1].ID)
+
+	// Carpets of restored color are back
+	carpets, err := pRepo.GetAllCarpetWithProductId(1, gotP1.ID)
+	require.Nil(t, err)
+	require.Equal(t, 6, len(carpets))
+}
+
+func TestThemeRepo_InsertThemesWithColor_Repeated(t *testing.T) {
+	// NewProduct repo
+	pRepo, err := NewProductRepoMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create product
+	gotP1 := CreateProduct1(pRepo, t)
+
+	// Theme repo
+	tRepo := NewThemeRepoMock()
+
+	var gotThemes []schema.Theme
+	err = pRepo.db.Transaction(func(tx *gorm.DB) error {
+		gotThemes, err = tRepo.InsertThemesWithColor(tx, gotP1.ID, []string{"سبز", "سبز"})
+		return err
+	})
+	require.Nil(t, gotThemes)
+	require.NotNil(t, err)
+	require.Equal(t, derror.StatusText(derror.InvalidColor), derror.StatusText(err))
+}
@@ -3,6 +3,8 @@ package repo
 import (
 	"github.com/seed95/product-service/internal/model"
 	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/seed95/product-service/pkg/sku"
+	"time"
 )
 
 type (
@@ -12,11 +14,102 @@ type (
 		DeleteProduct(productId uint) error
 		EditProduct(product model.Product) (*schema.Product, error)
 		GetAllProducts(companyId uint) ([]schema.Product, error)
+		SearchProducts(filter model.ProductFilter) ([]schema.Product, error)
+		ChangeProductStatus(companyId, productId uint, status string) (*schema.Product, error)
+		CloneProduct(clone model.ProductClone) (*schema.Product, error)
+		BulkEditProducts(edit model.BulkEdit) ([]model.BulkEditResult, error)
+		GetProductWithDesignCode(companyId uint, designCode string) (*schema.Product, error)
+		RenameDesignCode(companyId, productId uint, designCode string) (*schema.Product, error)
+		GetProductAliases(companyId, productId uint) ([]schema.ProductAlias, error)
+		MergeProducts(companyId, sourceId, targetId uint) (*schema.Product, error)
+		GetProductHistory(companyId, productId uint) ([]schema.ProductHistory, error)
+		ImportProducts(companyId uint, rows []model.ImportRow, chunkSize int, dryRun bool) ([]model.ImportResult, error)
+		ExportProducts(filter model.ExportFilter, fn func(model.ExportProduct) error) (uint, error)
+		ExportCarpets(filter model.ExportFilter, fn func(model.ExportCarpet) error) (uint, error)
+		DumpCatalog(companyId uint) (*model.CatalogDump, error)
+		RestoreCatalog(companyId uint, dump model.CatalogDump) (*model.RestoreResult, error)
 		CarpetRepo
+		ThemeDimensionRepo
+		StandardSizeRepo
+		AttributeRepo
+		CategoryRepo
+		TagRepo
+		PriceRepo
+		StockRepo
 	}
 
 	CarpetRepo interface {
 		GetAllCarpet(companyId uint) ([]model.Carpet, error)
 		GetAllCarpetWithProductId(companyId, productId uint) ([]model.Carpet, error)
+		SearchCarpets(filter model.ProductFilter, page model.Page) ([]model.Carpet, int64, error)
+		GetCarpet(filter model.ProductFilter, carpetSku sku.SKU) (*model.Carpet, error)
+		ExcludeCarpet(companyId uint, exclusion model.CarpetExclusion) (*model.CarpetExclusion, error)
+		IncludeCarpet(companyId, productId, dimensionId, themeId uint) error
+		GetCarpetExclusions(companyId, productId uint) ([]model.CarpetExclusion, error)
+	}
+
+	ThemeDimensionRepo interface {
+		AddThemes(companyId, productId uint, colors []string) (*schema.Product, error)
+		RemoveThemes(companyId, productId uint, themeIds []uint) (*schema.Product, error)
+		AddDimensions(companyId, productId uint, sizes []string) (*schema.Product, error)
+		RemoveDimensions(companyId, productId uint, dimensionIds []uint) (*schema.Product, error)
+		RenameTheme(companyId, productId, themeId uint, color string) (*schema.Product, error)
+		RenameDimension(companyId, productId, dimensionId uint, size string) (*schema.Product, error)
+	}
+
+	StandardSizeRepo interface {
+		CreateStandardSize(size model.StandardSize) (*schema.StandardSize, error)
+		GetStandardSizes(companyId uint) ([]schema.StandardSize, error)
+		EditStandardSize(size model.StandardSize) (*schema.StandardSize, error)
+		DeleteStandardSize(companyId, standardSizeId uint) error
+		GetStandardSizeProducts(companyId uint) ([]model.StandardSizeProducts, error)
+	}
+
+	AttributeRepo interface {
+		CreateAttributeDefinition(definition model.AttributeDefinition) (*schema.AttributeDefinition, error)
+		GetAttributeDefinitions(companyId uint) ([]schema.AttributeDefinition, error)
+		EditAttributeDefinition(definition model.AttributeDefinition) (*schema.AttributeDefinition, error)
+		DeleteAttributeDefinition(companyId, attributeId uint) error
+	}
+
+	CategoryRepo interface {
+		CreateCategory(category model.Category) (*schema.Category, error)
+		GetCategories(companyId uint) ([]schema.Category, error)
+		MoveCategory(companyId, categoryId uint, parentId *uint) (*schema.Category, error)
+		DeleteCategory(companyId, categoryId uint) error
+		GetCategoryProducts(companyId, categoryId uint) ([]schema.Product, error)
+	}
+
+	TagRepo interface {
+		AddTags(companyId uint, productIds []uint, tags []string) error
+		RemoveTags(companyId uint, productIds []uint, tags []string) error
+		GetTags(companyId uint) ([]model.TagUsage, error)
+	}
+
+	PriceRepo interface {
+		CreatePriceList(list model.PriceList) (*schema.PriceList, error)
+		GetPriceLists(companyId uint) ([]schema.PriceList, error)
+		DeletePriceList(companyId, priceListId uint) error
+		AddPrice(companyId uint, price model.Price) (*schema.Price, error)
+		GetPrices(companyId, priceListId, productId uint) ([]schema.Price, error)
+		DeletePrice(companyId, priceId uint) error
+		ResolvePrice(companyId, priceListId uint, carpet model.Carpet, at time.Time) (*model.EffectivePrice, error)
+		AddPriceAdjustment(companyId uint, adjustment model.PriceAdjustment) (*schema.PriceAdjustment, error)
+		GetPriceAdjustments(companyId, priceListId uint) ([]schema.PriceAdjustment, error)
+		DeletePriceAdjustment(companyId, adjustmentId uint) error
+		QuotePrice(companyId uint, quote model.PriceQuote, at time.Time) (*model.EffectivePrice, error)
+	}
+
+	StockRepo interface {
+		CreateWarehouse(warehouse model.Warehouse) (*schema.Warehouse, error)
+		GetWarehouses(companyId uint) ([]schema.Warehouse, error)
+		DeleteWarehouse(companyId, warehouseId uint) error
+		RecordStockMovement(movement model.StockMovement) ([]schema.StockMovement, error)
+		GetStockMovements(filter model.MovementFilter, page model.Page) ([]schema.StockMovement, int64, error)
+		GetStock(companyId, warehouseId, productId uint, page model.Page) ([]model.StockLevel, int64, error)
+		ReserveStock(reservation model.Reservation) (*schema.Reservation, error)
+		ReleaseReservation(companyId, reservationId uint) (*schema.Reservation, error)
+		GetReservations(filter model.ReservationFilter, page model.Page) ([]schema.Reservation, int64, error)
+		ReleaseExpiredReservations(at time.Time) (int64, error)
 	}
 )
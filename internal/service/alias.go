@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+// GetProductWithDesignCode return product of company with design code of request, an old code of a product is also found
+func (g *gateway) GetProductWithDesignCode(ctx context.Context, req *api.GetProductWithDesignCodeRequest) (res *api.GetProductResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetProductWithDesignCode", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	designCode := model.NormalizeText(req.DesignCode)
+	if designCode == "" {
+		return nil, derror.New(derror.InvalidProduct, "empty design code")
+	}
+
+	schemaProduct, err := g.product.GetProductWithDesignCode(req.CompanyId, designCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if schemaProduct.Status == model.StatusDraft && !canSeeDrafts(ctx) {
+		return nil, derror.ProductNotFound
+	}
+
+	res = &api.GetProductResponse{}
+	res.Product = *api.ProductSchemaToApi(*schemaProduct)
+	return res, nil
+}
+
+// RenameDesignCode change design code of a product, lookups by old code still find the product
+func (g *gateway) RenameDesignCode(ctx context.Context, req *api.RenameDesignCodeRequest) (res *api.EditProductResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.RenameDesignCode", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+	if req.ProductId == 0 {
+		return nil, derror.InvalidProduct
+	}
+
+	designCode := model.NormalizeText(req.DesignCode)
+	if designCode == "" {
+		return nil, derror.New(derror.InvalidProduct, "empty design code")
+	}
+
+	product, err := g.product.RenameDesignCode(req.CompanyId, req.ProductId, designCode)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.EditProductResponse{}
+	res.Product = *api.ProductSchemaToApi(*product)
+	return res, nil
+}
+
+// GetProductAliases return old design codes of a product
+func (g *gateway) GetProductAliases(ctx context.Context, req *api.GetProductAliasesRequest) (res *api.GetProductAliasesResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetProductAliases", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+	if req.ProductId == 0 {
+		return nil, derror.InvalidProduct
+	}
+
+	aliases, err := g.product.GetProductAliases(req.CompanyId, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetProductAliasesResponse{Aliases: make([]api.ProductAlias, len(aliases))}
+	for i, a := range aliases {
+		res.Aliases[i] = *api.ProductAliasSchemaToApi(a)
+	}
+	return res, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"github.com/seed95/product-service/pkg/unique"
+)
+
+func (g *gateway) CreateAttributeDefinition(ctx context.Context, req *api.CreateAttributeDefinitionRequest) (res *api.CreateAttributeDefinitionResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.CreateAttributeDefinition", err, commonKeyVal...)
+	}()
+
+	modelDefinition := api.AttributeDefinitionApiToModel(req.AttributeDefinition)
+	if err := attributeDefinitionIsValid(*modelDefinition); err != nil {
+		return nil, err
+	}
+
+	if modelDefinition.Id != 0 {
+		return nil, derror.New(derror.InvalidAttribute, "invalid attribute id")
+	}
+
+	definition, err := g.product.CreateAttributeDefinition(*modelDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.CreateAttributeDefinitionResponse{}
+	res.AttributeDefinition = *api.AttributeDefinitionSchemaToApi(*definition)
+	return res, nil
+}
+
+func (g *gateway) GetAttributeDefinitions(ctx context.Context, companyId uint) (res *api.GetAttributeDefinitionsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetAttributeDefinitions", err, commonKeyVal...)
+	}()
+
+	if companyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	definitions, err := g.product.GetAttributeDefinitions(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetAttributeDefinitionsResponse{}
+	res.AttributeDefinitions = make([]api.AttributeDefinition, len(definitions))
+	for i, d := range definitions {
+		res.AttributeDefinitions[i] = *api.AttributeDefinitionSchemaToApi(d)
+	}
+	return res, nil
+}
+
+func (g *gateway) EditAttributeDefinition(ctx context.Context, req *api.EditAttributeDefinitionRequest) (res *api.EditAttributeDefinitionResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.EditAttributeDefinition", err, commonKeyVal...)
+	}()
+
+	modelDefinition := api.AttributeDefinitionApiToModel(req.AttributeDefinition)
+	if err := attributeDefinitionIsValid(*modelDefinition); err != nil {
+		return nil, err
+	}
+
+	if modelDefinition.Id == 0 {
+		return nil, derror.New(derror.InvalidAttribute, "invalid attribute id")
+	}
+
+	definition, err := g.product.EditAttributeDefinition(*modelDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.EditAttributeDefinitionResponse{}
+	res.AttributeDefinition = *api.AttributeDefinitionSchemaToApi(*definition)
+	return res, nil
+}
+
+func (g *gateway) DeleteAttributeDefinition(ctx context.Context, req *api.DeleteAttributeDefinitionRequest) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+		}
+		kitlog.LogReqRes(g.logger, "service.DeleteAttributeDefinition", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if req.AttributeId == 0 {
+		return derror.InvalidAttribute
+	}
+
+	return g.product.DeleteAttributeDefinition(req.CompanyId, req.AttributeId)
+}
+
+func attributeDefinitionIsValid(d model.AttributeDefinition) error {
+
+	if d.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if d.Name == "" {
+		return derror.New(derror.InvalidAttribute, "empty name")
+	}
+
+	if !d.TypeIsValid() {
+		return derror.New(derror.InvalidAttribute, "invalid type")
+	}
+
+	if d.Type != model.AttributeEnum && len(d.Options) != 0 {
+		return derror.New(derror.InvalidAttribute, "options of non enum attribute")
+	}
+
+	// Check unique option
+	if !unique.StringsAreUnique(d.Options) {
+		return derror.New(derror.InvalidAttribute, "not unique option")
+	}
+
+	return nil
+}
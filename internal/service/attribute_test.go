@@ -0,0 +1,62 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAttributeDefinitionIsValid(t *testing.T) {
+
+	tests := []struct {
+		Name       string
+		Definition model.AttributeDefinition
+		Valid      bool
+	}{
+		{
+			Name:       "Ok",
+			Definition: model.AttributeDefinition{CompanyId: 1, Name: "shaneh", Type: model.AttributeNumber},
+			Valid:      true,
+		},
+		{
+			Name:       "Enum",
+			Definition: model.AttributeDefinition{CompanyId: 1, Name: "weave", Type: model.AttributeEnum, Options: []string{"hand", "machine"}},
+			Valid:      true,
+		},
+		{
+			Name:       "EnumWithoutOption",
+			Definition: model.AttributeDefinition{CompanyId: 1, Name: "weave", Type: model.AttributeEnum},
+		},
+		{
+			Name:       "DuplicateOption",
+			Definition: model.AttributeDefinition{CompanyId: 1, Name: "weave", Type: model.AttributeEnum, Options: []string{"hand", "hand"}},
+		},
+		{
+			Name:       "OptionOfString",
+			Definition: model.AttributeDefinition{CompanyId: 1, Name: "material", Type: model.AttributeString, Options: []string{"wool"}},
+		},
+		{
+			Name:       "InvalidType",
+			Definition: model.AttributeDefinition{CompanyId: 1, Name: "material", Type: "text"},
+		},
+		{
+			Name:       "EmptyName",
+			Definition: model.AttributeDefinition{CompanyId: 1, Type: model.AttributeString},
+		},
+		{
+			Name:       "ZeroCompany",
+			Definition: model.AttributeDefinition{Name: "material", Type: model.AttributeString},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := attributeDefinitionIsValid(tt.Definition)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+// BulkEditProducts add or remove colors and sizes and set attributes of products that match request,
+// every product is edited alone and a dry run report what edit would do without saving
+func (g *gateway) BulkEditProducts(ctx context.Context, req *api.BulkEditProductsRequest) (res *api.BulkEditProductsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.BulkEditProducts", err, commonKeyVal...)
+	}()
+
+	filter, err := searchFilter(ctx, req.SearchProductsRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	edit := model.BulkEdit{
+		Filter:       *filter,
+		AddColors:    normalizeList(req.AddColors),
+		RemoveColors: normalizeList(req.RemoveColors),
+		AddSizes:     normalizeList(req.AddSizes),
+		RemoveSizes:  normalizeList(req.RemoveSizes),
+		Attributes:   req.Attributes,
+		DryRun:       req.DryRun,
+	}
+	if err := bulkEditIsValid(edit); err != nil {
+		return nil, err
+	}
+
+	results, err := g.product.BulkEditProducts(edit)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.BulkEditProductsResponse{DryRun: req.DryRun}
+	res.Products = make([]api.BulkEditResult, len(results))
+	for i, r := range results {
+		res.Products[i] = *api.BulkEditResultModelToApi(r)
+		switch {
+		case r.Error != "":
+			res.Failed++
+		case r.Changed():
+			res.Changed++
+		default:
+			res.Unchanged++
+		}
+	}
+	return res, nil
+}
+
+func bulkEditIsValid(edit model.BulkEdit) error {
+	if edit.Filter.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if len(edit.AddColors) == 0 && len(edit.RemoveColors) == 0 && len(edit.AddSizes) == 0 &&
+		len(edit.RemoveSizes) == 0 && len(edit.Attributes) == 0 {
+		return derror.New(derror.InvalidProduct, "no change")
+	}
+
+	for _, c := range append(edit.AddColors, edit.RemoveColors...) {
+		if c == "" {
+			return derror.New(derror.InvalidColor, "empty color")
+		}
+	}
+	for _, c := range edit.AddColors {
+		if containsString(edit.RemoveColors, c) {
+			return derror.New(derror.InvalidColor, fmt.Sprintf("color %v is added and removed", c))
+		}
+	}
+
+	for _, s := range append(edit.AddSizes, edit.RemoveSizes...) {
+		if s == "" {
+			return derror.New(derror.InvalidDimension, "empty size")
+		}
+	}
+	for _, s := range edit.AddSizes {
+		if containsString(edit.RemoveSizes, s) {
+			return derror.New(derror.InvalidDimension, fmt.Sprintf("size %v is added and removed", s))
+		}
+	}
+
+	return nil
+}
+
+// normalizeList return `list` with every item in normalized form
+func normalizeList(list []string) []string {
+	if len(list) == 0 {
+		return nil
+	}
+	result := make([]string, len(list))
+	for i, s := range list {
+		result[i] = model.NormalizeText(s)
+	}
+	return result
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
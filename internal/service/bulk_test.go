@@ -0,0 +1,64 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBulkEditIsValid(t *testing.T) {
+
+	company := model.ProductFilter{CompanyId: 1}
+
+	tests := []struct {
+		Name  string
+		Edit  model.BulkEdit
+		Valid bool
+	}{
+		{
+			Name:  "AddColor",
+			Edit:  model.BulkEdit{Filter: company, AddColors: []string{"سبز"}},
+			Valid: true,
+		},
+		{
+			Name:  "RemoveSizeAndSetAttribute",
+			Edit:  model.BulkEdit{Filter: company, RemoveSizes: []string{"6"}, Attributes: map[string]string{"material": ""}},
+			Valid: true,
+		},
+		{
+			Name: "ZeroCompany",
+			Edit: model.BulkEdit{AddColors: []string{"سبز"}},
+		},
+		{
+			Name: "NoChange",
+			Edit: model.BulkEdit{Filter: company, DryRun: true},
+		},
+		{
+			Name: "EmptyColor",
+			Edit: model.BulkEdit{Filter: company, RemoveColors: []string{""}},
+		},
+		{
+			Name: "EmptySize",
+			Edit: model.BulkEdit{Filter: company, AddSizes: []string{"6", ""}},
+		},
+		{
+			Name: "AddedAndRemovedColor",
+			Edit: model.BulkEdit{Filter: company, AddColors: []string{"سبز"}, RemoveColors: []string{"سبز"}},
+		},
+		{
+			Name: "AddedAndRemovedSize",
+			Edit: model.BulkEdit{Filter: company, AddSizes: []string{"6"}, RemoveSizes: []string{"6"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := bulkEditIsValid(tt.Edit)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"github.com/seed95/product-service/pkg/sku"
+)
+
+// GetCarpets return a page of carpets of company products that match filters of request
+func (g *gateway) GetCarpets(ctx context.Context, req *api.GetCarpetsRequest) (res *api.GetCarpetsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetCarpets", err, commonKeyVal...)
+	}()
+
+	filter, err := searchFilter(ctx, req.SearchProductsRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.searchCarpets(*filter, api.PageRequestToModel(req.PageRequest))
+}
+
+// GetProductCarpets return a page of carpets of a product
+func (g *gateway) GetProductCarpets(ctx context.Context, req *api.GetProductCarpetsRequest) (res *api.GetCarpetsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetProductCarpets", err, commonKeyVal...)
+	}()
+
+	if req.ProductId == 0 {
+		return nil, derror.InvalidProduct
+	}
+
+	filter, err := searchFilter(ctx, api.SearchProductsRequest{CompanyId: req.CompanyId, ProductIds: []uint{req.ProductId}})
+	if err != nil {
+		return nil, err
+	}
+
+	return g.searchCarpets(*filter, api.PageRequestToModel(req.PageRequest))
+}
+
+// GetCarpet return a carpet of company with its sku id
+func (g *gateway) GetCarpet(ctx context.Context, req *api.GetCarpetRequest) (res *api.GetCarpetResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetCarpet", err, commonKeyVal...)
+	}()
+
+	carpet, err := g.companyCarpet(ctx, req.CompanyId, req.CarpetId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetCarpetResponse{}
+	res.Carpet = *api.CarpetModelToApi(*carpet)
+	return res, nil
+}
+
+func (g *gateway) searchCarpets(filter model.ProductFilter, page model.Page) (*api.GetCarpetsResponse, error) {
+	carpets, total, err := g.product.SearchCarpets(filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &api.GetCarpetsResponse{Total: total, Page: page.Number, PageSize: page.Size}
+	res.Carpets = make([]api.Carpet, len(carpets))
+	for i, c := range carpets {
+		res.Carpets[i] = *api.CarpetModelToApi(c)
+	}
+	return res, nil
+}
+
+// companyCarpet return carpet of company with sku `carpetId` if caller can see its product
+func (g *gateway) companyCarpet(ctx context.Context, companyId uint, carpetId string) (*model.Carpet, error) {
+	carpetSku, err := carpetSkuOfCompany(companyId, carpetId)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := searchFilter(ctx, api.SearchProductsRequest{CompanyId: companyId})
+	if err != nil {
+		return nil, err
+	}
+
+	return g.product.GetCarpet(*filter, carpetSku)
+}
+
+// carpetSkuOfCompany decode `carpetId`, sku with prefix of another company is not found
+func carpetSkuOfCompany(companyId uint, carpetId string) (sku.SKU, error) {
+	carpetSku, err := sku.Decode(carpetId)
+	if err != nil {
+		return sku.SKU{}, derror.New(derror.InvalidCarpet, err.Error())
+	}
+
+	if carpetSku.CompanyId != 0 && carpetSku.CompanyId != companyId {
+		return sku.SKU{}, derror.CarpetNotFound
+	}
+
+	return carpetSku, nil
+}
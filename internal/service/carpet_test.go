@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSearchFilter(t *testing.T) {
+	t.Run("viewer", func(t *testing.T) {
+		filter, err := searchFilter(context.Background(), api.SearchProductsRequest{CompanyId: 1})
+		require.Nil(t, err)
+		require.True(t, filter.HideDrafts)
+	})
+
+	t.Run("editor", func(t *testing.T) {
+		filter, err := searchFilter(WithRole(context.Background(), model.RoleEditor), api.SearchProductsRequest{CompanyId: 1})
+		require.Nil(t, err)
+		require.False(t, filter.HideDrafts)
+	})
+
+	t.Run("zero company", func(t *testing.T) {
+		_, err := searchFilter(context.Background(), api.SearchProductsRequest{})
+		require.Equal(t, derror.InvalidCompany, err)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		_, err := searchFilter(context.Background(), api.SearchProductsRequest{CompanyId: 1, Statuses: []string{"deleted"}})
+		require.Equal(t, derror.StatusText(derror.InvalidStatus), derror.StatusText(err))
+	})
+}
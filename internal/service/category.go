@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+func (g *gateway) CreateCategory(ctx context.Context, req *api.CreateCategoryRequest) (res *api.CreateCategoryResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.CreateCategory", err, commonKeyVal...)
+	}()
+
+	modelCategory := api.CategoryApiToModel(req.Category)
+	if err := categoryIsValid(*modelCategory); err != nil {
+		return nil, err
+	}
+
+	if modelCategory.Id != 0 {
+		return nil, derror.New(derror.InvalidCategory, "invalid category id")
+	}
+
+	category, err := g.product.CreateCategory(*modelCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.CreateCategoryResponse{}
+	res.Category = *api.CategorySchemaToApi(*category)
+	return res, nil
+}
+
+func (g *gateway) GetCategories(ctx context.Context, companyId uint) (res *api.GetCategoriesResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetCategories", err, commonKeyVal...)
+	}()
+
+	if companyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	categories, err := g.product.GetCategories(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetCategoriesResponse{}
+	res.Categories = api.CategoriesSchemaToTree(categories)
+	return res, nil
+}
+
+func (g *gateway) MoveCategory(ctx context.Context, req *api.MoveCategoryRequest) (res *api.MoveCategoryResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.MoveCategory", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	if req.CategoryId == 0 {
+		return nil, derror.InvalidCategory
+	}
+
+	if req.ParentId != nil && *req.ParentId == req.CategoryId {
+		return nil, derror.New(derror.InvalidCategory, "move under itself")
+	}
+
+	category, err := g.product.MoveCategory(req.CompanyId, req.CategoryId, req.ParentId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.MoveCategoryResponse{}
+	res.Category = *api.CategorySchemaToApi(*category)
+	return res, nil
+}
+
+func (g *gateway) DeleteCategory(ctx context.Context, req *api.DeleteCategoryRequest) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+		}
+		kitlog.LogReqRes(g.logger, "service.DeleteCategory", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if req.CategoryId == 0 {
+		return derror.InvalidCategory
+	}
+
+	return g.product.DeleteCategory(req.CompanyId, req.CategoryId)
+}
+
+func (g *gateway) GetCategoryProducts(ctx context.Context, req *api.GetCategoryProductsRequest) (res *api.GetAllProductsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetCategoryProducts", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	if req.CategoryId == 0 {
+		return nil, derror.InvalidCategory
+	}
+
+	products, err := g.product.GetCategoryProducts(req.CompanyId, req.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetAllProductsResponse{}
+	res.Products = productsToApi(ctx, products)
+	return res, nil
+}
+
+func categoryIsValid(c model.Category) error {
+
+	if c.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if c.Name == "" {
+		return derror.New(derror.InvalidCategory, "empty name")
+	}
+
+	if !c.KindIsValid() {
+		return derror.New(derror.InvalidCategory, "invalid kind")
+	}
+
+	return nil
+}
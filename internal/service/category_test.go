@@ -0,0 +1,50 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCategoryIsValid(t *testing.T) {
+
+	tests := []struct {
+		Name     string
+		Category model.Category
+		Valid    bool
+	}{
+		{
+			Name:     "Ok",
+			Category: model.Category{CompanyId: 1, Name: "کاشان", Kind: model.CategoryKindCategory},
+			Valid:    true,
+		},
+		{
+			Name:     "Collection",
+			Category: model.Category{CompanyId: 1, Name: "مدرن ۲۰۲۶", Kind: model.CategoryKindCollection},
+			Valid:    true,
+		},
+		{
+			Name:     "InvalidKind",
+			Category: model.Category{CompanyId: 1, Name: "کاشان", Kind: "tag"},
+		},
+		{
+			Name:     "EmptyName",
+			Category: model.Category{CompanyId: 1, Kind: model.CategoryKindCategory},
+		},
+		{
+			Name:     "ZeroCompany",
+			Category: model.Category{Name: "کاشان", Kind: model.CategoryKindCategory},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := categoryIsValid(tt.Category)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
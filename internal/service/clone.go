@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+// CloneProduct copy a product to a new design code in same or another company, the copy is a draft
+func (g *gateway) CloneProduct(ctx context.Context, req *api.CloneProductRequest) (res *api.CloneProductResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.CloneProduct", err, commonKeyVal...)
+	}()
+
+	clone := api.CloneProductApiToModel(*req)
+	clone.DesignCode = model.NormalizeText(clone.DesignCode)
+	if err := productCloneIsValid(clone); err != nil {
+		return nil, err
+	}
+
+	product, err := g.product.CloneProduct(clone)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.CloneProductResponse{}
+	res.Product = *api.ProductSchemaToApi(*product)
+	return res, nil
+}
+
+func productCloneIsValid(clone model.ProductClone) error {
+	if clone.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if clone.ProductId == 0 {
+		return derror.InvalidProduct
+	}
+
+	if clone.DesignCode == "" {
+		return derror.New(derror.InvalidProduct, "empty design code")
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestProductCloneIsValid(t *testing.T) {
+
+	tests := []struct {
+		Name  string
+		Clone model.ProductClone
+		Valid bool
+	}{
+		{
+			Name:  "SameCompany",
+			Clone: model.ProductClone{CompanyId: 1, ProductId: 1, DesignCode: "107"},
+			Valid: true,
+		},
+		{
+			Name:  "AnotherCompany",
+			Clone: model.ProductClone{CompanyId: 1, ProductId: 1, DesignCode: "105", TargetCompanyId: 2, WithPrices: true},
+			Valid: true,
+		},
+		{
+			Name:  "ZeroCompany",
+			Clone: model.ProductClone{ProductId: 1, DesignCode: "107"},
+		},
+		{
+			Name:  "ZeroProduct",
+			Clone: model.ProductClone{CompanyId: 1, DesignCode: "107"},
+		},
+		{
+			Name:  "EmptyDesignCode",
+			Clone: model.ProductClone{CompanyId: 1, ProductId: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := productCloneIsValid(tt.Clone)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
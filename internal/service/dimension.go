@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+// AddDimensions add sizes to a product without resending its other sizes
+func (g *gateway) AddDimensions(ctx context.Context, req *api.AddDimensionsRequest) (res *api.EditProductResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.AddDimensions", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+	if req.ProductId == 0 {
+		return nil, derror.InvalidProduct
+	}
+
+	sizes := normalizeList(req.Sizes)
+	if err := labelsAreValid(sizes); err != nil {
+		return nil, derror.New(derror.InvalidDimension, err.Error())
+	}
+
+	product, err := g.product.AddDimensions(req.CompanyId, req.ProductId, sizes)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.EditProductResponse{}
+	res.Product = *api.ProductSchemaToApi(*product)
+	return res, nil
+}
+
+// RemoveDimensions remove sizes of a product, a product keeps at least one size
+func (g *gateway) RemoveDimensions(ctx context.Context, req *api.RemoveDimensionsRequest) (res *api.EditProductResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.RemoveDimensions", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+	if req.ProductId == 0 {
+		return nil, derror.InvalidProduct
+	}
+
+	if err := idsAreValid(req.DimensionIds); err != nil {
+		return nil, derror.New(derror.InvalidDimension, err.Error())
+	}
+
+	product, err := g.product.RemoveDimensions(req.CompanyId, req.ProductId, req.DimensionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.EditProductResponse{}
+	res.Product = *api.ProductSchemaToApi(*product)
+	return res, nil
+}
+
+// RenameDimension change a size of a product in place, carpets of size keep their SKU
+func (g *gateway) RenameDimension(ctx context.Context, req *api.RenameDimensionRequest) (res *api.EditProductResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.RenameDimension", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+	if req.ProductId == 0 {
+		return nil, derror.InvalidProduct
+	}
+	if req.DimensionId == 0 {
+		return nil, derror.New(derror.InvalidDimension, "zero id")
+	}
+
+	size := model.NormalizeText(req.Size)
+	if size == "" {
+		return nil, derror.New(derror.InvalidDimension, "empty size")
+	}
+
+	product, err := g.product.RenameDimension(req.CompanyId, req.ProductId, req.DimensionId, size)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.EditProductResponse{}
+	res.Product = *api.ProductSchemaToApi(*product)
+	return res, nil
+}
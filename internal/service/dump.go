@@ -0,0 +1,81 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/catalog"
+	"github.com/seed95/product-service/internal/derror"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"io"
+)
+
+// DumpContentType is content type of a catalog dump
+const DumpContentType = "application/json"
+
+// DumpCatalog write complete catalog of company to `w` as a versioned dump, it is read in one snapshot
+func (g *gateway) DumpCatalog(ctx context.Context, req *api.DumpCatalogRequest, w io.Writer) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", req.CompanyId)),
+		}
+		kitlog.LogReqRes(g.logger, "service.DumpCatalog", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	dump, err := g.product.DumpCatalog(req.CompanyId)
+	if err != nil {
+		return err
+	}
+
+	if err := catalog.WriteDump(w, *dump); err != nil {
+		return derror.New(derror.InternalServer, err.Error())
+	}
+	return nil
+}
+
+// RestoreCatalog verify a dump and restore it into company of request in one transaction,
+// dumped ids are remapped to new ids
+func (g *gateway) RestoreCatalog(ctx context.Context, req *api.RestoreCatalogRequest) (res *api.RestoreCatalogResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", req.CompanyId)),
+			keyval.String("size", fmt.Sprintf("%v", len(req.Data))),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.RestoreCatalog", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	dump, err := catalog.ReadDump(bytes.NewReader(req.Data))
+	if err != nil {
+		return nil, dumpError(err)
+	}
+
+	result, err := g.product.RestoreCatalog(req.CompanyId, *dump)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.RestoreCatalogResponse{Counts: result.Counts, ProductIds: result.ProductIds}, nil
+}
+
+// dumpError convert an error of reading a dump to a service error
+func dumpError(err error) error {
+	switch {
+	case errors.Is(err, catalog.ErrDumpVersion), errors.Is(err, catalog.ErrDumpChecksum), errors.Is(err, catalog.ErrInvalidDump):
+		return derror.New(derror.InvalidDump, err.Error())
+	}
+	return derror.New(derror.InternalServer, err.Error())
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+// ExcludeCarpet remove a size and color of a product from its carpets
+func (g *gateway) ExcludeCarpet(ctx context.Context, req *api.ExcludeCarpetRequest) (res *api.ExcludeCarpetResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.ExcludeCarpet", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	modelExclusion := api.CarpetExclusionApiToModel(req.CarpetExclusion)
+	if req.CarpetId != "" {
+		carpetSku, err := carpetSkuOfCompany(req.CompanyId, req.CarpetId)
+		if err != nil {
+			return nil, err
+		}
+		modelExclusion.ProductId = carpetSku.ProductId
+		modelExclusion.DimensionId = carpetSku.DimensionId
+		modelExclusion.ThemeId = carpetSku.ThemeId
+	}
+
+	if err := carpetExclusionIsValid(*modelExclusion); err != nil {
+		return nil, err
+	}
+
+	if modelExclusion.Id != 0 {
+		return nil, derror.New(derror.InvalidCarpet, "invalid exclusion id")
+	}
+
+	exclusion, err := g.product.ExcludeCarpet(req.CompanyId, *modelExclusion)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.ExcludeCarpetResponse{}
+	res.CarpetExclusion = *api.CarpetExclusionModelToApi(*exclusion)
+	return res, nil
+}
+
+// IncludeCarpet delete exclusion of a size and color of a product, carpet is listed again
+func (g *gateway) IncludeCarpet(ctx context.Context, req *api.IncludeCarpetRequest) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+		}
+		kitlog.LogReqRes(g.logger, "service.IncludeCarpet", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	productId, dimensionId, themeId := req.ProductId, req.DimensionId, req.ThemeId
+	if req.CarpetId != "" {
+		carpetSku, err := carpetSkuOfCompany(req.CompanyId, req.CarpetId)
+		if err != nil {
+			return err
+		}
+		productId, dimensionId, themeId = carpetSku.ProductId, carpetSku.DimensionId, carpetSku.ThemeId
+	}
+
+	if productId == 0 || dimensionId == 0 || themeId == 0 {
+		return derror.InvalidCarpet
+	}
+
+	return g.product.IncludeCarpet(req.CompanyId, productId, dimensionId, themeId)
+}
+
+// GetCarpetExclusions return excluded sizes and colors of a product
+func (g *gateway) GetCarpetExclusions(ctx context.Context, req *api.GetCarpetExclusionsRequest) (res *api.GetCarpetExclusionsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetCarpetExclusions", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	if req.ProductId == 0 {
+		return nil, derror.InvalidProduct
+	}
+
+	exclusions, err := g.product.GetCarpetExclusions(req.CompanyId, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetCarpetExclusionsResponse{}
+	res.Exclusions = make([]api.CarpetExclusion, len(exclusions))
+	for i, e := range exclusions {
+		res.Exclusions[i] = *api.CarpetExclusionModelToApi(e)
+	}
+	return res, nil
+}
+
+func carpetExclusionIsValid(e model.CarpetExclusion) error {
+
+	if e.ProductId == 0 {
+		return derror.InvalidProduct
+	}
+
+	if e.DimensionId == 0 && e.Size == "" {
+		return derror.New(derror.InvalidCarpet, "empty size")
+	}
+
+	if e.ThemeId == 0 && e.Color == "" {
+		return derror.New(derror.InvalidCarpet, "empty color")
+	}
+
+	return nil
+}
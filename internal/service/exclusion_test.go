@@ -0,0 +1,50 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCarpetExclusionIsValid(t *testing.T) {
+
+	tests := []struct {
+		Name      string
+		Exclusion model.CarpetExclusion
+		Valid     bool
+	}{
+		{
+			Name:      "OkIds",
+			Exclusion: model.CarpetExclusion{ProductId: 1, DimensionId: 1, ThemeId: 1},
+			Valid:     true,
+		},
+		{
+			Name:      "OkLabels",
+			Exclusion: model.CarpetExclusion{ProductId: 1, Size: "6", Color: "قرمز"},
+			Valid:     true,
+		},
+		{
+			Name:      "ZeroProduct",
+			Exclusion: model.CarpetExclusion{DimensionId: 1, ThemeId: 1},
+		},
+		{
+			Name:      "NoSize",
+			Exclusion: model.CarpetExclusion{ProductId: 1, Color: "قرمز"},
+		},
+		{
+			Name:      "NoColor",
+			Exclusion: model.CarpetExclusion{ProductId: 1, DimensionId: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := carpetExclusionIsValid(tt.Exclusion)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/catalog"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"io"
+	"time"
+)
+
+// MaxExportBatches is number of database batches written by one ExportCatalog call,
+// a larger export is continued with the returned id
+const MaxExportBatches = 10
+
+// ExportCatalog write products or carpets of company that match request to `w` as csv or json lines,
+// rows are written while they are read from database. At most MaxExportBatches batches are written,
+// id to continue export after is returned if export isn't finished, otherwise zero
+func (g *gateway) ExportCatalog(ctx context.Context, req *api.ExportCatalogRequest, w io.Writer) (nextAfterId uint, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("next_after_id", fmt.Sprintf("%v", nextAfterId)),
+		}
+		kitlog.LogReqRes(g.logger, "service.ExportCatalog", err, commonKeyVal...)
+	}()
+
+	kind, format := exportKindFormat(req)
+	if !model.ExportKindIsValid(kind) {
+		return 0, derror.New(derror.InvalidExport, "invalid kind "+kind)
+	}
+	if !model.ExportFormatIsValid(format) {
+		return 0, derror.New(derror.InvalidExport, "invalid format "+format)
+	}
+
+	productFilter, err := searchFilter(ctx, req.SearchProductsRequest)
+	if err != nil {
+		return 0, err
+	}
+	filter := model.ExportFilter{
+		ProductFilter: *productFilter,
+		PriceListId:   req.PriceListId,
+		At:            time.Now(),
+		AfterId:       req.AfterId,
+		MaxBatches:    MaxExportBatches,
+	}
+
+	columns, err := catalog.ExportColumns(kind, req.Columns, req.PriceListId != 0)
+	if err != nil {
+		return 0, derror.New(derror.InvalidExport, err.Error())
+	}
+
+	writer, err := catalog.NewWriter(w, format, columns, req.AfterId == 0)
+	if err != nil {
+		return 0, derror.New(derror.InternalServer, err.Error())
+	}
+
+	if kind == model.ExportCarpets {
+		nextAfterId, err = g.product.ExportCarpets(filter, func(c model.ExportCarpet) error {
+			return writer.Write(catalog.CarpetValues(c))
+		})
+	} else {
+		nextAfterId, err = g.product.ExportProducts(filter, func(p model.ExportProduct) error {
+			return writer.Write(catalog.ProductValues(p))
+		})
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := writer.Flush(); err != nil {
+		return 0, derror.New(derror.InternalServer, err.Error())
+	}
+	return nextAfterId, nil
+}
+
+// exportKindFormat return kind and format of request with their defaults
+func exportKindFormat(req *api.ExportCatalogRequest) (kind, format string) {
+	kind, format = req.Kind, req.Format
+	if kind == "" {
+		kind = model.ExportProducts
+	}
+	if format == "" {
+		format = model.ExportCSV
+	}
+	return kind, format
+}
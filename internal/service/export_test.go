@@ -0,0 +1,18 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestExportKindFormat(t *testing.T) {
+	kind, format := exportKindFormat(&api.ExportCatalogRequest{})
+	require.Equal(t, model.ExportProducts, kind)
+	require.Equal(t, model.ExportCSV, format)
+
+	kind, format = exportKindFormat(&api.ExportCatalogRequest{Kind: model.ExportCarpets, Format: model.ExportJSONL})
+	require.Equal(t, model.ExportCarpets, kind)
+	require.Equal(t, model.ExportJSONL, format)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/catalog"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"sort"
+	"strings"
+)
+
+const (
+	DefaultImportChunkSize = 100
+	MaxImportChunkSize     = 1000
+	MaxImportRows          = 10000
+)
+
+// ImportProducts create or update products of company from a csv with a line per design.
+// every row is validated like a new product, a dry run report what a commit would do without saving
+func (g *gateway) ImportProducts(ctx context.Context, req *api.ImportProductsRequest) (res *api.ImportProductsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", req.CompanyId)),
+			keyval.String("dry_run", fmt.Sprintf("%v", req.DryRun)),
+			keyval.String("chunk_size", fmt.Sprintf("%v", req.ChunkSize)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.ImportProducts", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	chunkSize := req.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = DefaultImportChunkSize
+	}
+	if chunkSize < 0 || chunkSize > MaxImportChunkSize {
+		return nil, derror.New(derror.InvalidImport, fmt.Sprintf("chunk size should be between 1 and %v", MaxImportChunkSize))
+	}
+
+	rows, err := catalog.ReadProducts(strings.NewReader(req.Csv))
+	if err != nil {
+		return nil, derror.New(derror.InvalidImport, err.Error())
+	}
+	if len(rows) == 0 {
+		return nil, derror.New(derror.InvalidImport, "no rows")
+	}
+	if len(rows) > MaxImportRows {
+		return nil, derror.New(derror.InvalidImport, fmt.Sprintf("more than %v rows", MaxImportRows))
+	}
+
+	results, validRows := importRowsAreValid(req.CompanyId, rows)
+
+	if len(validRows) != 0 {
+		imported, err := g.product.ImportProducts(req.CompanyId, validRows, chunkSize, req.DryRun)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, imported...)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Line < results[j].Line
+	})
+
+	res = &api.ImportProductsResponse{DryRun: req.DryRun}
+	res.Rows = make([]api.ImportResult, len(results))
+	for i, r := range results {
+		res.Rows[i] = *api.ImportResultModelToApi(r)
+		switch r.Action {
+		case model.ImportCreate:
+			res.Created++
+		case model.ImportUpdate:
+			res.Updated++
+		default:
+			res.Failed++
+		}
+	}
+	return res, nil
+}
+
+// importRowsAreValid check every row with rules of a new product, a design code can be in one row.
+// it returns results of failed rows and rows to import
+func importRowsAreValid(companyId uint, rows []catalog.Row) ([]model.ImportResult, []model.ImportRow) {
+	var failed []model.ImportResult
+	var valid []model.ImportRow
+
+	lines := make(map[string]int, len(rows))
+	for _, row := range rows {
+		product := row.Product
+		product.CompanyId = companyId
+		product.Normalize()
+
+		err := row.Err
+		if err == nil {
+			err = productIsValid(product)
+		}
+		if line, ok := lines[product.DesignCode]; err == nil && ok {
+			err = derror.New(derror.InvalidImport, fmt.Sprintf("design code %v is also in line %v", product.DesignCode, line))
+		}
+
+		if err != nil {
+			failed = append(failed, model.ImportResult{
+				Line:       row.Line,
+				DesignCode: product.DesignCode,
+				Action:     model.ImportFail,
+				Error:      err.Error(),
+			})
+			continue
+		}
+
+		lines[product.DesignCode] = row.Line
+		valid = append(valid, model.ImportRow{Line: row.Line, Product: product})
+	}
+
+	return failed, valid
+}
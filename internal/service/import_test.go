@@ -0,0 +1,33 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/catalog"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestImportRowsAreValid(t *testing.T) {
+	rows := []catalog.Row{
+		{Line: 2, Product: model.Product{DesignCode: "105", Sizes: []string{"6"}, Colors: []string{"قرمز"}}},
+		{Line: 3, Product: model.Product{DesignCode: "106", Sizes: []string{"6", "6"}, Colors: []string{"قرمز"}}},
+		{Line: 4, Product: model.Product{DesignCode: "105", Sizes: []string{"9"}, Colors: []string{"آبی"}}},
+		{Line: 5, Product: model.Product{Sizes: []string{"6"}, Colors: []string{"قرمز"}}},
+		{Line: 6, Err: catalog.ErrInvalidCell},
+		{Line: 7, Product: model.Product{DesignCode: "107", Sizes: []string{"6"}, Colors: []string{"قرمز"}}},
+	}
+
+	failed, valid := importRowsAreValid(1, rows)
+
+	require.Equal(t, 2, len(valid))
+	require.Equal(t, 2, valid[0].Line)
+	require.Equal(t, uint(1), valid[0].Product.CompanyId)
+	require.Equal(t, 7, valid[1].Line)
+
+	require.Equal(t, 4, len(failed))
+	for i, line := range []int{3, 4, 5, 6} {
+		require.Equal(t, line, failed[i].Line)
+		require.Equal(t, model.ImportFail, failed[i].Action)
+		require.NotEmpty(t, failed[i].Error)
+	}
+}
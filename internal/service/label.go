@@ -0,0 +1,149 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/label"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+// defaultLabelColumns is number of labels in a row of sheet if not requested
+const defaultLabelColumns = 2
+
+// MaxLabelSheetCarpets is most carpets of a sheet without page, a larger sheet should be requested page by page
+const MaxLabelSheetCarpets = 2000
+
+// GetCarpetLabel render label of a carpet with its barcode and qr code
+func (g *gateway) GetCarpetLabel(ctx context.Context, req *api.GetCarpetLabelRequest) (res *api.LabelResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetCarpetLabel", err, commonKeyVal...)
+	}()
+
+	format, err := labelFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	carpet, err := g.GetCarpet(ctx, &api.GetCarpetRequest{CompanyId: req.CompanyId, CarpetId: req.CarpetId})
+	if err != nil {
+		return nil, err
+	}
+
+	return renderLabels(format, []model.Carpet{api.CarpetApiToModel(carpet.Carpet)}, 1)
+}
+
+// GetLabelSheet render a sheet of labels for every carpet of a product or a company, or for a page of them
+func (g *gateway) GetLabelSheet(ctx context.Context, req *api.GetLabelSheetRequest) (res *api.GetLabelSheetResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetLabelSheet", err, commonKeyVal...)
+	}()
+
+	format, err := labelFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := req.Columns
+	if columns == 0 {
+		columns = defaultLabelColumns
+	} else if columns < 0 {
+		return nil, derror.New(derror.InvalidLabel, "invalid columns")
+	}
+
+	search := api.SearchProductsRequest{CompanyId: req.CompanyId}
+	if req.ProductId != 0 {
+		search.ProductIds = []uint{req.ProductId}
+	}
+	filter, err := searchFilter(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetLabelSheetResponse{}
+	var carpets []model.Carpet
+	if req.Page != 0 || req.PageSize != 0 {
+		page := api.PageRequestToModel(req.PageRequest)
+		carpets, res.Total, err = g.product.SearchCarpets(*filter, page)
+		res.Page, res.PageSize = page.Number, page.Size
+	} else {
+		carpets, res.Total, err = g.sheetCarpets(*filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(carpets) == 0 {
+		return nil, derror.CarpetNotFound
+	}
+
+	sheet, err := renderLabels(format, carpets, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	res.LabelResponse = *sheet
+	return res, nil
+}
+
+// sheetCarpets return every carpet that match `filter` and their number, page by page,
+// more than MaxLabelSheetCarpets carpets is derror.InvalidLabel
+func (g *gateway) sheetCarpets(filter model.ProductFilter) ([]model.Carpet, int64, error) {
+	var carpets []model.Carpet
+	page := model.Page{Number: 1, Size: model.MaxPageSize}
+	for {
+		pageCarpets, total, err := g.product.SearchCarpets(filter, page)
+		if err != nil {
+			return nil, 0, err
+		}
+		if total > MaxLabelSheetCarpets {
+			return nil, 0, derror.New(derror.InvalidLabel,
+				fmt.Sprintf("%v carpets are more than %v, request sheet page by page", total, MaxLabelSheetCarpets))
+		}
+
+		carpets = append(carpets, pageCarpets...)
+		if len(pageCarpets) < page.Size || int64(len(carpets)) >= total {
+			return carpets, total, nil
+		}
+		page.Number++
+	}
+}
+
+// labelFormat check requested `format`, empty format is chosen by text of labels in renderLabels
+func labelFormat(format string) (string, error) {
+	if format == "" {
+		return "", nil
+	}
+	if !label.FormatIsValid(format) {
+		return "", derror.New(derror.InvalidLabel, "invalid format "+format)
+	}
+	return format, nil
+}
+
+func renderLabels(format string, carpets []model.Carpet, columns int) (*api.LabelResponse, error) {
+	labels := make([]label.Label, len(carpets))
+	for i, c := range carpets {
+		labels[i] = label.FromCarpet(c)
+	}
+	if format == "" {
+		format = label.DefaultFormat(labels)
+	}
+
+	var buf bytes.Buffer
+	if err := label.Render(&buf, format, labels, columns); err != nil {
+		return nil, derror.New(derror.InvalidLabel, err.Error())
+	}
+
+	return &api.LabelResponse{ContentType: label.ContentType(format), Data: buf.Bytes()}, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/label"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLabelFormat(t *testing.T) {
+	format, err := labelFormat("")
+	require.Nil(t, err)
+	require.Equal(t, "", format)
+
+	format, err = labelFormat(label.FormatSVG)
+	require.Nil(t, err)
+	require.Equal(t, label.FormatSVG, format)
+
+	_, err = labelFormat("pdf")
+	require.Equal(t, derror.StatusText(derror.InvalidLabel), derror.StatusText(err))
+}
+
+func TestRenderLabels(t *testing.T) {
+	carpets := []model.Carpet{{CompanyId: 1, ProductId: 12, DimensionId: 14, ThemeId: 13, DesignCode: "106", Dimension: "6", Color: "آبی"}}
+
+	res, err := renderLabels(label.FormatSVG, carpets, 1)
+	require.Nil(t, err)
+	require.Equal(t, "image/svg+xml", res.ContentType)
+	require.NotEmpty(t, res.Data)
+}
+
+func TestRenderLabels_DefaultFormat(t *testing.T) {
+	carpets := []model.Carpet{{CompanyId: 1, ProductId: 12, DimensionId: 14, ThemeId: 13, DesignCode: "106", Dimension: "6", Color: "blue"}}
+
+	res, err := renderLabels("", carpets, 1)
+	require.Nil(t, err)
+	require.Equal(t, "image/png", res.ContentType)
+
+	// Persian color isn't transliterated
+	carpets = append(carpets, model.Carpet{CompanyId: 1, ProductId: 12, DimensionId: 14, ThemeId: 14, DesignCode: "106", Dimension: "6", Color: "قرمز"})
+	res, err = renderLabels("", carpets, 1)
+	require.Nil(t, err)
+	require.Equal(t, "image/svg+xml", res.ContentType)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+// MergeProducts fold a duplicate product into another product of company and return the product that is kept
+func (g *gateway) MergeProducts(ctx context.Context, req *api.MergeProductsRequest) (res *api.EditProductResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.MergeProducts", err, commonKeyVal...)
+	}()
+
+	if err := productMergeIsValid(req); err != nil {
+		return nil, err
+	}
+
+	product, err := g.product.MergeProducts(req.CompanyId, req.SourceProductId, req.TargetProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.EditProductResponse{}
+	res.Product = *api.ProductSchemaToApi(*product)
+	return res, nil
+}
+
+// GetProductHistory return recorded changes of a product
+func (g *gateway) GetProductHistory(ctx context.Context, req *api.GetProductHistoryRequest) (res *api.GetProductHistoryResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetProductHistory", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+	if req.ProductId == 0 {
+		return nil, derror.InvalidProduct
+	}
+
+	history, err := g.product.GetProductHistory(req.CompanyId, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetProductHistoryResponse{History: make([]api.ProductHistory, len(history))}
+	for i, h := range history {
+		res.History[i] = *api.ProductHistorySchemaToApi(h)
+	}
+	return res, nil
+}
+
+func productMergeIsValid(req *api.MergeProductsRequest) error {
+	if req.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if req.SourceProductId == 0 || req.TargetProductId == 0 {
+		return derror.InvalidProduct
+	}
+
+	if req.SourceProductId == req.TargetProductId {
+		return derror.New(derror.InvalidProduct, "product is merged into itself")
+	}
+
+	return nil
+}
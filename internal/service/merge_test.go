@@ -0,0 +1,49 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/api"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestProductMergeIsValid(t *testing.T) {
+
+	tests := []struct {
+		Name  string
+		Req   api.MergeProductsRequest
+		Valid bool
+	}{
+		{
+			Name:  "Valid",
+			Req:   api.MergeProductsRequest{CompanyId: 1, SourceProductId: 2, TargetProductId: 1},
+			Valid: true,
+		},
+		{
+			Name: "ZeroCompany",
+			Req:  api.MergeProductsRequest{SourceProductId: 2, TargetProductId: 1},
+		},
+		{
+			Name: "ZeroSource",
+			Req:  api.MergeProductsRequest{CompanyId: 1, TargetProductId: 1},
+		},
+		{
+			Name: "ZeroTarget",
+			Req:  api.MergeProductsRequest{CompanyId: 1, SourceProductId: 2},
+		},
+		{
+			Name: "SameProduct",
+			Req:  api.MergeProductsRequest{CompanyId: 1, SourceProductId: 1, TargetProductId: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := productMergeIsValid(&tt.Req)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,439 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"time"
+)
+
+func (g *gateway) CreatePriceList(ctx context.Context, req *api.CreatePriceListRequest) (res *api.CreatePriceListResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.CreatePriceList", err, commonKeyVal...)
+	}()
+
+	modelList := api.PriceListApiToModel(req.PriceList)
+	if err := priceListIsValid(*modelList); err != nil {
+		return nil, err
+	}
+
+	if modelList.Id != 0 {
+		return nil, derror.New(derror.InvalidPriceList, "invalid price list id")
+	}
+
+	list, err := g.product.CreatePriceList(*modelList)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.CreatePriceListResponse{}
+	res.PriceList = *api.PriceListSchemaToApi(*list)
+	return res, nil
+}
+
+func (g *gateway) GetPriceLists(ctx context.Context, companyId uint) (res *api.GetPriceListsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetPriceLists", err, commonKeyVal...)
+	}()
+
+	if companyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	lists, err := g.product.GetPriceLists(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetPriceListsResponse{}
+	res.PriceLists = make([]api.PriceList, len(lists))
+	for i, l := range lists {
+		res.PriceLists[i] = *api.PriceListSchemaToApi(l)
+	}
+	return res, nil
+}
+
+func (g *gateway) DeletePriceList(ctx context.Context, req *api.DeletePriceListRequest) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+		}
+		kitlog.LogReqRes(g.logger, "service.DeletePriceList", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if req.PriceListId == 0 {
+		return derror.New(derror.InvalidPriceList, "invalid price list id")
+	}
+
+	return g.product.DeletePriceList(req.CompanyId, req.PriceListId)
+}
+
+// AddPrice add a price of a carpet or of a product size to a price list, zero ValidFrom means now
+func (g *gateway) AddPrice(ctx context.Context, req *api.AddPriceRequest) (res *api.AddPriceResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.AddPrice", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	modelPrice := api.PriceApiToModel(req.Price)
+	if req.CarpetId != "" {
+		carpetSku, err := carpetSkuOfCompany(req.CompanyId, req.CarpetId)
+		if err != nil {
+			return nil, err
+		}
+		modelPrice.ProductId = carpetSku.ProductId
+		modelPrice.DimensionId = carpetSku.DimensionId
+		modelPrice.ThemeId = carpetSku.ThemeId
+	}
+
+	if modelPrice.ValidFrom.IsZero() {
+		modelPrice.ValidFrom = time.Now()
+	}
+
+	if err := priceIsValid(*modelPrice); err != nil {
+		return nil, err
+	}
+
+	if modelPrice.Id != 0 {
+		return nil, derror.New(derror.InvalidPrice, "invalid price id")
+	}
+
+	price, err := g.product.AddPrice(req.CompanyId, *modelPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.AddPriceResponse{}
+	res.Price = *api.PriceSchemaToApi(*price)
+	return res, nil
+}
+
+func (g *gateway) GetPrices(ctx context.Context, req *api.GetPricesRequest) (res *api.GetPricesResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetPrices", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	if req.PriceListId == 0 {
+		return nil, derror.New(derror.InvalidPriceList, "invalid price list id")
+	}
+
+	prices, err := g.product.GetPrices(req.CompanyId, req.PriceListId, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetPricesResponse{}
+	res.Prices = make([]api.Price, len(prices))
+	for i, p := range prices {
+		res.Prices[i] = *api.PriceSchemaToApi(p)
+	}
+	return res, nil
+}
+
+func (g *gateway) DeletePrice(ctx context.Context, req *api.DeletePriceRequest) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+		}
+		kitlog.LogReqRes(g.logger, "service.DeletePrice", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if req.PriceId == 0 {
+		return derror.New(derror.InvalidPrice, "invalid price id")
+	}
+
+	return g.product.DeletePrice(req.CompanyId, req.PriceId)
+}
+
+// ResolvePrice return price of a carpet in a price list that is effective at request time, nil time means now
+func (g *gateway) ResolvePrice(ctx context.Context, req *api.ResolvePriceRequest) (res *api.ResolvePriceResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.ResolvePrice", err, commonKeyVal...)
+	}()
+
+	if req.PriceListId == 0 {
+		return nil, derror.New(derror.InvalidPriceList, "invalid price list id")
+	}
+
+	carpet, err := g.companyCarpet(ctx, req.CompanyId, req.CarpetId)
+	if err != nil {
+		return nil, err
+	}
+
+	at := time.Now()
+	if req.At != nil {
+		at = *req.At
+	}
+
+	effective, err := g.product.ResolvePrice(req.CompanyId, req.PriceListId, *carpet, at)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.ResolvePriceResponse{}
+	res.EffectivePrice = *api.EffectivePriceModelToApi(*effective)
+	return res, nil
+}
+
+// AddPriceAdjustment add a surcharge or discount of area prices for a color or shape to a price list
+func (g *gateway) AddPriceAdjustment(ctx context.Context, req *api.AddPriceAdjustmentRequest) (res *api.AddPriceAdjustmentResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.AddPriceAdjustment", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	modelAdjustment := api.PriceAdjustmentApiToModel(req.PriceAdjustment)
+	if err := priceAdjustmentIsValid(*modelAdjustment); err != nil {
+		return nil, err
+	}
+
+	if modelAdjustment.Id != 0 {
+		return nil, derror.New(derror.InvalidPrice, "invalid adjustment id")
+	}
+
+	adjustment, err := g.product.AddPriceAdjustment(req.CompanyId, *modelAdjustment)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.AddPriceAdjustmentResponse{}
+	res.PriceAdjustment = *api.PriceAdjustmentSchemaToApi(*adjustment)
+	return res, nil
+}
+
+func (g *gateway) GetPriceAdjustments(ctx context.Context, req *api.GetPriceAdjustmentsRequest) (res *api.GetPriceAdjustmentsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetPriceAdjustments", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	if req.PriceListId == 0 {
+		return nil, derror.New(derror.InvalidPriceList, "invalid price list id")
+	}
+
+	adjustments, err := g.product.GetPriceAdjustments(req.CompanyId, req.PriceListId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetPriceAdjustmentsResponse{}
+	res.Adjustments = make([]api.PriceAdjustment, len(adjustments))
+	for i, a := range adjustments {
+		res.Adjustments[i] = *api.PriceAdjustmentSchemaToApi(a)
+	}
+	return res, nil
+}
+
+func (g *gateway) DeletePriceAdjustment(ctx context.Context, req *api.DeletePriceAdjustmentRequest) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+		}
+		kitlog.LogReqRes(g.logger, "service.DeletePriceAdjustment", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if req.AdjustmentId == 0 {
+		return derror.New(derror.InvalidPrice, "invalid adjustment id")
+	}
+
+	return g.product.DeletePriceAdjustment(req.CompanyId, req.AdjustmentId)
+}
+
+// QuotePrice calculate area price of a product for a size of product or a custom size with its breakdown
+func (g *gateway) QuotePrice(ctx context.Context, req *api.QuotePriceRequest) (res *api.QuotePriceResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.QuotePrice", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	quote := api.QuotePriceRequestToModel(*req)
+	if err := priceQuoteIsValid(quote); err != nil {
+		return nil, err
+	}
+
+	at := time.Now()
+	if req.At != nil {
+		at = *req.At
+	}
+
+	effective, err := g.product.QuotePrice(req.CompanyId, quote, at)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.QuotePriceResponse{}
+	res.EffectivePrice = *api.EffectivePriceModelToApi(*effective)
+	return res, nil
+}
+
+func priceListIsValid(l model.PriceList) error {
+
+	if l.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if l.Name == "" {
+		return derror.New(derror.InvalidPriceList, "empty name")
+	}
+
+	if !l.KindIsValid() {
+		return derror.New(derror.InvalidPriceList, "invalid kind")
+	}
+
+	if !model.CurrencyIsValid(l.Currency) {
+		return derror.New(derror.InvalidPriceList, "invalid currency")
+	}
+
+	return nil
+}
+
+func priceIsValid(p model.Price) error {
+
+	if p.PriceListId == 0 {
+		return derror.New(derror.InvalidPriceList, "invalid price list id")
+	}
+
+	if p.ProductId == 0 {
+		return derror.InvalidProduct
+	}
+
+	if p.PerSquareMeter {
+		if p.DimensionId != 0 || p.Size != "" || p.ThemeId != 0 {
+			return derror.New(derror.InvalidPrice, "price per square meter with size or color")
+		}
+	} else if p.DimensionId == 0 && p.Size == "" {
+		return derror.New(derror.InvalidPrice, "empty size")
+	}
+
+	if p.Amount <= 0 {
+		return derror.New(derror.InvalidPrice, "invalid amount")
+	}
+
+	if p.ValidTo != nil && !p.ValidTo.After(p.ValidFrom) {
+		return derror.New(derror.InvalidPrice, "valid to is before valid from")
+	}
+
+	return nil
+}
+
+func priceAdjustmentIsValid(a model.PriceAdjustment) error {
+
+	if a.PriceListId == 0 {
+		return derror.New(derror.InvalidPriceList, "invalid price list id")
+	}
+
+	if !a.KindIsValid() {
+		return derror.New(derror.InvalidPrice, "invalid adjustment kind")
+	}
+
+	if a.Value == "" {
+		return derror.New(derror.InvalidPrice, "empty adjustment value")
+	}
+
+	if a.Kind == model.AdjustmentShape && !model.ShapeIsValid(a.Value) {
+		return derror.New(derror.InvalidPrice, "invalid shape "+a.Value)
+	}
+
+	// A discount can't make price zero or negative
+	if a.Percent == 0 || a.Percent <= -100 {
+		return derror.New(derror.InvalidPrice, "invalid adjustment percent")
+	}
+
+	return nil
+}
+
+func priceQuoteIsValid(q model.PriceQuote) error {
+
+	if q.PriceListId == 0 {
+		return derror.New(derror.InvalidPriceList, "invalid price list id")
+	}
+
+	if q.ProductId == 0 {
+		return derror.InvalidProduct
+	}
+
+	if q.Size == "" && (q.Width == 0 || q.Length == 0) {
+		return derror.New(derror.InvalidDimension, "empty size")
+	}
+
+	if !model.ShapeIsValid(q.Shape) {
+		return derror.New(derror.InvalidDimension, "invalid shape "+q.Shape)
+	}
+
+	return nil
+}
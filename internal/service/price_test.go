@@ -0,0 +1,206 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPriceListIsValid(t *testing.T) {
+
+	tests := []struct {
+		Name      string
+		PriceList model.PriceList
+		Valid     bool
+	}{
+		{
+			Name:      "Ok",
+			PriceList: model.PriceList{CompanyId: 1, Name: "خرده فروشی", Kind: model.PriceListRetail, Currency: "IRR"},
+			Valid:     true,
+		},
+		{
+			Name:      "ZeroCompany",
+			PriceList: model.PriceList{Name: "خرده فروشی", Kind: model.PriceListRetail, Currency: "IRR"},
+		},
+		{
+			Name:      "EmptyName",
+			PriceList: model.PriceList{CompanyId: 1, Kind: model.PriceListRetail, Currency: "IRR"},
+		},
+		{
+			Name:      "InvalidKind",
+			PriceList: model.PriceList{CompanyId: 1, Name: "خرده فروشی", Kind: "export", Currency: "IRR"},
+		},
+		{
+			Name:      "InvalidCurrency",
+			PriceList: model.PriceList{CompanyId: 1, Name: "خرده فروشی", Kind: model.PriceListRetail, Currency: "ریال"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := priceListIsValid(tt.PriceList)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
+
+func TestPriceIsValid(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-time.Hour)
+
+	tests := []struct {
+		Name  string
+		Price model.Price
+		Valid bool
+	}{
+		{
+			Name:  "Ok",
+			Price: model.Price{PriceListId: 1, ProductId: 1, DimensionId: 1, Amount: 1000, ValidFrom: now},
+			Valid: true,
+		},
+		{
+			Name:  "OkSize",
+			Price: model.Price{PriceListId: 1, ProductId: 1, Size: "6", Amount: 1000, ValidFrom: now},
+			Valid: true,
+		},
+		{
+			Name:  "OkPerSquareMeter",
+			Price: model.Price{PriceListId: 1, ProductId: 1, PerSquareMeter: true, Amount: 1000, ValidFrom: now},
+			Valid: true,
+		},
+		{
+			Name:  "PerSquareMeterWithSize",
+			Price: model.Price{PriceListId: 1, ProductId: 1, Size: "6", PerSquareMeter: true, Amount: 1000, ValidFrom: now},
+		},
+		{
+			Name:  "ZeroPriceList",
+			Price: model.Price{ProductId: 1, DimensionId: 1, Amount: 1000, ValidFrom: now},
+		},
+		{
+			Name:  "ZeroProduct",
+			Price: model.Price{PriceListId: 1, DimensionId: 1, Amount: 1000, ValidFrom: now},
+		},
+		{
+			Name:  "EmptySize",
+			Price: model.Price{PriceListId: 1, ProductId: 1, Amount: 1000, ValidFrom: now},
+		},
+		{
+			Name:  "ZeroAmount",
+			Price: model.Price{PriceListId: 1, ProductId: 1, DimensionId: 1, ValidFrom: now},
+		},
+		{
+			Name:  "ValidToBeforeValidFrom",
+			Price: model.Price{PriceListId: 1, ProductId: 1, DimensionId: 1, Amount: 1000, ValidFrom: now, ValidTo: &before},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := priceIsValid(tt.Price)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
+
+func TestPriceAdjustmentIsValid(t *testing.T) {
+
+	tests := []struct {
+		Name       string
+		Adjustment model.PriceAdjustment
+		Valid      bool
+	}{
+		{
+			Name:       "OkColor",
+			Adjustment: model.PriceAdjustment{PriceListId: 1, Kind: model.AdjustmentColor, Value: "لاکی", Percent: 10},
+			Valid:      true,
+		},
+		{
+			Name:       "OkShape",
+			Adjustment: model.PriceAdjustment{PriceListId: 1, ProductId: 1, Kind: model.AdjustmentShape, Value: model.ShapeRunner, Percent: -15},
+			Valid:      true,
+		},
+		{
+			Name:       "ZeroPriceList",
+			Adjustment: model.PriceAdjustment{Kind: model.AdjustmentColor, Value: "لاکی", Percent: 10},
+		},
+		{
+			Name:       "InvalidKind",
+			Adjustment: model.PriceAdjustment{PriceListId: 1, Kind: "size", Value: "6", Percent: 10},
+		},
+		{
+			Name:       "InvalidShape",
+			Adjustment: model.PriceAdjustment{PriceListId: 1, Kind: model.AdjustmentShape, Value: "oval", Percent: 10},
+		},
+		{
+			Name:       "ZeroPercent",
+			Adjustment: model.PriceAdjustment{PriceListId: 1, Kind: model.AdjustmentColor, Value: "لاکی"},
+		},
+		{
+			Name:       "FullDiscount",
+			Adjustment: model.PriceAdjustment{PriceListId: 1, Kind: model.AdjustmentColor, Value: "لاکی", Percent: -100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := priceAdjustmentIsValid(tt.Adjustment)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
+
+func TestPriceQuoteIsValid(t *testing.T) {
+
+	tests := []struct {
+		Name  string
+		Quote model.PriceQuote
+		Valid bool
+	}{
+		{
+			Name:  "OkSize",
+			Quote: model.PriceQuote{PriceListId: 1, ProductId: 1, Size: "6"},
+			Valid: true,
+		},
+		{
+			Name:  "OkCustomSize",
+			Quote: model.PriceQuote{PriceListId: 1, ProductId: 1, Width: 80, Length: 300, Shape: model.ShapeRunner},
+			Valid: true,
+		},
+		{
+			Name:  "EmptySize",
+			Quote: model.PriceQuote{PriceListId: 1, ProductId: 1, Width: 80},
+		},
+		{
+			Name:  "ZeroProduct",
+			Quote: model.PriceQuote{PriceListId: 1, Size: "6"},
+		},
+		{
+			Name:  "InvalidShape",
+			Quote: model.PriceQuote{PriceListId: 1, ProductId: 1, Size: "6", Shape: "oval"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := priceQuoteIsValid(tt.Quote)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+	"time"
+)
+
+// ReserveStock hold available pieces of a carpet in a warehouse for a holder until expiry
+func (g *gateway) ReserveStock(ctx context.Context, req *api.ReserveStockRequest) (res *api.ReserveStockResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.ReserveStock", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	modelReservation := api.ReservationApiToModel(req.Reservation)
+	if req.CarpetId != "" {
+		carpetSku, err := carpetSkuOfCompany(req.CompanyId, req.CarpetId)
+		if err != nil {
+			return nil, err
+		}
+		modelReservation.ProductId = carpetSku.ProductId
+		modelReservation.DimensionId = carpetSku.DimensionId
+		modelReservation.ThemeId = carpetSku.ThemeId
+	}
+
+	if err := reservationIsValid(*modelReservation, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if modelReservation.Id != 0 {
+		return nil, derror.New(derror.InvalidReservation, "invalid reservation id")
+	}
+
+	reservation, err := g.product.ReserveStock(*modelReservation)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.ReserveStockResponse{}
+	res.Reservation = *api.ReservationSchemaToApi(*reservation)
+	return res, nil
+}
+
+// ReleaseReservation cancel an active reservation
+func (g *gateway) ReleaseReservation(ctx context.Context, req *api.ReleaseReservationRequest) (res *api.ReleaseReservationResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.ReleaseReservation", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	if req.ReservationId == 0 {
+		return nil, derror.New(derror.InvalidReservation, "invalid reservation id")
+	}
+
+	reservation, err := g.product.ReleaseReservation(req.CompanyId, req.ReservationId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.ReleaseReservationResponse{}
+	res.Reservation = *api.ReservationSchemaToApi(*reservation)
+	return res, nil
+}
+
+// GetReservations return a page of reservations of company, newest first
+func (g *gateway) GetReservations(ctx context.Context, req *api.GetReservationsRequest) (res *api.GetReservationsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetReservations", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	filter := model.ReservationFilter{
+		CompanyId:   req.CompanyId,
+		WarehouseId: req.WarehouseId,
+		ProductId:   req.ProductId,
+		Holder:      req.Holder,
+		ActiveOnly:  req.ActiveOnly,
+	}
+
+	page := api.PageRequestToModel(req.PageRequest)
+	reservations, total, err := g.product.GetReservations(filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetReservationsResponse{Total: total, Page: page.Number, PageSize: page.Size}
+	res.Reservations = make([]api.Reservation, len(reservations))
+	for i, r := range reservations {
+		res.Reservations[i] = *api.ReservationSchemaToApi(r)
+	}
+	return res, nil
+}
+
+func reservationIsValid(r model.Reservation, now time.Time) error {
+
+	if r.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if r.WarehouseId == 0 {
+		return derror.New(derror.InvalidWarehouse, "invalid warehouse id")
+	}
+
+	if r.ProductId == 0 || r.DimensionId == 0 || r.ThemeId == 0 {
+		return derror.InvalidCarpet
+	}
+
+	if r.Quantity <= 0 {
+		return derror.New(derror.InvalidReservation, "invalid quantity")
+	}
+
+	if r.Holder == "" {
+		return derror.New(derror.InvalidReservation, "empty holder")
+	}
+
+	if !r.ExpiresAt.After(now) {
+		return derror.New(derror.InvalidReservation, "expiry is not in future")
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestReservationIsValid(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	tests := []struct {
+		Name        string
+		Reservation model.Reservation
+		Valid       bool
+	}{
+		{
+			Name:        "Ok",
+			Reservation: model.Reservation{CompanyId: 1, WarehouseId: 1, ProductId: 1, DimensionId: 1, ThemeId: 1, Quantity: 2, Holder: "فروشگاه شیراز", ExpiresAt: later},
+			Valid:       true,
+		},
+		{
+			Name:        "ZeroCompany",
+			Reservation: model.Reservation{WarehouseId: 1, ProductId: 1, DimensionId: 1, ThemeId: 1, Quantity: 2, Holder: "فروشگاه شیراز", ExpiresAt: later},
+		},
+		{
+			Name:        "ZeroWarehouse",
+			Reservation: model.Reservation{CompanyId: 1, ProductId: 1, DimensionId: 1, ThemeId: 1, Quantity: 2, Holder: "فروشگاه شیراز", ExpiresAt: later},
+		},
+		{
+			Name:        "ZeroDimension",
+			Reservation: model.Reservation{CompanyId: 1, WarehouseId: 1, ProductId: 1, ThemeId: 1, Quantity: 2, Holder: "فروشگاه شیراز", ExpiresAt: later},
+		},
+		{
+			Name:        "ZeroQuantity",
+			Reservation: model.Reservation{CompanyId: 1, WarehouseId: 1, ProductId: 1, DimensionId: 1, ThemeId: 1, Holder: "فروشگاه شیراز", ExpiresAt: later},
+		},
+		{
+			Name:        "EmptyHolder",
+			Reservation: model.Reservation{CompanyId: 1, WarehouseId: 1, ProductId: 1, DimensionId: 1, ThemeId: 1, Quantity: 2, ExpiresAt: later},
+		},
+		{
+			Name:        "Expired",
+			Reservation: model.Reservation{CompanyId: 1, WarehouseId: 1, ProductId: 1, DimensionId: 1, ThemeId: 1, Quantity: 2, Holder: "فروشگاه شیراز", ExpiresAt: now},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := reservationIsValid(tt.Reservation, now)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+)
+
+type roleKey struct{}
+
+// WithRole return a copy of `ctx` carrying role of caller
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+// canSeeDrafts check caller of `ctx` can see draft products
+func canSeeDrafts(ctx context.Context) bool {
+	role, _ := ctx.Value(roleKey{}).(string)
+	return model.RoleCanSeeDrafts(role)
+}
+
+// statusChangeIsAllowed return derror.AccessDenied if caller of `ctx` can't move a product to `status`,
+// only an editor or an admin publishes a product or moves it back to draft
+func statusChangeIsAllowed(ctx context.Context, status string) error {
+	role, _ := ctx.Value(roleKey{}).(string)
+	if (status == model.StatusPublished || status == model.StatusDraft) && !model.RoleCanPublish(role) {
+		return derror.New(derror.AccessDenied, fmt.Sprintf("role %q can't move a product to %v", role, status))
+	}
+	return nil
+}
+
+// productsToApi convert `products` to api products, drafts are dropped if caller can't see them
+func productsToApi(ctx context.Context, products []schema.Product) []api.Product {
+	seeDrafts := canSeeDrafts(ctx)
+	result := make([]api.Product, 0, len(products))
+	for _, p := range products {
+		if p.Status == model.StatusDraft && !seeDrafts {
+			continue
+		}
+		result = append(result, *api.ProductSchemaToApi(p))
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/seed95/product-service/internal/repo/product/schema"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestProductsToApi(t *testing.T) {
+	products := []schema.Product{
+		{Model: gorm.Model{ID: 1}, Status: model.StatusPublished},
+		{Model: gorm.Model{ID: 2}, Status: model.StatusDraft},
+		{Model: gorm.Model{ID: 3}, Status: model.StatusDiscontinued},
+	}
+
+	t.Run("viewer", func(t *testing.T) {
+		result := productsToApi(context.Background(), products)
+		require.Equal(t, 2, len(result))
+		require.Equal(t, uint(1), result[0].Id)
+		require.Equal(t, uint(3), result[1].Id)
+	})
+
+	t.Run("editor", func(t *testing.T) {
+		result := productsToApi(WithRole(context.Background(), model.RoleEditor), products)
+		require.Equal(t, 3, len(result))
+	})
+}
+
+func TestStatusChangeIsAllowed(t *testing.T) {
+	viewer := WithRole(context.Background(), model.RoleViewer)
+	editor := WithRole(context.Background(), model.RoleEditor)
+
+	require.NotNil(t, statusChangeIsAllowed(context.Background(), model.StatusPublished))
+	require.NotNil(t, statusChangeIsAllowed(viewer, model.StatusPublished))
+	require.NotNil(t, statusChangeIsAllowed(viewer, model.StatusDraft))
+	require.Nil(t, statusChangeIsAllowed(viewer, model.StatusArchived))
+	require.Nil(t, statusChangeIsAllowed(editor, model.StatusPublished))
+	require.Nil(t, statusChangeIsAllowed(editor, model.StatusDraft))
+}
@@ -10,6 +10,7 @@ import (
 	kitlog "github.com/seed95/product-service/pkg/logger"
 	"github.com/seed95/product-service/pkg/logger/keyval"
 	"github.com/seed95/product-service/pkg/unique"
+	"io"
 )
 
 type ProductService interface {
@@ -18,6 +19,78 @@ type ProductService interface {
 	GetProductWithId(ctx context.Context, productId uint) (res *api.GetProductResponse, err error)
 	DeleteProduct(ctx context.Context, productId uint) (err error)
 	EditProduct(ctx context.Context, req *api.EditProductRequest) (res *api.EditProductResponse, err error)
+	SearchProducts(ctx context.Context, req *api.SearchProductsRequest) (res *api.GetAllProductsResponse, err error)
+	ChangeProductStatus(ctx context.Context, req *api.ChangeProductStatusRequest) (res *api.ChangeProductStatusResponse, err error)
+	CloneProduct(ctx context.Context, req *api.CloneProductRequest) (res *api.CloneProductResponse, err error)
+	BulkEditProducts(ctx context.Context, req *api.BulkEditProductsRequest) (res *api.BulkEditProductsResponse, err error)
+	GetProductWithDesignCode(ctx context.Context, req *api.GetProductWithDesignCodeRequest) (res *api.GetProductResponse, err error)
+	RenameDesignCode(ctx context.Context, req *api.RenameDesignCodeRequest) (res *api.EditProductResponse, err error)
+	GetProductAliases(ctx context.Context, req *api.GetProductAliasesRequest) (res *api.GetProductAliasesResponse, err error)
+	MergeProducts(ctx context.Context, req *api.MergeProductsRequest) (res *api.EditProductResponse, err error)
+	GetProductHistory(ctx context.Context, req *api.GetProductHistoryRequest) (res *api.GetProductHistoryResponse, err error)
+	AddThemes(ctx context.Context, req *api.AddThemesRequest) (res *api.EditProductResponse, err error)
+	RemoveThemes(ctx context.Context, req *api.RemoveThemesRequest) (res *api.EditProductResponse, err error)
+	AddDimensions(ctx context.Context, req *api.AddDimensionsRequest) (res *api.EditProductResponse, err error)
+	RemoveDimensions(ctx context.Context, req *api.RemoveDimensionsRequest) (res *api.EditProductResponse, err error)
+	RenameTheme(ctx context.Context, req *api.RenameThemeRequest) (res *api.EditProductResponse, err error)
+	RenameDimension(ctx context.Context, req *api.RenameDimensionRequest) (res *api.EditProductResponse, err error)
+	ImportProducts(ctx context.Context, req *api.ImportProductsRequest) (res *api.ImportProductsResponse, err error)
+	ExportCatalog(ctx context.Context, req *api.ExportCatalogRequest, w io.Writer) (nextAfterId uint, err error)
+	DumpCatalog(ctx context.Context, req *api.DumpCatalogRequest, w io.Writer) (err error)
+	RestoreCatalog(ctx context.Context, req *api.RestoreCatalogRequest) (res *api.RestoreCatalogResponse, err error)
+
+	CreateStandardSize(ctx context.Context, req *api.CreateStandardSizeRequest) (res *api.CreateStandardSizeResponse, err error)
+	GetStandardSizes(ctx context.Context, companyId uint) (res *api.GetStandardSizesResponse, err error)
+	EditStandardSize(ctx context.Context, req *api.EditStandardSizeRequest) (res *api.EditStandardSizeResponse, err error)
+	DeleteStandardSize(ctx context.Context, req *api.DeleteStandardSizeRequest) (err error)
+	GetStandardSizeProducts(ctx context.Context, companyId uint) (res *api.GetStandardSizeProductsResponse, err error)
+
+	CreateAttributeDefinition(ctx context.Context, req *api.CreateAttributeDefinitionRequest) (res *api.CreateAttributeDefinitionResponse, err error)
+	GetAttributeDefinitions(ctx context.Context, companyId uint) (res *api.GetAttributeDefinitionsResponse, err error)
+	EditAttributeDefinition(ctx context.Context, req *api.EditAttributeDefinitionRequest) (res *api.EditAttributeDefinitionResponse, err error)
+	DeleteAttributeDefinition(ctx context.Context, req *api.DeleteAttributeDefinitionRequest) (err error)
+
+	CreateCategory(ctx context.Context, req *api.CreateCategoryRequest) (res *api.CreateCategoryResponse, err error)
+	GetCategories(ctx context.Context, companyId uint) (res *api.GetCategoriesResponse, err error)
+	MoveCategory(ctx context.Context, req *api.MoveCategoryRequest) (res *api.MoveCategoryResponse, err error)
+	DeleteCategory(ctx context.Context, req *api.DeleteCategoryRequest) (err error)
+	GetCategoryProducts(ctx context.Context, req *api.GetCategoryProductsRequest) (res *api.GetAllProductsResponse, err error)
+
+	AddTags(ctx context.Context, req *api.TagProductsRequest) (res *api.GetAllProductsResponse, err error)
+	RemoveTags(ctx context.Context, req *api.TagProductsRequest) (res *api.GetAllProductsResponse, err error)
+	GetTags(ctx context.Context, companyId uint) (res *api.GetTagsResponse, err error)
+
+	GetCarpets(ctx context.Context, req *api.GetCarpetsRequest) (res *api.GetCarpetsResponse, err error)
+	GetProductCarpets(ctx context.Context, req *api.GetProductCarpetsRequest) (res *api.GetCarpetsResponse, err error)
+	GetCarpet(ctx context.Context, req *api.GetCarpetRequest) (res *api.GetCarpetResponse, err error)
+	ExcludeCarpet(ctx context.Context, req *api.ExcludeCarpetRequest) (res *api.ExcludeCarpetResponse, err error)
+	IncludeCarpet(ctx context.Context, req *api.IncludeCarpetRequest) (err error)
+	GetCarpetExclusions(ctx context.Context, req *api.GetCarpetExclusionsRequest) (res *api.GetCarpetExclusionsResponse, err error)
+
+	GetCarpetLabel(ctx context.Context, req *api.GetCarpetLabelRequest) (res *api.LabelResponse, err error)
+	GetLabelSheet(ctx context.Context, req *api.GetLabelSheetRequest) (res *api.GetLabelSheetResponse, err error)
+
+	CreatePriceList(ctx context.Context, req *api.CreatePriceListRequest) (res *api.CreatePriceListResponse, err error)
+	GetPriceLists(ctx context.Context, companyId uint) (res *api.GetPriceListsResponse, err error)
+	DeletePriceList(ctx context.Context, req *api.DeletePriceListRequest) (err error)
+	AddPrice(ctx context.Context, req *api.AddPriceRequest) (res *api.AddPriceResponse, err error)
+	GetPrices(ctx context.Context, req *api.GetPricesRequest) (res *api.GetPricesResponse, err error)
+	DeletePrice(ctx context.Context, req *api.DeletePriceRequest) (err error)
+	ResolvePrice(ctx context.Context, req *api.ResolvePriceRequest) (res *api.ResolvePriceResponse, err error)
+	QuotePrice(ctx context.Context, req *api.QuotePriceRequest) (res *api.QuotePriceResponse, err error)
+	AddPriceAdjustment(ctx context.Context, req *api.AddPriceAdjustmentRequest) (res *api.AddPriceAdjustmentResponse, err error)
+	GetPriceAdjustments(ctx context.Context, req *api.GetPriceAdjustmentsRequest) (res *api.GetPriceAdjustmentsResponse, err error)
+	DeletePriceAdjustment(ctx context.Context, req *api.DeletePriceAdjustmentRequest) (err error)
+
+	CreateWarehouse(ctx context.Context, req *api.CreateWarehouseRequest) (res *api.CreateWarehouseResponse, err error)
+	GetWarehouses(ctx context.Context, companyId uint) (res *api.GetWarehousesResponse, err error)
+	DeleteWarehouse(ctx context.Context, req *api.DeleteWarehouseRequest) (err error)
+	RecordStockMovement(ctx context.Context, req *api.RecordStockMovementRequest) (res *api.RecordStockMovementResponse, err error)
+	GetStockMovements(ctx context.Context, req *api.GetStockMovementsRequest) (res *api.GetStockMovementsResponse, err error)
+	GetStock(ctx context.Context, req *api.GetStockRequest) (res *api.GetStockResponse, err error)
+	ReserveStock(ctx context.Context, req *api.ReserveStockRequest) (res *api.ReserveStockResponse, err error)
+	ReleaseReservation(ctx context.Context, req *api.ReleaseReservationRequest) (res *api.ReleaseReservationResponse, err error)
+	GetReservations(ctx context.Context, req *api.GetReservationsRequest) (res *api.GetReservationsResponse, err error)
 }
 
 type (
@@ -50,6 +123,7 @@ func (g *gateway) CreateNewProduct(ctx context.Context, req api.CreateNewProduct
 	}()
 
 	modelProduct := api.ProductApiToModel(req.Product)
+	modelProduct.Normalize()
 	if err := productIsValid(*modelProduct); err != nil {
 		return nil, err
 	}
@@ -58,6 +132,11 @@ func (g *gateway) CreateNewProduct(ctx context.Context, req api.CreateNewProduct
 		return nil, derror.New(derror.InvalidProduct, "invalid product id")
 	}
 
+	// A product starts as draft or published
+	if s := modelProduct.Status; s != "" && s != model.StatusDraft && s != model.StatusPublished {
+		return nil, derror.New(derror.InvalidStatus, "invalid initial status "+s)
+	}
+
 	_, err = g.product.CreateProduct(*modelProduct)
 	if err != nil {
 		return nil, err
@@ -86,10 +165,7 @@ func (g *gateway) GetAllProducts(ctx context.Context, companyId uint) (res *api.
 	}
 
 	res = &api.GetAllProductsResponse{}
-	res.Products = make([]api.Product, len(allProducts))
-	for i, p := range allProducts {
-		res.Products[i] = *api.ProductSchemaToApi(p)
-	}
+	res.Products = productsToApi(ctx, allProducts)
 	return res, nil
 }
 
@@ -112,6 +188,10 @@ func (g *gateway) GetProductWithId(ctx context.Context, productId uint) (res *ap
 		return nil, err
 	}
 
+	if schemaProduct.Status == model.StatusDraft && !canSeeDrafts(ctx) {
+		return nil, derror.ProductNotFound
+	}
+
 	res = &api.GetProductResponse{}
 	res.Product = *api.ProductSchemaToApi(*schemaProduct)
 	return res, nil
@@ -143,6 +223,7 @@ func (g *gateway) EditProduct(ctx context.Context, req *api.EditProductRequest)
 	}()
 
 	modelProduct := api.ProductApiToModel(req.Product)
+	modelProduct.Normalize()
 	if err := productIsValid(*modelProduct); err != nil {
 		return nil, err
 	}
@@ -161,6 +242,89 @@ func (g *gateway) EditProduct(ctx context.Context, req *api.EditProductRequest)
 	return res, nil
 }
 
+func (g *gateway) SearchProducts(ctx context.Context, req *api.SearchProductsRequest) (res *api.GetAllProductsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.SearchProducts", err, commonKeyVal...)
+	}()
+
+	filter, err := searchFilter(ctx, *req)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := g.product.SearchProducts(*filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetAllProductsResponse{}
+	res.Products = productsToApi(ctx, products)
+	return res, nil
+}
+
+// searchFilter validate `req` and return its filter, drafts are hidden if caller can't see them
+func searchFilter(ctx context.Context, req api.SearchProductsRequest) (*model.ProductFilter, error) {
+	filter := api.SearchProductsRequestToFilter(req)
+	if filter.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	if !model.TagMatchIsValid(filter.TagMatch) {
+		return nil, derror.New(derror.InvalidTag, "invalid tag match "+filter.TagMatch)
+	}
+
+	for _, s := range filter.Statuses {
+		if !model.StatusIsValid(s) {
+			return nil, derror.New(derror.InvalidStatus, s)
+		}
+	}
+
+	filter.HideDrafts = !canSeeDrafts(ctx)
+	return filter, nil
+}
+
+// ChangeProductStatus move a product through its lifecycle
+func (g *gateway) ChangeProductStatus(ctx context.Context, req *api.ChangeProductStatusRequest) (res *api.ChangeProductStatusResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.ChangeProductStatus", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	if req.ProductId == 0 {
+		return nil, derror.InvalidProduct
+	}
+
+	if !model.StatusIsValid(req.Status) {
+		return nil, derror.New(derror.InvalidStatus, req.Status)
+	}
+
+	if err := statusChangeIsAllowed(ctx, req.Status); err != nil {
+		return nil, err
+	}
+
+	product, err := g.product.ChangeProductStatus(req.CompanyId, req.ProductId, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.ChangeProductStatusResponse{}
+	res.Product = *api.ProductSchemaToApi(*product)
+	return res, nil
+}
+
 func productIsValid(p model.Product) error {
 
 	// Check empty color
@@ -175,8 +339,8 @@ func productIsValid(p model.Product) error {
 		}
 	}
 
-	// Check empty size
-	if len(p.Sizes) == 0 {
+	// Check empty size, a product can offer only catalog sizes
+	if len(p.Sizes) == 0 && len(p.StandardSizes) == 0 {
 		return derror.New(derror.InvalidProduct, "empty size")
 	}
 
@@ -197,11 +361,16 @@ func productIsValid(p model.Product) error {
 		return derror.New(derror.InvalidProduct, "not unique size")
 	}
 
-	if p.DesignCode != "" {
+	// Check unique catalog size
+	if !unique.UintsAreUnique(p.StandardSizes) {
+		return derror.New(derror.InvalidProduct, "not unique standard size")
+	}
+
+	if p.DesignCode == "" {
 		return derror.New(derror.InvalidProduct, "empty design code")
 	}
 
-	if p.CompanyId != 0 {
+	if p.CompanyId == 0 {
 		return derror.New(derror.InvalidProduct, "invalid company id")
 	}
 
@@ -17,7 +17,7 @@ func TestGateway_CreateNewProduct_Ok(t *testing.T) {
 	ctx := context.Background()
 	req := api.CreateNewProductRequest{Product: GetProduct1()}
 
-	res, err := service.CreateNewProduct(ctx, &req)
+	res, err := service.CreateNewProduct(ctx, req)
 	require.Nil(t, err)
 	require.Equal(t, 1, len(res.Products))
 }
@@ -32,7 +32,7 @@ func TestGateway_CreateNewProduct_Empty(t *testing.T) {
 		p1.DesignCode = ""
 		req := api.CreateNewProductRequest{Product: p1}
 
-		res, err := service.CreateNewProduct(ctx, &req)
+		res, err := service.CreateNewProduct(ctx, req)
 		require.Equal(t, derror.InvalidProduct, err)
 		require.Nil(t, res)
 	})
@@ -42,7 +42,7 @@ func TestGateway_CreateNewProduct_Empty(t *testing.T) {
 		p1.Colors = []string{}
 		req := api.CreateNewProductRequest{Product: p1}
 
-		res, err := service.CreateNewProduct(ctx, &req)
+		res, err := service.CreateNewProduct(ctx, req)
 		require.Equal(t, derror.InvalidProduct, err)
 		require.Nil(t, res)
 	})
@@ -52,7 +52,7 @@ func TestGateway_CreateNewProduct_Empty(t *testing.T) {
 		p1.Sizes = []string{}
 		req := api.CreateNewProductRequest{Product: p1}
 
-		res, err := service.CreateNewProduct(ctx, &req)
+		res, err := service.CreateNewProduct(ctx, req)
 		require.Equal(t, derror.InvalidProduct, err)
 		require.Nil(t, res)
 	})
@@ -62,7 +62,7 @@ func TestGateway_CreateNewProduct_Empty(t *testing.T) {
 		p1.Description = ""
 		req := api.CreateNewProductRequest{Product: p1}
 
-		res, err := service.CreateNewProduct(ctx, &req)
+		res, err := service.CreateNewProduct(ctx, req)
 		require.Nil(t, err)
 		require.Equal(t, 1, len(res.Products))
 	})
@@ -76,7 +76,7 @@ func TestGateway_CreateNewProduct_NonZeroId(t *testing.T) {
 	req := api.CreateNewProductRequest{Product: GetProduct1()}
 	req.Product.Id = 10
 
-	res, err := service.CreateNewProduct(ctx, &req)
+	res, err := service.CreateNewProduct(ctx, req)
 	require.Equal(t, derror.InvalidProduct, err)
 	require.Nil(t, res)
 }
@@ -89,7 +89,7 @@ func TestGateway_CreateNewProduct_CompanyZeroId(t *testing.T) {
 	req := api.CreateNewProductRequest{Product: GetProduct1()}
 	req.Product.CompanyId = 0
 
-	res, err := service.CreateNewProduct(ctx, &req)
+	res, err := service.CreateNewProduct(ctx, req)
 	require.Equal(t, derror.InvalidProduct, err)
 	require.Nil(t, res)
 }
@@ -557,8 +557,12 @@ func TestProductIsValid(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.Name, func(t *testing.T) {
-			valid := productIsValid(tt.Product)
-			require.Equal(t, tt.Valid, valid, tt.Product)
+			err := productIsValid(tt.Product)
+			if tt.Valid {
+				require.Nil(t, err, tt.Product)
+			} else {
+				require.NotNil(t, err, tt.Product)
+			}
 		})
 	}
 
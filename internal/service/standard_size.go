@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+func (g *gateway) CreateStandardSize(ctx context.Context, req *api.CreateStandardSizeRequest) (res *api.CreateStandardSizeResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.CreateStandardSize", err, commonKeyVal...)
+	}()
+
+	modelSize := api.StandardSizeApiToModel(req.StandardSize)
+	modelSize.Label = model.NormalizeText(modelSize.Label)
+	if err := standardSizeIsValid(*modelSize); err != nil {
+		return nil, err
+	}
+
+	if modelSize.Id != 0 {
+		return nil, derror.New(derror.InvalidStandardSize, "invalid standard size id")
+	}
+
+	standardSize, err := g.product.CreateStandardSize(*modelSize)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.CreateStandardSizeResponse{}
+	res.StandardSize = *api.StandardSizeSchemaToApi(*standardSize)
+	return res, nil
+}
+
+func (g *gateway) GetStandardSizes(ctx context.Context, companyId uint) (res *api.GetStandardSizesResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetStandardSizes", err, commonKeyVal...)
+	}()
+
+	if companyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	standardSizes, err := g.product.GetStandardSizes(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetStandardSizesResponse{}
+	res.StandardSizes = make([]api.StandardSize, len(standardSizes))
+	for i, s := range standardSizes {
+		res.StandardSizes[i] = *api.StandardSizeSchemaToApi(s)
+	}
+	return res, nil
+}
+
+func (g *gateway) EditStandardSize(ctx context.Context, req *api.EditStandardSizeRequest) (res *api.EditStandardSizeResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.EditStandardSize", err, commonKeyVal...)
+	}()
+
+	modelSize := api.StandardSizeApiToModel(req.StandardSize)
+	modelSize.Label = model.NormalizeText(modelSize.Label)
+	if err := standardSizeIsValid(*modelSize); err != nil {
+		return nil, err
+	}
+
+	if modelSize.Id == 0 {
+		return nil, derror.New(derror.InvalidStandardSize, "invalid standard size id")
+	}
+
+	standardSize, err := g.product.EditStandardSize(*modelSize)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.EditStandardSizeResponse{}
+	res.StandardSize = *api.StandardSizeSchemaToApi(*standardSize)
+	return res, nil
+}
+
+func (g *gateway) DeleteStandardSize(ctx context.Context, req *api.DeleteStandardSizeRequest) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+		}
+		kitlog.LogReqRes(g.logger, "service.DeleteStandardSize", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if req.StandardSizeId == 0 {
+		return derror.InvalidStandardSize
+	}
+
+	return g.product.DeleteStandardSize(req.CompanyId, req.StandardSizeId)
+}
+
+func (g *gateway) GetStandardSizeProducts(ctx context.Context, companyId uint) (res *api.GetStandardSizeProductsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetStandardSizeProducts", err, commonKeyVal...)
+	}()
+
+	if companyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	report, err := g.product.GetStandardSizeProducts(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetStandardSizeProductsResponse{}
+	res.StandardSizes = make([]api.StandardSizeProducts, len(report))
+	for i, r := range report {
+		res.StandardSizes[i].StandardSize = *api.StandardSizeModelToApi(r.StandardSize)
+		res.StandardSizes[i].Products = make([]api.ProductCode, len(r.Products))
+		for j, p := range r.Products {
+			res.StandardSizes[i].Products[j] = api.ProductCode{ProductId: p.ProductId, DesignCode: p.DesignCode}
+		}
+	}
+	return res, nil
+}
+
+func standardSizeIsValid(s model.StandardSize) error {
+
+	if s.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if s.Label == "" {
+		return derror.New(derror.InvalidStandardSize, "empty label")
+	}
+
+	// A size is either fully measured or not measured
+	if (s.Width == 0) != (s.Length == 0) {
+		return derror.New(derror.InvalidStandardSize, "incomplete measurement")
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStandardSizeIsValid(t *testing.T) {
+
+	tests := []struct {
+		Name  string
+		Size  model.StandardSize
+		Valid bool
+	}{
+		{
+			Name:  "Ok",
+			Size:  model.StandardSize{CompanyId: 1, Label: "6", Width: 200, Length: 300},
+			Valid: true,
+		},
+		{
+			Name:  "NoMeasurement",
+			Size:  model.StandardSize{CompanyId: 1, Label: "6"},
+			Valid: true,
+		},
+		{
+			Name: "ZeroCompany",
+			Size: model.StandardSize{Label: "6"},
+		},
+		{
+			Name: "EmptyLabel",
+			Size: model.StandardSize{CompanyId: 1},
+		},
+		{
+			Name: "IncompleteMeasurement",
+			Size: model.StandardSize{CompanyId: 1, Label: "6", Width: 200},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := standardSizeIsValid(tt.Size)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
+
+func TestGateway_GetStandardSizes_ZeroCompanyId(t *testing.T) {
+	// Service mock
+	service := NewServiceMock(t)
+
+	ctx := context.Background()
+	res, err := service.GetStandardSizes(ctx, 0)
+	require.Equal(t, derror.InvalidCompany, err)
+	require.Nil(t, res)
+}
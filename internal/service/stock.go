@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+func (g *gateway) CreateWarehouse(ctx context.Context, req *api.CreateWarehouseRequest) (res *api.CreateWarehouseResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.CreateWarehouse", err, commonKeyVal...)
+	}()
+
+	modelWarehouse := api.WarehouseApiToModel(req.Warehouse)
+	if err := warehouseIsValid(*modelWarehouse); err != nil {
+		return nil, err
+	}
+
+	if modelWarehouse.Id != 0 {
+		return nil, derror.New(derror.InvalidWarehouse, "invalid warehouse id")
+	}
+
+	warehouse, err := g.product.CreateWarehouse(*modelWarehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.CreateWarehouseResponse{}
+	res.Warehouse = *api.WarehouseSchemaToApi(*warehouse)
+	return res, nil
+}
+
+func (g *gateway) GetWarehouses(ctx context.Context, companyId uint) (res *api.GetWarehousesResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetWarehouses", err, commonKeyVal...)
+	}()
+
+	if companyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	warehouses, err := g.product.GetWarehouses(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetWarehousesResponse{}
+	res.Warehouses = make([]api.Warehouse, len(warehouses))
+	for i, w := range warehouses {
+		res.Warehouses[i] = *api.WarehouseSchemaToApi(w)
+	}
+	return res, nil
+}
+
+func (g *gateway) DeleteWarehouse(ctx context.Context, req *api.DeleteWarehouseRequest) (err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+		}
+		kitlog.LogReqRes(g.logger, "service.DeleteWarehouse", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if req.WarehouseId == 0 {
+		return derror.New(derror.InvalidWarehouse, "invalid warehouse id")
+	}
+
+	return g.product.DeleteWarehouse(req.CompanyId, req.WarehouseId)
+}
+
+// RecordStockMovement record a receipt, sale, adjustment or transfer of a carpet and return its ledger entries
+func (g *gateway) RecordStockMovement(ctx context.Context, req *api.RecordStockMovementRequest) (res *api.RecordStockMovementResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.RecordStockMovement", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	modelMovement := api.StockMovementApiToModel(req.StockMovement)
+	if req.CarpetId != "" {
+		carpetSku, err := carpetSkuOfCompany(req.CompanyId, req.CarpetId)
+		if err != nil {
+			return nil, err
+		}
+		modelMovement.ProductId = carpetSku.ProductId
+		modelMovement.DimensionId = carpetSku.DimensionId
+		modelMovement.ThemeId = carpetSku.ThemeId
+	}
+
+	if err := stockMovementIsValid(*modelMovement); err != nil {
+		return nil, err
+	}
+
+	if modelMovement.Id != 0 {
+		return nil, derror.New(derror.InvalidStockMovement, "invalid movement id")
+	}
+
+	entries, err := g.product.RecordStockMovement(*modelMovement)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.RecordStockMovementResponse{}
+	res.Movements = make([]api.StockMovement, len(entries))
+	for i, e := range entries {
+		res.Movements[i] = *api.StockMovementSchemaToApi(e)
+	}
+	return res, nil
+}
+
+// GetStockMovements return a page of stock ledger of company, newest first
+func (g *gateway) GetStockMovements(ctx context.Context, req *api.GetStockMovementsRequest) (res *api.GetStockMovementsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetStockMovements", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	if req.Kind != "" && !model.MovementKindIsValid(req.Kind) {
+		return nil, derror.New(derror.InvalidStockMovement, "invalid kind")
+	}
+
+	filter := model.MovementFilter{
+		CompanyId:   req.CompanyId,
+		WarehouseId: req.WarehouseId,
+		ProductId:   req.ProductId,
+		Kind:        req.Kind,
+	}
+	if req.CarpetId != "" {
+		carpetSku, err := carpetSkuOfCompany(req.CompanyId, req.CarpetId)
+		if err != nil {
+			return nil, err
+		}
+		filter.ProductId = carpetSku.ProductId
+		filter.DimensionId = carpetSku.DimensionId
+		filter.ThemeId = carpetSku.ThemeId
+	}
+
+	page := api.PageRequestToModel(req.PageRequest)
+	entries, total, err := g.product.GetStockMovements(filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetStockMovementsResponse{Total: total, Page: page.Number, PageSize: page.Size}
+	res.Movements = make([]api.StockMovement, len(entries))
+	for i, e := range entries {
+		res.Movements[i] = *api.StockMovementSchemaToApi(e)
+	}
+	return res, nil
+}
+
+// GetStock return a page of quantities of company carpets per warehouse
+func (g *gateway) GetStock(ctx context.Context, req *api.GetStockRequest) (res *api.GetStockResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetStock", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	page := api.PageRequestToModel(req.PageRequest)
+	levels, total, err := g.product.GetStock(req.CompanyId, req.WarehouseId, req.ProductId, page)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetStockResponse{Total: total, Page: page.Number, PageSize: page.Size}
+	res.Stock = make([]api.StockLevel, len(levels))
+	for i, l := range levels {
+		res.Stock[i] = *api.StockLevelModelToApi(l)
+	}
+	return res, nil
+}
+
+func warehouseIsValid(w model.Warehouse) error {
+
+	if w.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if w.Name == "" {
+		return derror.New(derror.InvalidWarehouse, "empty name")
+	}
+
+	return nil
+}
+
+func stockMovementIsValid(m model.StockMovement) error {
+
+	if m.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if m.WarehouseId == 0 {
+		return derror.New(derror.InvalidWarehouse, "invalid warehouse id")
+	}
+
+	if m.ProductId == 0 || m.DimensionId == 0 || m.ThemeId == 0 {
+		return derror.InvalidCarpet
+	}
+
+	if !model.MovementKindIsValid(m.Kind) {
+		return derror.New(derror.InvalidStockMovement, "invalid kind")
+	}
+
+	if err := m.Validate(); err != nil {
+		return derror.New(derror.InvalidStockMovement, "invalid quantity or destination warehouse")
+	}
+
+	return nil
+}
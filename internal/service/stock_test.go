@@ -0,0 +1,96 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWarehouseIsValid(t *testing.T) {
+
+	tests := []struct {
+		Name      string
+		Warehouse model.Warehouse
+		Valid     bool
+	}{
+		{
+			Name:      "Ok",
+			Warehouse: model.Warehouse{CompanyId: 1, Name: "انبار مرکزی"},
+			Valid:     true,
+		},
+		{
+			Name:      "ZeroCompany",
+			Warehouse: model.Warehouse{Name: "انبار مرکزی"},
+		},
+		{
+			Name:      "EmptyName",
+			Warehouse: model.Warehouse{CompanyId: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := warehouseIsValid(tt.Warehouse)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
+
+func TestStockMovementIsValid(t *testing.T) {
+
+	tests := []struct {
+		Name     string
+		Movement model.StockMovement
+		Valid    bool
+	}{
+		{
+			Name:     "OkReceipt",
+			Movement: model.StockMovement{CompanyId: 1, WarehouseId: 1, ProductId: 1, DimensionId: 1, ThemeId: 1, Kind: model.MovementReceipt, Quantity: 5},
+			Valid:    true,
+		},
+		{
+			Name:     "OkTransfer",
+			Movement: model.StockMovement{CompanyId: 1, WarehouseId: 1, ToWarehouseId: 2, ProductId: 1, DimensionId: 1, ThemeId: 1, Kind: model.MovementTransfer, Quantity: 5},
+			Valid:    true,
+		},
+		{
+			Name:     "ZeroCompany",
+			Movement: model.StockMovement{WarehouseId: 1, ProductId: 1, DimensionId: 1, ThemeId: 1, Kind: model.MovementReceipt, Quantity: 5},
+		},
+		{
+			Name:     "ZeroWarehouse",
+			Movement: model.StockMovement{CompanyId: 1, ProductId: 1, DimensionId: 1, ThemeId: 1, Kind: model.MovementReceipt, Quantity: 5},
+		},
+		{
+			Name:     "ZeroTheme",
+			Movement: model.StockMovement{CompanyId: 1, WarehouseId: 1, ProductId: 1, DimensionId: 1, Kind: model.MovementReceipt, Quantity: 5},
+		},
+		{
+			Name:     "InvalidKind",
+			Movement: model.StockMovement{CompanyId: 1, WarehouseId: 1, ProductId: 1, DimensionId: 1, ThemeId: 1, Kind: "return", Quantity: 5},
+		},
+		{
+			Name:     "NegativeSale",
+			Movement: model.StockMovement{CompanyId: 1, WarehouseId: 1, ProductId: 1, DimensionId: 1, ThemeId: 1, Kind: model.MovementSale, Quantity: -5},
+		},
+		{
+			Name:     "TransferWithoutDestination",
+			Movement: model.StockMovement{CompanyId: 1, WarehouseId: 1, ProductId: 1, DimensionId: 1, ThemeId: 1, Kind: model.MovementTransfer, Quantity: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := stockMovementIsValid(tt.Movement)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
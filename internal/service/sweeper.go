@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"github.com/seed95/product-service/internal/repo"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"time"
+)
+
+// DefaultSweepInterval is time between sweeps of expired reservations if interval isn't set
+const DefaultSweepInterval = time.Minute
+
+type (
+	// ReservationSweeper release expired reservations of every company in background,
+	// available quantities are correct before a sweep and sweeper only records release of reservations
+	ReservationSweeper struct {
+		product  repo.ProductRepo
+		interval time.Duration
+		logger   kitlog.Logger
+	}
+
+	SweeperSetting struct {
+		ProductRepo repo.ProductRepo
+		Interval    time.Duration
+		Logger      kitlog.Logger
+	}
+)
+
+func NewReservationSweeper(s *SweeperSetting) *ReservationSweeper {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultSweepInterval
+	}
+	return &ReservationSweeper{product: s.ProductRepo, interval: interval, logger: s.Logger}
+}
+
+// Run sweep expired reservations every interval until `ctx` is done
+func (s *ReservationSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// Errors are logged by repo, next tick retries
+			_, _ = s.Sweep(now)
+		}
+	}
+}
+
+// Sweep release reservations that are expired at `now` and return their number
+func (s *ReservationSweeper) Sweep(now time.Time) (int64, error) {
+	return s.product.ReleaseExpiredReservations(now)
+}
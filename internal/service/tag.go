@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+// AddTags add tags to products of request and return tagged products
+func (g *gateway) AddTags(ctx context.Context, req *api.TagProductsRequest) (res *api.GetAllProductsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.AddTags", err, commonKeyVal...)
+	}()
+
+	if err := tagProductsIsValid(*req); err != nil {
+		return nil, err
+	}
+
+	if err := g.product.AddTags(req.CompanyId, req.ProductIds, req.Tags); err != nil {
+		return nil, err
+	}
+
+	return g.SearchProducts(ctx, &api.SearchProductsRequest{CompanyId: req.CompanyId, ProductIds: req.ProductIds})
+}
+
+// RemoveTags remove tags from products of request and return the products
+func (g *gateway) RemoveTags(ctx context.Context, req *api.TagProductsRequest) (res *api.GetAllProductsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.RemoveTags", err, commonKeyVal...)
+	}()
+
+	if err := tagProductsIsValid(*req); err != nil {
+		return nil, err
+	}
+
+	if err := g.product.RemoveTags(req.CompanyId, req.ProductIds, req.Tags); err != nil {
+		return nil, err
+	}
+
+	return g.SearchProducts(ctx, &api.SearchProductsRequest{CompanyId: req.CompanyId, ProductIds: req.ProductIds})
+}
+
+func (g *gateway) GetTags(ctx context.Context, companyId uint) (res *api.GetTagsResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("company_id", fmt.Sprintf("%v", companyId)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.GetTags", err, commonKeyVal...)
+	}()
+
+	if companyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+
+	tags, err := g.product.GetTags(companyId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.GetTagsResponse{}
+	res.Tags = make([]api.TagUsage, len(tags))
+	for i, t := range tags {
+		res.Tags[i] = *api.TagUsageModelToApi(t)
+	}
+	return res, nil
+}
+
+func tagProductsIsValid(req api.TagProductsRequest) error {
+
+	if req.CompanyId == 0 {
+		return derror.InvalidCompany
+	}
+
+	if len(req.ProductIds) == 0 {
+		return derror.New(derror.InvalidProduct, "empty product ids")
+	}
+
+	for _, id := range req.ProductIds {
+		if id == 0 {
+			return derror.New(derror.InvalidProduct, "invalid product id")
+		}
+	}
+
+	if len(model.NormalizeTags(req.Tags)) == 0 {
+		return derror.New(derror.InvalidTag, "empty tags")
+	}
+
+	return nil
+}
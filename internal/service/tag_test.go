@@ -0,0 +1,49 @@
+package service
+
+import (
+	"github.com/seed95/product-service/internal/api"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTagProductsIsValid(t *testing.T) {
+
+	tests := []struct {
+		Name    string
+		Request api.TagProductsRequest
+		Valid   bool
+	}{
+		{
+			Name:    "Ok",
+			Request: api.TagProductsRequest{CompanyId: 1, ProductIds: []uint{1, 2}, Tags: []string{"کلاسیک"}},
+			Valid:   true,
+		},
+		{
+			Name:    "ZeroCompany",
+			Request: api.TagProductsRequest{ProductIds: []uint{1}, Tags: []string{"کلاسیک"}},
+		},
+		{
+			Name:    "EmptyProducts",
+			Request: api.TagProductsRequest{CompanyId: 1, Tags: []string{"کلاسیک"}},
+		},
+		{
+			Name:    "ZeroProductId",
+			Request: api.TagProductsRequest{CompanyId: 1, ProductIds: []uint{0}, Tags: []string{"کلاسیک"}},
+		},
+		{
+			Name:    "BlankTags",
+			Request: api.TagProductsRequest{CompanyId: 1, ProductIds: []uint{1}, Tags: []string{" ", "‌"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := tagProductsIsValid(tt.Request)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/seed95/product-service/internal/api"
+	"github.com/seed95/product-service/internal/derror"
+	"github.com/seed95/product-service/internal/model"
+	kitlog "github.com/seed95/product-service/pkg/logger"
+	"github.com/seed95/product-service/pkg/logger/keyval"
+)
+
+// AddThemes add colors to a product without resending its other colors
+func (g *gateway) AddThemes(ctx context.Context, req *api.AddThemesRequest) (res *api.EditProductResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.AddThemes", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+	if req.ProductId == 0 {
+		return nil, derror.InvalidProduct
+	}
+
+	colors := normalizeList(req.Colors)
+	if err := labelsAreValid(colors); err != nil {
+		return nil, derror.New(derror.InvalidColor, err.Error())
+	}
+
+	product, err := g.product.AddThemes(req.CompanyId, req.ProductId, colors)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.EditProductResponse{}
+	res.Product = *api.ProductSchemaToApi(*product)
+	return res, nil
+}
+
+// RemoveThemes remove colors of a product, a product keeps at least one color
+func (g *gateway) RemoveThemes(ctx context.Context, req *api.RemoveThemesRequest) (res *api.EditProductResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.RemoveThemes", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+	if req.ProductId == 0 {
+		return nil, derror.InvalidProduct
+	}
+
+	if err := idsAreValid(req.ThemeIds); err != nil {
+		return nil, derror.New(derror.InvalidTheme, err.Error())
+	}
+
+	product, err := g.product.RemoveThemes(req.CompanyId, req.ProductId, req.ThemeIds)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.EditProductResponse{}
+	res.Product = *api.ProductSchemaToApi(*product)
+	return res, nil
+}
+
+// RenameTheme change a color of a product in place, carpets of color keep their SKU
+func (g *gateway) RenameTheme(ctx context.Context, req *api.RenameThemeRequest) (res *api.EditProductResponse, err error) {
+	// Log request response
+	defer func() {
+		commonKeyVal := []keyval.Pair{
+			keyval.String("req", fmt.Sprintf("%+v", req)),
+			keyval.String("res", fmt.Sprintf("%+v", res)),
+		}
+		kitlog.LogReqRes(g.logger, "service.RenameTheme", err, commonKeyVal...)
+	}()
+
+	if req.CompanyId == 0 {
+		return nil, derror.InvalidCompany
+	}
+	if req.ProductId == 0 {
+		return nil, derror.InvalidProduct
+	}
+	if req.ThemeId == 0 {
+		return nil, derror.New(derror.InvalidColor, "zero id")
+	}
+
+	color := model.NormalizeText(req.Color)
+	if color == "" {
+		return nil, derror.New(derror.InvalidColor, "empty color")
+	}
+
+	product, err := g.product.RenameTheme(req.CompanyId, req.ProductId, req.ThemeId, color)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &api.EditProductResponse{}
+	res.Product = *api.ProductSchemaToApi(*product)
+	return res, nil
+}
+
+// labelsAreValid check `labels` (colors or sizes) is not empty and has no empty or repeated label
+func labelsAreValid(labels []string) error {
+	if len(labels) == 0 {
+		return fmt.Errorf("empty list")
+	}
+	seen := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		if l == "" {
+			return fmt.Errorf("empty value")
+		}
+		if seen[l] {
+			return fmt.Errorf("%v is repeated", l)
+		}
+		seen[l] = true
+	}
+	return nil
+}
+
+// idsAreValid check `ids` is not empty and has no zero or repeated id
+func idsAreValid(ids []uint) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("empty list")
+	}
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if id == 0 {
+			return fmt.Errorf("zero id")
+		}
+		if seen[id] {
+			return fmt.Errorf("id %v is repeated", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
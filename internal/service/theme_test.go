@@ -0,0 +1,80 @@
+package service
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLabelsAreValid(t *testing.T) {
+
+	tests := []struct {
+		Name   string
+		Labels []string
+		Valid  bool
+	}{
+		{
+			Name:   "Ok",
+			Labels: []string{"قرمز", "سبز"},
+			Valid:  true,
+		},
+		{
+			Name: "Empty",
+		},
+		{
+			Name:   "EmptyValue",
+			Labels: []string{"قرمز", ""},
+		},
+		{
+			Name:   "Repeated",
+			Labels: []string{"6", "9", "6"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := labelsAreValid(tt.Labels)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
+
+func TestIdsAreValid(t *testing.T) {
+
+	tests := []struct {
+		Name  string
+		Ids   []uint
+		Valid bool
+	}{
+		{
+			Name:  "Ok",
+			Ids:   []uint{1, 2},
+			Valid: true,
+		},
+		{
+			Name: "Empty",
+		},
+		{
+			Name: "Zero",
+			Ids:  []uint{1, 0},
+		},
+		{
+			Name: "Repeated",
+			Ids:  []uint{3, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := idsAreValid(tt.Ids)
+			if tt.Valid {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
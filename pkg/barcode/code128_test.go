@@ -0,0 +1,58 @@
+package barcode
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCode128Patterns(t *testing.T) {
+	require.Equal(t, 107, len(code128Patterns))
+
+	seen := make(map[string]bool)
+	for i, p := range code128Patterns {
+		sum := 0
+		for _, w := range p {
+			sum += int(w - '0')
+		}
+		if i == code128Stop {
+			require.Equal(t, 13, sum)
+		} else {
+			require.Equal(t, 11, sum, "pattern %v", i)
+		}
+		require.False(t, seen[p], "duplicate pattern %v", i)
+		seen[p] = true
+	}
+}
+
+func TestCode128(t *testing.T) {
+	modules, err := Code128("P12D14T13")
+	require.Nil(t, err)
+	// Start, 9 characters and checksum of 11 modules and stop of 13 modules
+	require.Equal(t, 11*11+13, len(modules))
+	require.True(t, modules[0])
+	require.True(t, modules[len(modules)-1])
+
+	// Checksum of PJJ123C is (104 + 48*1 + 42*2 + 42*3 + 17*4 + 18*5 + 19*6 + 35*7) % 103 = 55
+	modules, err = Code128("PJJ123C")
+	require.Nil(t, err)
+	checksum := modules[len(modules)-24 : len(modules)-13]
+	require.Equal(t, patternModules(code128Patterns[55]), checksum)
+
+	_, err = Code128("")
+	require.Equal(t, ErrInvalidCode128, err)
+
+	_, err = Code128("قرمز")
+	require.Equal(t, ErrInvalidCode128, err)
+}
+
+func patternModules(p string) []bool {
+	var result []bool
+	bar := true
+	for _, w := range p {
+		for i := 0; i < int(w-'0'); i++ {
+			result = append(result, bar)
+		}
+		bar = !bar
+	}
+	return result
+}
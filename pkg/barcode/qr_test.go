@@ -0,0 +1,177 @@
+package barcode
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// HELLO WORLD in version 1-M
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	ec := reedSolomonRemainder(data, reedSolomonDivisor(10))
+	require.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, ec)
+}
+
+func TestQRFormatBits(t *testing.T) {
+	require.Equal(t, 0b101010000010010, qrFormatBits(qrECLevelM, 0))
+	require.Equal(t, 0b111011111000100, qrFormatBits(1, 0)) // Level L mask 0
+}
+
+func TestQRVersionBits(t *testing.T) {
+	require.Equal(t, 0b000111110010010100, qrVersionBits(7))
+}
+
+func TestQRVersions(t *testing.T) {
+	for i, v := range qrVersions {
+		version := i + 1
+		size := version*4 + 17
+		// Data and error correction codewords fill all data modules
+		total := v.dataCodewords() + v.ecPerBlock*len(v.blocks)
+		require.Equal(t, qrRawModules(version)/8, total, "version %v", version)
+		if version > 1 {
+			require.Equal(t, size-7, v.alignment[len(v.alignment)-1])
+		}
+	}
+}
+
+func TestQR(t *testing.T) {
+	for _, data := range []string{"P12D14T13", "1-P123D4567T8901-5", string(make([]byte, 200))} {
+		modules, err := QR([]byte(data))
+		require.Nil(t, err)
+
+		got, err := qrRead(modules)
+		require.Nil(t, err)
+		require.Equal(t, data, string(got))
+	}
+
+	_, err := QR(make([]byte, 300))
+	require.Equal(t, ErrQRTooLong, err)
+}
+
+// qrRawModules return number of data modules of `version`
+func qrRawModules(version int) int {
+	size := version*4 + 17
+	result := size * size
+	result -= 3 * 64          // Finder patterns and separators
+	result -= 2 * (size - 16) // Timing patterns
+	result -= 31              // Format information and dark module
+	if version >= 2 {
+		n := version/7 + 2
+		result -= (n*n - 3) * 25
+		result += 2 * (n - 2) * 5 // Alignment patterns overlap timing patterns
+	}
+	if version >= 7 {
+		result -= 36
+	}
+	return result
+}
+
+// qrRead decode byte mode data of a qr code of this package, checking format and error correction
+func qrRead(modules [][]bool) ([]byte, error) {
+	size := len(modules)
+	version := (size - 17) / 4
+	v := qrVersions[version-1]
+
+	// Format from first copy
+	format := 0
+	bit := func(x, y int) int {
+		if modules[y][x] {
+			return 1
+		}
+		return 0
+	}
+	for i := 0; i <= 5; i++ {
+		format |= bit(8, i) << i
+	}
+	format |= bit(8, 7)<<6 | bit(8, 8)<<7 | bit(7, 8)<<8
+	for i := 9; i < 15; i++ {
+		format |= bit(14-i, 8) << i
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if qrFormatBits(qrECLevelM, m) == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		return nil, ErrQRTooLong
+	}
+
+	// Unmask and read codewords with placement of encoder
+	q := &qrCode{size: size, modules: make([][]bool, size), isFunction: make([][]bool, size)}
+	for i := range q.modules {
+		q.modules[i] = make([]bool, size)
+		q.isFunction[i] = make([]bool, size)
+	}
+	q.drawFunctionPatterns(version, v.alignment)
+	for y := range modules {
+		copy(q.modules[y], modules[y])
+	}
+	q.applyMask(mask)
+
+	total := v.dataCodewords() + v.ecPerBlock*len(v.blocks)
+	codewords := make([]byte, total)
+	i := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = size - 1 - vert
+				}
+				if !q.isFunction[y][x] && i < total*8 {
+					if q.modules[y][x] {
+						codewords[i>>3] |= 1 << (7 - i&7)
+					}
+					i++
+				}
+			}
+		}
+	}
+
+	// Deinterleave and check error correction of each block
+	blocks := make([][]byte, len(v.blocks))
+	k := 0
+	for n := 0; n < v.blocks[len(v.blocks)-1]; n++ {
+		for b := range v.blocks {
+			if n < v.blocks[b] {
+				blocks[b] = append(blocks[b], codewords[k])
+				k++
+			}
+		}
+	}
+	divisor := reedSolomonDivisor(v.ecPerBlock)
+	var data []byte
+	for b := range v.blocks {
+		var ec []byte
+		for n := 0; n < v.ecPerBlock; n++ {
+			ec = append(ec, codewords[k+n*len(v.blocks)+b])
+		}
+		if string(reedSolomonRemainder(blocks[b], divisor)) != string(ec) {
+			return nil, ErrQRTooLong
+		}
+		data = append(data, blocks[b]...)
+	}
+
+	// Byte mode header
+	if data[0]>>4 != 0x4 {
+		return nil, ErrQRTooLong
+	}
+	if version < 10 {
+		n := int(data[0]&0xF)<<4 | int(data[1]>>4)
+		result := make([]byte, n)
+		for j := 0; j < n; j++ {
+			result[j] = data[1+j]<<4 | data[2+j]>>4
+		}
+		return result, nil
+	}
+	n := int(data[0]&0xF)<<12 | int(data[1])<<4 | int(data[2]>>4)
+	result := make([]byte, n)
+	for j := 0; j < n; j++ {
+		result[j] = data[2+j]<<4 | data[3+j]>>4
+	}
+	return result, nil
+}
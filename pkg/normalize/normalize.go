@@ -0,0 +1,42 @@
+package normalize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// replacer map arabic letters to persian letters, persian and arabic digits to ascii digits
+// and zero width non joiner to space
+var replacer = strings.NewReplacer(
+	"ي", "ی",
+	"ى", "ی",
+	"ك", "ک",
+	"ة", "ه",
+	"أ", "ا",
+	"إ", "ا",
+	"۰", "0", "۱", "1", "۲", "2", "۳", "3", "۴", "4", "۵", "5", "۶", "6", "۷", "7", "۸", "8", "۹", "9",
+	"٠", "0", "١", "1", "٢", "2", "٣", "3", "٤", "4", "٥", "5", "٦", "6", "٧", "7", "٨", "8", "٩", "9",
+	"‌", " ",
+)
+
+// String return a canonical form of `s` so persian variants of a text are equal.
+// arabic letters and digits are replaced, tatweel and diacritics are removed
+// and whitespaces are collapsed to a single space
+func String(s string) string {
+	s = replacer.Replace(s)
+
+	s = strings.Map(func(r rune) rune {
+		// Tatweel and arabic diacritics (fathatan to sukun)
+		if r == 'ـ' || (r >= 'ً' && r <= 'ْ') {
+			return -1
+		}
+		return r
+	}, s)
+
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " ")
+}
+
+// Key return a case insensitive canonical form of `s`
+func Key(s string) string {
+	return strings.ToLower(String(s))
+}
@@ -0,0 +1,34 @@
+package normalize
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		s      string
+		expect string
+	}{
+		{"قرمز", "قرمز"},
+		{"كاشي", "کاشی"},
+		{"۱۰۵", "105"},
+		{"١٠٥", "105"},
+		{"فرش‌های  دستباف ", "فرش های دستباف"},
+		{"قــرمز", "قرمز"},
+		{"قِرمَز", "قرمز"},
+		{" \tnew  arrival\n", "new arrival"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		got := String(tt.s)
+		require.Equal(t, tt.expect, got)
+	}
+}
+
+func TestKey(t *testing.T) {
+	require.Equal(t, "new-arrival", Key(" New-Arrival"))
+	require.Equal(t, Key("پرفروش"), Key("پرفروش "))
+	require.Equal(t, Key("كلاسيك"), Key("کلاسیک"))
+}
@@ -0,0 +1,124 @@
+package sku
+
+import (
+	"errors"
+	"fmt"
+	"github.com/seed95/product-service/pkg/normalize"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Prefixes of ids in a sku
+const (
+	ProductPrefix   = "P"
+	DimensionPrefix = "D"
+	ThemePrefix     = "T"
+
+	separator = "-"
+)
+
+var (
+	ErrInvalidSKU = errors.New("invalid_sku")
+	ErrCheckDigit = errors.New("invalid_sku_check_digit")
+)
+
+// pattern match [<company>-]P<product>D<dimension>(T|C)<theme>[-<check digit>], C is legacy prefix of theme (color)
+var pattern = regexp.MustCompile(`^(?:(\d+)-)?P(\d+)D(\d+)[TC](\d+)(?:-(\d))?$`)
+
+type (
+	// SKU identify a carpet, a size (dimension) and a color (theme) of a product.
+	// CompanyId is optional
+	SKU struct {
+		CompanyId   uint
+		ProductId   uint
+		DimensionId uint
+		ThemeId     uint
+	}
+)
+
+// Encode return canonical form of `s`: P<product>D<dimension>T<theme>
+func Encode(s SKU) string {
+	return ProductPrefix + strconv.FormatUint(uint64(s.ProductId), 10) +
+		DimensionPrefix + strconv.FormatUint(uint64(s.DimensionId), 10) +
+		ThemePrefix + strconv.FormatUint(uint64(s.ThemeId), 10)
+}
+
+// EncodeWithCheck return canonical form of `s` with company prefix, if CompanyId isn't zero,
+// and a check digit: [<company>-]P<product>D<dimension>T<theme>-<check digit>
+func EncodeWithCheck(s SKU) string {
+	code := Encode(s)
+	if s.CompanyId != 0 {
+		code = strconv.FormatUint(uint64(s.CompanyId), 10) + separator + code
+	}
+	return code + separator + strconv.Itoa(CheckDigit(code))
+}
+
+// Decode parse canonical and legacy forms of a sku. letters are case insensitive,
+// persian digits are accepted, theme may have legacy C prefix, company prefix is optional
+// and a check digit, if present, should be valid
+func Decode(code string) (SKU, error) {
+	code = strings.ToUpper(strings.ReplaceAll(normalize.String(code), " ", ""))
+
+	match := pattern.FindStringSubmatch(code)
+	if match == nil {
+		return SKU{}, ErrInvalidSKU
+	}
+
+	ids := make([]uint, 4)
+	for i, m := range match[1:5] {
+		if m == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(m, 10, 0)
+		if err != nil {
+			return SKU{}, ErrInvalidSKU
+		}
+		ids[i] = uint(id)
+	}
+
+	s := SKU{CompanyId: ids[0], ProductId: ids[1], DimensionId: ids[2], ThemeId: ids[3]}
+	if s.ProductId == 0 || s.DimensionId == 0 || s.ThemeId == 0 || (match[1] != "" && s.CompanyId == 0) {
+		return SKU{}, ErrInvalidSKU
+	}
+
+	if match[5] != "" {
+		body := Encode(s)
+		if match[1] != "" {
+			body = match[1] + separator + body
+		}
+		if strconv.Itoa(CheckDigit(body)) != match[5] {
+			return SKU{}, ErrCheckDigit
+		}
+	}
+
+	return s, nil
+}
+
+// SQL return a sql expression that builds canonical form of sku from id columns
+func SQL(productColumn, dimensionColumn, themeColumn string) string {
+	return fmt.Sprintf("'%s' || %s::text || '%s' || %s::text || '%s' || %s::text",
+		ProductPrefix, productColumn, DimensionPrefix, dimensionColumn, ThemePrefix, themeColumn)
+}
+
+// CheckDigit return luhn check digit of digits of `code`
+func CheckDigit(code string) int {
+	sum := 0
+	double := true
+	for i := len(code) - 1; i >= 0; i-- {
+		c := code[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
@@ -0,0 +1,73 @@
+package sku
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	require.Equal(t, "P12D14T13", Encode(SKU{ProductId: 12, DimensionId: 14, ThemeId: 13}))
+	require.Equal(t, "P12D14T13", Encode(SKU{CompanyId: 1, ProductId: 12, DimensionId: 14, ThemeId: 13}))
+}
+
+func TestEncodeWithCheck(t *testing.T) {
+	s := SKU{CompanyId: 1, ProductId: 12, DimensionId: 14, ThemeId: 13}
+	code := EncodeWithCheck(s)
+	require.Regexp(t, `^1-P12D14T13-\d$`, code)
+
+	got, err := Decode(code)
+	require.Nil(t, err)
+	require.Equal(t, s, got)
+
+	s.CompanyId = 0
+	got, err = Decode(EncodeWithCheck(s))
+	require.Nil(t, err)
+	require.Equal(t, s, got)
+}
+
+func TestDecode(t *testing.T) {
+	s := SKU{ProductId: 12, DimensionId: 14, ThemeId: 13}
+
+	tests := []struct {
+		code   string
+		expect SKU
+		err    error
+	}{
+		{"P12D14T13", s, nil},
+		{"P12D14C13", s, nil},
+		{"p12d14t13", s, nil},
+		{" P12D14T13 ", s, nil},
+		{"P۱۲D۱۴T۱۳", s, nil},
+		{"7-P12D14T13", SKU{CompanyId: 7, ProductId: 12, DimensionId: 14, ThemeId: 13}, nil},
+		{"P12D14", SKU{}, ErrInvalidSKU},
+		{"P0D14T13", SKU{}, ErrInvalidSKU},
+		{"0-P12D14T13", SKU{}, ErrInvalidSKU},
+		{"P12D14X13", SKU{}, ErrInvalidSKU},
+		{"", SKU{}, ErrInvalidSKU},
+		{"P99999999999999999999D1T1", SKU{}, ErrInvalidSKU},
+	}
+
+	for _, tt := range tests {
+		got, err := Decode(tt.code)
+		require.Equal(t, tt.err, err, tt.code)
+		require.Equal(t, tt.expect, got, tt.code)
+	}
+}
+
+func TestDecode_CheckDigit(t *testing.T) {
+	code := EncodeWithCheck(SKU{CompanyId: 1, ProductId: 12, DimensionId: 14, ThemeId: 13})
+	wrong := code[:len(code)-1] + string('0'+byte((int(code[len(code)-1]-'0')+1)%10))
+
+	_, err := Decode(wrong)
+	require.Equal(t, ErrCheckDigit, err)
+}
+
+func TestCheckDigit(t *testing.T) {
+	// Luhn check digit of 7992739871 is 3
+	require.Equal(t, 3, CheckDigit("7992739871"))
+	require.Equal(t, 3, CheckDigit("P799D2739T871"))
+}
+
+func TestSQL(t *testing.T) {
+	require.Equal(t, "'P' || p.id::text || 'D' || d.id::text || 'T' || t.id::text", SQL("p.id", "d.id", "t.id"))
+}
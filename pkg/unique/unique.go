@@ -42,3 +42,14 @@ func StringsAreUnique(s []string) bool {
 	}
 	return true
 }
+
+func UintsAreUnique(s []uint) bool {
+	keys := make(map[uint]bool)
+	for _, entry := range s {
+		if keys[entry] {
+			return false
+		}
+		keys[entry] = true
+	}
+	return true
+}
@@ -66,3 +66,21 @@ func TestStringsAreUnique(t *testing.T) {
 	}
 
 }
+
+func TestUintsAreUnique(t *testing.T) {
+	tests := []struct {
+		slice  []uint
+		expect bool
+	}{
+		{[]uint{1, 2, 4, 7}, true},
+		{[]uint{1, 2, 4, 1}, false},
+		{[]uint{1}, true},
+		{[]uint{}, true},
+		{nil, true},
+	}
+
+	for _, tt := range tests {
+		got := UintsAreUnique(tt.slice)
+		require.Equal(t, tt.expect, got)
+	}
+}